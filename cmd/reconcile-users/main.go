@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/flapenna/go-ddd-crud/config"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	kafkaC "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func init() {
+	log.SetFormatter(&log.JSONFormatter{})
+	log.SetOutput(os.Stdout)
+	log.SetLevel(log.InfoLevel)
+}
+
+// Reconciles the users published to Kafka with the users collection, and optionally repairs the drifts
+func main() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	cfg := config.NewConfig()
+	// The snapshot topic holds a single message per user, it is the cheapest to read
+	topic := cfg.ReconcileUserTopic
+	if topic == "" {
+		topic = cfg.KafkaUserSnapshotTopic
+	}
+	if topic == "" {
+		topic = cfg.KafkaUserEventTopic
+	}
+
+	// Connect to MongoDB
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDBUri))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := mongoClient.Disconnect(context.Background()); err != nil {
+			log.Warnf("failed to disconnect from MongoDB: %v", err)
+		}
+	}()
+	if err := mongoClient.Ping(ctx, readpref.Primary()); err != nil {
+		log.Fatal(err)
+	}
+	userRepo := mongodb.NewUserRepository(mongoClient.Database(cfg.MongoDBDatabase).Collection(cfg.MongoDBUserCollection))
+
+	// Kafka
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.KafkaServer,
+		// The partitions are assigned, the group only names the client
+		"group.id":           "go-ddd-crud_user-reconciler",
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create consumer due to %v", err)
+	}
+	defer consumer.Close()
+
+	// Users changed shortly before the topic is read may not be published yet
+	readAt := time.Now().UTC().Add(-cfg.ReconcilePublishLag)
+	published, err := kafkaC.NewUserTopicReader(consumer, cfg.KafkaAdminTimeout).ReadPublishedUsers(ctx, topic)
+	if err != nil {
+		log.Fatalf("Failed to read topic %s: %v", topic, err)
+	}
+
+	var producer domain.UserProducer
+	if cfg.ReconcileRepair {
+		broker, err := kafka.NewProducer(&kafka.ConfigMap{
+			"bootstrap.servers":   cfg.KafkaServer,
+			"enable.idempotence":  true,
+			"retries":             cfg.KafkaRetries,
+			"delivery.timeout.ms": int(cfg.KafkaDeliveryTimeout.Milliseconds()),
+		})
+		if err != nil {
+			log.Fatalf("Failed to create producer due to %v", err)
+		}
+		defer broker.Close()
+		producer = newRepairProducer(cfg, broker, topic)
+	}
+
+	report, err := domain.NewUserReconciler(userRepo, producer).Reconcile(ctx, published, readAt, cfg.ReconcileRepair)
+	if err != nil {
+		log.Fatalf("Failed to reconcile topic %s: %v", topic, err)
+	}
+
+	for _, drift := range report.Drifts {
+		log.WithFields(log.Fields{
+			"reconciliation_id": report.Id,
+			"user_id":           drift.UserId,
+			"drift":             drift.Kind,
+			"stored_version":    drift.StoredVersion,
+			"published_version": drift.PublishedVersion,
+			"repaired":          drift.Repaired,
+		}).Warn("user drift")
+	}
+	log.WithFields(log.Fields{
+		"reconciliation_id": report.Id,
+		"topic":             topic,
+		"stored_users":      report.StoredUsers,
+		"published_users":   report.PublishedUsers,
+		"in_flight_users":   report.InFlightUsers,
+		"missing_users":     report.Count(domain.UserDriftMissing),
+		"stale_users":       report.Count(domain.UserDriftStale),
+		"orphaned_users":    report.Count(domain.UserDriftOrphaned),
+		"repaired_users":    report.Repaired(),
+	}).Info("user reconciliation completed")
+
+	if cfg.ReconcileMetricsFile != "" {
+		if err := writeMetrics(cfg.ReconcileMetricsFile, topic, report); err != nil {
+			log.Fatalf("Failed to write metrics: %v", err)
+		}
+	}
+}
+
+// newRepairProducer publishes the corrective events to the reconciled topic only, as the
+// server would: with its snapshot format, or with its route projection and encoding
+func newRepairProducer(cfg *config.Config, broker *kafka.Producer, topic string) domain.UserProducer {
+	if topic == cfg.KafkaUserSnapshotTopic {
+		return kafkaC.NewUserSnapshotProducer(broker, topic)
+	}
+
+	routes, err := kafkaC.ParseUserEventRoutes(cfg.KafkaUserEventRoutes, []byte(cfg.ProjectionHashKey))
+	if err != nil {
+		log.Fatalf("invalid user event routes: %v", err)
+	}
+	route := kafkaC.UserEventRoute{Topic: topic}
+	for _, r := range routes {
+		if r.Topic == topic {
+			route = r
+		}
+	}
+	route.Encoder, err = kafkaC.NewUserEventEncoder(cfg.KafkaEventEncoding, cfg.SchemaRegistryUrl, topic)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var opts kafkaC.UserProducerOptions
+	if cfg.KafkaMessageFormat == kafkaC.MessageFormatCloudEvents {
+		opts.CloudEventsSource = cfg.CloudEventsSource
+	}
+	return kafkaC.NewRoutedUserProducer(broker, []kafkaC.UserEventRoute{route}, opts)
+}
+
+// writeMetrics writes the report in the Prometheus text format, e.g. for the textfile collector
+// of the node exporter
+func writeMetrics(path string, topic string, report *domain.UserReconciliationReport) error {
+	registry := prometheus.NewRegistry()
+	labels := prometheus.Labels{"topic": topic}
+
+	users := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "user_reconciliation_users",
+		Help:        "Number of users by source in the last reconciliation.",
+		ConstLabels: labels,
+	}, []string{"source"})
+	users.WithLabelValues("stored").Set(float64(report.StoredUsers))
+	users.WithLabelValues("published").Set(float64(report.PublishedUsers))
+	users.WithLabelValues("in_flight").Set(float64(report.InFlightUsers))
+
+	drifts := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "user_reconciliation_drifts",
+		Help:        "Number of drifted users by kind in the last reconciliation.",
+		ConstLabels: labels,
+	}, []string{"kind"})
+	for _, kind := range []domain.UserDriftKind{domain.UserDriftMissing, domain.UserDriftStale, domain.UserDriftOrphaned} {
+		drifts.WithLabelValues(string(kind)).Set(float64(report.Count(kind)))
+	}
+
+	repaired := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "user_reconciliation_repaired",
+		Help:        "Number of drifted users repaired in the last reconciliation.",
+		ConstLabels: labels,
+	})
+	repaired.Set(float64(report.Repaired()))
+
+	completed := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "user_reconciliation_completed_timestamp_seconds",
+		Help:        "Time the last reconciliation completed.",
+		ConstLabels: labels,
+	})
+	completed.Set(float64(report.FinishedAt.Unix()))
+
+	registry.MustRegister(users, drifts, repaired, completed)
+	return prometheus.WriteToTextfile(path, registry)
+}
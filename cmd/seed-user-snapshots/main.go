@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/flapenna/go-ddd-crud/config"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	kafkaC "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/joho/godotenv"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func init() {
+	log.SetFormatter(&log.JSONFormatter{})
+	log.SetOutput(os.Stdout)
+	log.SetLevel(log.InfoLevel)
+}
+
+// Seeds the compacted snapshot topic with the current state of every user
+func main() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	cfg := config.NewConfig()
+	if cfg.KafkaUserSnapshotTopic == "" {
+		log.Fatal("KAFKA_USER_SNAPSHOT_TOPIC is not set")
+	}
+
+	// Connect to MongoDB
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDBUri))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := mongoClient.Disconnect(context.Background()); err != nil {
+			log.Warnf("failed to disconnect from MongoDB: %v", err)
+		}
+	}()
+	if err := mongoClient.Ping(ctx, readpref.Primary()); err != nil {
+		log.Fatal(err)
+	}
+	userRepo := mongodb.NewUserRepository(mongoClient.Database(cfg.MongoDBDatabase).Collection(cfg.MongoDBUserCollection))
+
+	// Kafka
+	broker, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":   cfg.KafkaServer,
+		"enable.idempotence":  true,
+		"retries":             cfg.KafkaRetries,
+		"delivery.timeout.ms": int(cfg.KafkaDeliveryTimeout.Milliseconds()),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create producer due to %v", err)
+	}
+	defer broker.Close()
+
+	if cfg.KafkaProvisionTopics {
+		snapshotTopic := kafkaC.TopicSpec{
+			Name:              cfg.KafkaUserSnapshotTopic,
+			Partitions:        cfg.KafkaTopicPartitions,
+			ReplicationFactor: cfg.KafkaTopicReplicationFactor,
+			CleanupPolicy:     kafkaC.CleanupPolicyCompact,
+		}
+		if err := kafkaC.ProvisionTopics(ctx, broker, cfg.KafkaAdminTimeout, []kafkaC.TopicSpec{snapshotTopic}); err != nil {
+			log.Fatalf("Failed to provision kafka topics: %v", err)
+		}
+	}
+
+	// Every snapshot is acknowledged by the broker before the next user is read
+	snapshotProducer := kafkaC.NewUserSnapshotProducer(broker, cfg.KafkaUserSnapshotTopic)
+	seeded := 0
+	err = userRepo.ScanUsers(ctx, &domain.ScanUsersQuery{}, func(user *domain.User) error {
+		if err := snapshotProducer.SendMessage(&domain.UserEvent{
+			UserId:        user.ID,
+			AfterChange:   user,
+			OperationType: domain.OPERATION_UPDATE,
+			Sequence:      user.Version,
+		}); err != nil {
+			return err
+		}
+		seeded++
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to seed user snapshots after %d users: %v", seeded, err)
+	}
+
+	log.Infof("Seeded %d user snapshots to %s", seeded, cfg.KafkaUserSnapshotTopic)
+}
@@ -2,17 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/flapenna/go-ddd-crud/config"
 	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/auth"
 	kafkaC "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
 	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/spill"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/webhook"
 	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	httpServer "github.com/flapenna/go-ddd-crud/internal/interfaces/http"
+	kafkaI "github.com/flapenna/go-ddd-crud/internal/interfaces/kafka"
 	pbHealth "github.com/flapenna/go-ddd-crud/pkg/pb/health/v1"
 	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -26,9 +34,22 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 )
 
+const (
+	// webhookStreamName is the checkpoint name of the change stream delivering the webhooks
+	webhookStreamName = "webhooks"
+	// userWatcherLeaseName is the lease of the instance publishing the user events
+	userWatcherLeaseName = "user-watcher"
+	// metricsPath serves the Prometheus metrics on the gateway
+	metricsPath = "/metrics"
+	// namespaceExistsCode is the MongoDB error of a collection created twice
+	namespaceExistsCode = 48
+)
+
 func init() {
 	// Log as JSON instead of the default ASCII formatter.
 	log.SetFormatter(&log.JSONFormatter{})
@@ -44,7 +65,8 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	cfg := config.NewConfig()
 
 	// Connect to MongoDB
@@ -63,9 +85,12 @@ func main() {
 	// MongoDb
 	mongoDb := mongoClient.Database(cfg.MongoDBDatabase)
 
-	// Drop the collection on every startup
-	if err := mongoDb.Collection(cfg.MongoDBUserCollection, options.Collection().SetReadPreference(readpref.Secondary())).Drop(ctx); err != nil {
-		log.Fatal(err)
+	// Start from an empty collection, for local development only
+	if cfg.MongoDBDropUsersOnStartup {
+		log.Warnf("Dropping the %s collection", cfg.MongoDBUserCollection)
+		if err := mongoDb.Collection(cfg.MongoDBUserCollection).Drop(ctx); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	// Create the collection with options (needed to return the pre-changes document using change stream)
@@ -73,40 +98,231 @@ func main() {
 		SetChangeStreamPreAndPostImages(bson.M{"enabled": true})
 
 	err = mongoDb.CreateCollection(ctx, cfg.MongoDBUserCollection, collOpts)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == namespaceExistsCode {
+		// The collection is kept across restarts, the images are enabled in case it was created without
+		err = mongoDb.RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: cfg.MongoDBUserCollection},
+			{Key: "changeStreamPreAndPostImages", Value: bson.M{"enabled": true}},
+		}).Err()
+	}
 	if err != nil {
 		log.Fatalf("failed to set up an additional collection options: %v", err)
 	}
 
-	// Create new User Repository
+	// The change stream checkpoints are kept across restarts, to process the changes made while the service was down
+	historyLostPolicy := mongodb.HistoryLostPolicy(cfg.ChangeStreamHistoryLostPolicy)
+	if historyLostPolicy != mongodb.HistoryLostFail && historyLostPolicy != mongodb.HistoryLostResync {
+		log.Fatalf("unknown change stream history lost policy %q", cfg.ChangeStreamHistoryLostPolicy)
+	}
+	checkpoints := mongodb.NewResumeTokenRepository(mongoDb.Collection(cfg.MongoDBCheckpointCollection))
+	retryPolicy := mongodb.RetryPolicy{
+		InitialBackoff: cfg.WatcherRetryInitialBackoff,
+		MaxBackoff:     cfg.WatcherRetryMaxBackoff,
+		MaxAttempts:    cfg.WatcherRetryMaxAttempts,
+	}
+
+	// The watcher emits as many events ahead of their acknowledgements as the event pipeline holds
+	pipelineOpts := domain.UserEventPipelineOptions{
+		Workers:   cfg.UserEventWorkers,
+		QueueSize: cfg.UserEventQueueSize,
+	}
+	maxInFlight := cfg.UserEventWorkers * cfg.UserEventQueueSize
+
+	// Every instance, leader or not, streams the user events to the watchers from a feed of its own,
+	// emitting the events with the ids they are published with. The feed retries forever.
+	feedRetryPolicy := retryPolicy
+	feedRetryPolicy.MaxAttempts = 0
+
+	// Create new User Repository, the user watcher and the user feed, depending on the event publishing mode
 	userCollection := mongoDb.Collection(cfg.MongoDBUserCollection)
-	userRepo := mongodb.NewUserRepository(userCollection)
+	// The deleting actors are recorded for the change stream watchers to attribute the delete events
+	deletionCollection := mongoDb.Collection(cfg.MongoDBUserDeletionCollection)
+	var userRepo *mongodb.UserRepository
+	var userWatcher, userFeed domain.UserWatcher
+	switch cfg.EventPublishingMode {
+	case config.EventPublishingOutbox:
+		outboxCollection := mongoDb.Collection(cfg.MongoDBOutboxCollection)
+		userRepo = mongodb.NewOutboxUserRepository(userCollection, outboxCollection, mongoDb.Collection(cfg.MongoDBSequenceCollection)).
+			WithDeletions(deletionCollection)
+		userWatcher = mongodb.NewOutboxRelay(outboxCollection, cfg.OutboxPollInterval).WithMaxInFlight(maxInFlight)
+		userFeed = mongodb.NewOutboxFeed(outboxCollection, feedRetryPolicy)
+	case config.EventPublishingWatcher:
+		userRepo = mongodb.NewUserRepository(userCollection).WithDeletions(deletionCollection)
+		userFeed = mongodb.NewCheckpointedChangeStreamWatcher(userCollection, nil, mongodb.HistoryLostFail, feedRetryPolicy).
+			WithDeletions(deletionCollection)
+		changeStreamWatcher := mongodb.NewCheckpointedChangeStreamWatcher(userCollection, checkpoints, historyLostPolicy, retryPolicy).
+			WithDeletions(deletionCollection).WithMaxInFlight(maxInFlight)
+		// Without the watcher no event is published anymore, let the service be restarted
+		go func() {
+			<-changeStreamWatcher.Failed()
+			log.Fatalf("User watcher failed: %s", changeStreamWatcher.WatcherStatus().LastError)
+		}()
+		userWatcher = changeStreamWatcher
+	default:
+		log.Fatalf("unknown event publishing mode %q", cfg.EventPublishingMode)
+	}
+	log.Infof("Publishing user events in %s mode", cfg.EventPublishingMode)
+
+	// Create new User Audit Repository, the audit collection is append-only and kept across restarts
+	auditCollection := mongoDb.Collection(cfg.MongoDBAuditCollection)
+	userAuditRepo := mongodb.NewUserAuditRepository(auditCollection)
+
+	// Create new User Version Repository, the version history is kept across restarts
+	userVersionRepo := mongodb.NewUserVersionRepository(mongoDb.Collection(cfg.MongoDBVersionCollection))
+
+	// Create new User Dead Letter Repository, kept across restarts until redriven or discarded
+	userDeadLetterRepo := mongodb.NewUserDeadLetterRepository(mongoDb.Collection(cfg.MongoDBDeadLetterCollection))
+
+	// Create new User Replay Repository, keeping the replay checkpoints across restarts
+	userReplayRepo := mongodb.NewUserReplayRepository(mongoDb.Collection(cfg.MongoDBReplayCollection))
+
+	// Create the webhook repositories, the subscriptions and their delivery log are kept across restarts
+	webhookSubscriptionRepo := mongodb.NewWebhookSubscriptionRepository(mongoDb.Collection(cfg.MongoDBWebhookCollection))
+	webhookDeliveryRepo := mongodb.NewWebhookDeliveryRepository(mongoDb.Collection(cfg.MongoDBWebhookLogCollection))
+	webhookRetryRepo := mongodb.NewWebhookRetryRepository(mongoDb.Collection(cfg.MongoDBWebhookRetryCollection))
 
 	// Kafka
-	broker, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": cfg.KafkaServer})
+	broker, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": cfg.KafkaServer,
+		// Idempotence implies acks=all and keeps the retried messages in order, without duplicates
+		"enable.idempotence":  true,
+		"retries":             cfg.KafkaRetries,
+		"delivery.timeout.ms": int(cfg.KafkaDeliveryTimeout.Milliseconds()),
+	})
 	if err != nil {
 		log.Fatalf("Failed to create producer due to %v", err)
 	}
-	defer broker.Close()
 
-	userProducer := kafkaC.NewUserProducer(broker, "go-ddd-crud_user-event")
+	// Every event is sent to each route, a topic with an optional projection redacting the users
+	routes, err := kafkaC.ParseUserEventRoutes(cfg.KafkaUserEventRoutes, []byte(cfg.ProjectionHashKey))
+	if err != nil {
+		log.Fatalf("invalid user event routes: %v", err)
+	}
+	if len(routes) == 0 {
+		routes = []kafkaC.UserEventRoute{{Topic: cfg.KafkaUserEventTopic}}
+	}
+	topics := make([]kafkaC.TopicSpec, 0, len(routes))
+	for i := range routes {
+		routes[i].Encoder = newUserEventEncoder(cfg, routes[i].Topic)
+		topics = append(topics, kafkaC.TopicSpec{
+			Name:              routes[i].Topic,
+			Partitions:        cfg.KafkaTopicPartitions,
+			ReplicationFactor: cfg.KafkaTopicReplicationFactor,
+			CleanupPolicy:     cfg.KafkaTopicCleanupPolicy,
+		})
+	}
+	if cfg.KafkaUserSnapshotTopic != "" {
+		// The snapshot topic keeps the latest state of every user, for consumers to bootstrap from
+		routes = append(routes, kafkaC.UserEventRoute{Topic: cfg.KafkaUserSnapshotTopic, Snapshot: true})
+		topics = append(topics, userSnapshotTopicSpec(cfg))
+	}
+	if cfg.KafkaUserCommandsEnabled {
+		for _, topic := range []string{cfg.KafkaUserCommandTopic, cfg.KafkaUserCommandResultTopic, cfg.KafkaUserCommandDLQTopic} {
+			topics = append(topics, kafkaC.TopicSpec{
+				Name:              topic,
+				Partitions:        cfg.KafkaTopicPartitions,
+				ReplicationFactor: cfg.KafkaTopicReplicationFactor,
+				CleanupPolicy:     kafkaC.CleanupPolicyDelete,
+			})
+		}
+	}
+	if cfg.KafkaProvisionTopics {
+		// Fail fast rather than publishing to missing or misconfigured topics
+		if err := kafkaC.ProvisionTopics(ctx, broker, cfg.KafkaAdminTimeout, topics); err != nil {
+			log.Fatalf("Failed to provision kafka topics: %v", err)
+		}
+	}
+	var producerOpts kafkaC.UserProducerOptions
+	switch cfg.KafkaMessageFormat {
+	case kafkaC.MessageFormatCloudEvents:
+		producerOpts.CloudEventsSource = cfg.CloudEventsSource
+	case kafkaC.MessageFormatPlain:
+	default:
+		log.Fatalf("unknown kafka message format %q", cfg.KafkaMessageFormat)
+	}
+	userProducer := kafkaC.NewRoutedUserProducer(broker, routes, producerOpts)
 
-	// Create new user watcher
-	userWatcher := mongodb.NewChangeStreamWatcher(userCollection)
+	// Spill the user events to disk while Kafka is unavailable, rather than dead-lettering them
+	var spillProducer *spill.UserSpillProducer
+	if cfg.SpillEnabled {
+		spillProducer, err = spill.NewUserSpillProducer(userProducer, spill.Options{
+			Dir:            cfg.SpillDir,
+			MaxBytes:       int64(cfg.SpillMaxBytes),
+			SegmentBytes:   int64(cfg.SpillSegmentBytes),
+			OverflowPolicy: spill.OverflowPolicy(cfg.SpillOverflowPolicy),
+			RetryInterval:  cfg.SpillRetryInterval,
+			Spillable:      kafkaC.IsBrokerUnavailable,
+			DeadLetters:    userDeadLetterRepo,
+		})
+		if err != nil {
+			log.Fatalf("Failed to open the user event spill queue: %v", err)
+		}
+		userProducer = spillProducer
+	}
 
 	// Create user service
-	userService := domain.NewUserService(userRepo, userProducer, userWatcher)
+	userService := domain.NewUserServiceWithOptions(userRepo, userAuditRepo, userVersionRepo, userDeadLetterRepo, userReplayRepo,
+		userProducer, userWatcher, domain.UserServiceOptions{
+			SuppressTimestampOnlyEvents: cfg.SuppressTimestampOnlyEvents,
+			WatchHistorySize:            cfg.WatchHistorySize,
+			WatchBufferSize:             cfg.WatchBufferSize,
+			Pipeline:                    pipelineOpts,
+			InstanceId:                  cfg.InstanceId,
+			ReplayPollInterval:          cfg.UserReplayPollInterval,
+		})
+
+	// Create webhook service, delivering the changes of its own checkpointed change stream
+	webhookWatcher := mongodb.NewCheckpointedChangeStreamWatcher(userCollection, checkpoints, historyLostPolicy, retryPolicy).
+		WithStreamName(webhookStreamName).WithDeletions(deletionCollection)
+	webhookService := domain.NewWebhookService(webhookSubscriptionRepo, webhookDeliveryRepo, webhookRetryRepo,
+		webhook.NewHTTPSender(webhook.NewClient(cfg.WebhookTimeout)), webhookWatcher, domain.WebhookServiceOptions{
+			MaxAttempts:          cfg.WebhookMaxAttempts,
+			InitialBackoff:       cfg.WebhookRetryInitialBackoff,
+			MaxBackoff:           cfg.WebhookRetryMaxBackoff,
+			DisableAfterFailures: int32(cfg.WebhookDisableAfterFailures),
+			RetryPollInterval:    cfg.WebhookRetryPollInterval,
+		})
 
 	// Set up gRPC server
 	userServiceServer := grpcServer.NewUserServiceServer(userService)
-	healthServiceServer := grpcServer.NewHealthServiceServer()
+	userWatchServiceServer := grpcServer.NewUserWatchServiceServer(userService)
+	webhookServiceServer := grpcServer.NewWebhookServiceServer(webhookService)
+	// Only the elected instance publishes the user events, the others stay on standby
+	var elector *domain.LeaderElector
+	var leaderStatus domain.LeaderStatusReporter
+	if cfg.LeaderElectionEnabled {
+		elector = domain.NewLeaderElector(mongodb.NewLeaderLeaseRepository(mongoDb.Collection(cfg.MongoDBLeaseCollection)),
+			userWatcherLeaseName, cfg.InstanceId, domain.LeaderElectionOptions{
+				LeaseDuration: cfg.LeaderLeaseDuration,
+				RenewInterval: cfg.LeaderRenewInterval,
+			})
+		leaderStatus = elector
+	}
 
-	grpcServer := grpc.NewServer()
+	watcherStatus, _ := userWatcher.(domain.UserWatcherStatusReporter)
+	healthServiceServer := grpcServer.NewHealthServiceServer(watcherStatus, leaderStatus)
 
-	pb.RegisterUserServiceServer(grpcServer, userServiceServer)
-	pbHealth.RegisterHealthServiceServer(grpcServer, healthServiceServer)
+	// Authenticate the callers with the bearer tokens signed with the shared secret
+	var actorVerifier domain.ActorTokenVerifier
+	switch {
+	case cfg.AuthJWTSecret != "":
+		actorVerifier = auth.NewJWTVerifier([]byte(cfg.AuthJWTSecret))
+	case cfg.AuthAllowAnonymous:
+		log.Warn("AUTH_JWT_SECRET is not set and AUTH_ALLOW_ANONYMOUS is enabled, every request is anonymous")
+	default:
+		log.Fatal("AUTH_JWT_SECRET is not set, set it or enable AUTH_ALLOW_ANONYMOUS to run without authentication")
+	}
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcServer.ActorUnaryInterceptor(actorVerifier)),
+		grpc.StreamInterceptor(grpcServer.ActorStreamInterceptor(actorVerifier)))
+
+	pb.RegisterUserServiceServer(server, userServiceServer)
+	pb.RegisterUserWatchServiceServer(server, userWatchServiceServer)
+	pb.RegisterWebhookServiceServer(server, webhookServiceServer)
+	pbHealth.RegisterHealthServiceServer(server, healthServiceServer)
 
 	// Enable reflection for the gRPC server (useful for debugging and testing)
-	reflection.Register(grpcServer)
+	reflection.Register(server)
 
 	// Start gRPC server
 	go func() {
@@ -115,7 +331,7 @@ func main() {
 		if err != nil {
 			log.Fatalf("Failed to listen: %v", err)
 		}
-		if err := grpcServer.Serve(lis); err != nil {
+		if err := server.Serve(lis); err != nil {
 			log.Fatalf("Failed to serve: %v", err)
 		}
 	}()
@@ -130,12 +346,23 @@ func main() {
 		log.Fatalln("Failed to dial server:", err)
 	}
 
-	gwMux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-		MarshalOptions: protojson.MarshalOptions{
-			UseProtoNames:   true,
-			EmitUnpopulated: true,
-		},
-	}))
+	gwMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+		}),
+		// Forward the request id and trace context headers to the gRPC server, the Authorization
+		// header carrying the caller identity is forwarded by default
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			switch strings.ToLower(key) {
+			case grpcServer.RequestIdMetadataKey, grpcServer.TraceParentMetadataKey:
+				return strings.ToLower(key), true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+	)
 
 	// Register Health
 	err = pbHealth.RegisterHealthServiceHandler(context.Background(), gwMux, conn)
@@ -149,6 +376,47 @@ func main() {
 		log.Fatalln("Failed to register User handler to gateway:", err)
 	}
 
+	// Register Webhook
+	err = pb.RegisterWebhookServiceHandler(context.Background(), gwMux, conn)
+	if err != nil {
+		log.Fatalln("Failed to register Webhook handler to gateway:", err)
+	}
+
+	// Register the user event feed, streaming the WatchUsers RPC to the browsers
+	userEventsHandler := httpServer.NewUserEventsHandler(pb.NewUserWatchServiceClient(conn), cfg.UserEventsHeartbeatInterval)
+	err = gwMux.HandlePath(http.MethodGet, httpServer.UserEventsPath, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		userEventsHandler.ServeHTTP(w, r)
+	})
+	if err != nil {
+		log.Fatalln("Failed to register user events handler to gateway:", err)
+	}
+
+	// Register the downloadable data export of a user, bundling the ExportMyData RPC as a zip
+	userExportHandler := httpServer.NewUserExportHandler(pb.NewUserServiceClient(conn))
+	err = gwMux.HandlePath(http.MethodGet, httpServer.UserExportPath, userExportHandler.ServeExport)
+	if err != nil {
+		log.Fatalln("Failed to register user export handler to gateway:", err)
+	}
+
+	// Register the Prometheus metrics
+	registry := prometheus.NewRegistry()
+	if elector != nil {
+		registerLeaderMetrics(registry, elector, cfg.InstanceId)
+	}
+	if pipeline, ok := userService.(domain.UserEventPipelineStatsReporter); ok {
+		registerPipelineMetrics(registry, pipeline)
+	}
+	if spillProducer != nil {
+		registerSpillMetrics(registry, spillProducer)
+	}
+	metricsHandler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	err = gwMux.HandlePath(http.MethodGet, metricsPath, func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		metricsHandler.ServeHTTP(w, r)
+	})
+	if err != nil {
+		log.Fatalln("Failed to register metrics handler to gateway:", err)
+	}
+
 	gwServer := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.HttpPort),
 		Handler: gwMux,
@@ -162,8 +430,104 @@ func main() {
 		}
 	}()
 
-	// Start user watcher
-	userService.StartWatchingUsers(ctx)
+	// Start user watcher, the user replays, and delivering the user events to the webhooks. leading is done once the
+	// events in flight when ctx is done are published and delivered.
+	if cfg.WebhooksEnabled {
+		go func() {
+			<-webhookWatcher.Failed()
+			log.Fatalf("Webhook watcher failed: %s", webhookWatcher.WatcherStatus().LastError)
+		}()
+	}
+	broadcastDone := userService.StartBroadcastingUsers(ctx, userFeed)
+	var leading sync.WaitGroup
+	lead := func(ctx context.Context) {
+		// The spilled events are drained by the leader only, and those of a former term are
+		// dead-lettered rather than published after the events of the leaders since
+		if spillProducer != nil {
+			draining := spillProducer.Start(ctx)
+			leading.Add(1)
+			go func() {
+				defer leading.Done()
+				<-draining
+			}()
+		}
+		watching := userService.StartWatchingUsers(ctx)
+		leading.Add(1)
+		go func() {
+			defer leading.Done()
+			<-watching
+		}()
+		// The replays are published through the pipeline of the watcher, ordered with its events
+		replaying := userService.StartReplayingUsers(ctx)
+		leading.Add(1)
+		go func() {
+			defer leading.Done()
+			<-replaying
+		}()
+		if cfg.WebhooksEnabled {
+			delivering := webhookService.StartDeliveringWebhooks(ctx)
+			leading.Add(1)
+			go func() {
+				defer leading.Done()
+				<-delivering
+			}()
+		}
+	}
+	electionDone := make(chan struct{})
+	if elector != nil {
+		log.Infof("Campaigning for the %s lease as %s", userWatcherLeaseName, cfg.InstanceId)
+		go func() {
+			defer close(electionDone)
+			// A deposed leader goes back to standby once the events of its term are handled, then
+			// campaigns again. The watchers resume from the checkpoints of the last leader.
+			for {
+				err := elector.Run(ctx, lead)
+				leading.Wait()
+				if err == nil {
+					return
+				}
+				log.Warnf("User watcher stopped, campaigning again: %v", err)
+			}
+		}()
+	} else {
+		lead(ctx)
+		close(electionDone)
+	}
+
+	// Run the user commands of the command topic, as the equivalent RPCs
+	commandsDone := make(chan struct{})
+	if cfg.KafkaUserCommandsEnabled {
+		commandConsumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+			"bootstrap.servers": cfg.KafkaServer,
+			"group.id":          cfg.KafkaUserCommandGroupId,
+			// Offsets are committed once the result of the command is delivered
+			"enable.auto.commit": false,
+			"auto.offset.reset":  "earliest",
+		})
+		if err != nil {
+			log.Fatalf("Failed to create user command consumer due to %v", err)
+		}
+		processedCommandRepo := mongodb.NewProcessedUserCommandRepository(mongoDb.Collection(cfg.MongoDBCommandCollection),
+			cfg.KafkaUserCommandRetention)
+		userCommandConsumer := kafkaI.NewUserCommandConsumer(commandConsumer, broker,
+			kafkaI.NewUserCommandHandler(userServiceServer, actorVerifier, processedCommandRepo),
+			kafkaI.UserCommandConsumerOptions{
+				CommandTopic:    cfg.KafkaUserCommandTopic,
+				ResultTopic:     cfg.KafkaUserCommandResultTopic,
+				DeadLetterTopic: cfg.KafkaUserCommandDLQTopic,
+				RetryBackoff:    cfg.KafkaUserCommandRetryBackoff,
+			})
+		go func() {
+			defer close(commandsDone)
+			defer commandConsumer.Close()
+			log.Infof("Consuming user commands from %s", cfg.KafkaUserCommandTopic)
+			if err := userCommandConsumer.Run(ctx); err != nil {
+				log.Fatalf("User command consumer stopped: %v", err)
+			}
+		}()
+	} else {
+		close(commandsDone)
+	}
 
 	// Wait for interrupt signal to gracefully shut down the server
 	c := make(chan os.Signal, 1)
@@ -172,6 +536,113 @@ func main() {
 	defer signal.Stop(c)
 
 	log.Println("Shutting down gRPC server...")
-	grpcServer.GracefulStop()
+	userWatchServiceServer.Stop()
+	server.GracefulStop()
 	log.Println("gRPC server shut down")
+
+	// Stop watching and consuming commands, then wait for the in-flight user events to be delivered
+	cancel()
+	<-commandsDone
+	<-electionDone
+	leading.Wait()
+	<-broadcastDone
+	if spillProducer != nil {
+		if records := spillProducer.Stats().Records; records > 0 {
+			log.Warnf("%d spilled user events are left to drain after the restart", records)
+		}
+		spillProducer.Close()
+	}
+	if remaining := broker.Flush(int(cfg.KafkaFlushTimeout.Milliseconds())); remaining > 0 {
+		log.Warnf("%d user events were not delivered before shutdown", remaining)
+	}
+	broker.Close()
+	log.Println("Kafka producer closed")
+}
+
+// newUserEventEncoder creates the encoder of the events sent to topic
+func newUserEventEncoder(cfg *config.Config, topic string) kafkaC.UserEventEncoder {
+	encoder, err := kafkaC.NewUserEventEncoder(cfg.KafkaEventEncoding, cfg.SchemaRegistryUrl, topic)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return encoder
+}
+
+// userSnapshotTopicSpec is the spec of the compacted snapshot topic
+func userSnapshotTopicSpec(cfg *config.Config) kafkaC.TopicSpec {
+	return kafkaC.TopicSpec{
+		Name:              cfg.KafkaUserSnapshotTopic,
+		Partitions:        cfg.KafkaTopicPartitions,
+		ReplicationFactor: cfg.KafkaTopicReplicationFactor,
+		CleanupPolicy:     kafkaC.CleanupPolicyCompact,
+	}
+}
+
+// registerLeaderMetrics exposes the leadership of the instance, for the alerts on no or several leaders
+func registerLeaderMetrics(registry *prometheus.Registry, elector *domain.LeaderElector, instanceId string) {
+	labels := prometheus.Labels{"instance_id": instanceId}
+	leader := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "user_watcher_leader",
+		Help:        "Whether the instance is the elected user watcher leader.",
+		ConstLabels: labels,
+	}, func() float64 {
+		if elector.LeaderStatus().State == domain.LeaderStateLeader {
+			return 1
+		}
+		return 0
+	})
+	fencingToken := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "user_watcher_leader_fencing_token",
+		Help:        "Fencing token of the current leadership term of the instance, 0 when not leader.",
+		ConstLabels: labels,
+	}, func() float64 {
+		return float64(elector.LeaderStatus().FencingToken)
+	})
+	registry.MustRegister(leader, fencingToken)
+}
+
+// registerPipelineMetrics exposes the backlog of the user event pipeline and how far behind the changes it is
+func registerPipelineMetrics(registry *prometheus.Registry, pipeline domain.UserEventPipelineStatsReporter) {
+	queueDepth := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "user_event_pipeline_queue_depth",
+		Help: "Number of user events waiting for a pipeline worker.",
+	}, func() float64 {
+		return float64(pipeline.UserEventPipelineStats().QueueDepth)
+	})
+	inFlight := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "user_event_pipeline_in_flight",
+		Help: "Number of user events received from the watcher and not acknowledged yet.",
+	}, func() float64 {
+		return float64(pipeline.UserEventPipelineStats().InFlight)
+	})
+	lag := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "user_event_pipeline_lag_seconds",
+		Help: "Time between the change and the end of its processing, for the last processed user event.",
+	}, func() float64 {
+		return pipeline.UserEventPipelineStats().Lag.Seconds()
+	})
+	registry.MustRegister(queueDepth, inFlight, lag)
+}
+
+// registerSpillMetrics exposes the user events waiting on disk for Kafka to be available again
+func registerSpillMetrics(registry *prometheus.Registry, spillProducer *spill.UserSpillProducer) {
+	records := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "user_event_spill_records",
+		Help: "Number of spilled user events waiting to be drained to Kafka.",
+	}, func() float64 {
+		return float64(spillProducer.Stats().Records)
+	})
+	size := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "user_event_spill_bytes",
+		Help: "Size of the spill segment files.",
+	}, func() float64 {
+		return float64(spillProducer.Stats().Bytes)
+	})
+	dropped := prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "user_event_spill_dropped_total",
+		Help: "Number of spilled user events lost to the drop-oldest overflow policy or to corrupt segments.",
+	}, func() float64 {
+		return float64(spillProducer.Stats().Dropped)
+	})
+	registry.MustRegister(records, size, dropped)
 }
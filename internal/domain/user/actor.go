@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+const ActorRoleAdmin = "admin"
+
+// Actor is the identity performing a request against the user service
+type Actor struct {
+	ID   string
+	Role string
+}
+
+func (a *Actor) IsAdmin() bool {
+	return a != nil && a.Role == ActorRoleAdmin
+}
+
+// ActorTokenVerifier authenticates the bearer token of a request, and returns the identity it was
+// issued to. A token that cannot be trusted fails with ErrInvalidToken.
+type ActorTokenVerifier interface {
+	VerifyToken(token string) (*Actor, error)
+}
+
+// BearerToken returns the token of an authorization value of the Bearer scheme
+func BearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+type actorCtxKey struct{}
+
+func ContextWithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) *Actor {
+	actor, _ := ctx.Value(actorCtxKey{}).(*Actor)
+	return actor
+}
+
+type correlationIdCtxKey struct{}
+
+func ContextWithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationIdCtxKey{}, correlationId)
+}
+
+func CorrelationIdFromContext(ctx context.Context) string {
+	correlationId, _ := ctx.Value(correlationIdCtxKey{}).(string)
+	return correlationId
+}
+
+type traceParentCtxKey struct{}
+
+// ContextWithTraceParent stores the W3C trace context of the request
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceParentCtxKey{}, traceParent)
+}
+
+func TraceParentFromContext(ctx context.Context) string {
+	traceParent, _ := ctx.Value(traceParentCtxKey{}).(string)
+	return traceParent
+}
+
+// ActorIdFromContext returns the id of the actor performing the request, if any
+func ActorIdFromContext(ctx context.Context) string {
+	if actor := ActorFromContext(ctx); actor != nil {
+		return actor.ID
+	}
+	return ""
+}
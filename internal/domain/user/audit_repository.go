@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+)
+
+// UserAuditRepository is an append-only store of UserAuditEntry
+type UserAuditRepository interface {
+	AppendAuditEntry(ctx context.Context, entry *UserAuditEntry) error
+	ListAuditEntries(ctx context.Context, request *ListUserAuditEntriesQueryRequest) (*ListUserAuditEntriesQueryResponse, error)
+}
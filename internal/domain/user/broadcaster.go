@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"slices"
+	"sync"
+)
+
+const (
+	defaultWatchHistorySize = 1000
+	defaultWatchBufferSize  = 100
+)
+
+// WatchUsersRequest selects the user events streamed to a subscriber. Empty filters match every event.
+type WatchUsersRequest struct {
+	UserIds        []string
+	OperationTypes []OperationType
+	// Country matches the users whose country was or became Country
+	Country *string
+	// AfterEventId resumes the stream after the event with this id
+	AfterEventId string
+	// AfterSequence resumes the stream after the event at this position, when AfterEventId is empty
+	AfterSequence uint64
+	// Epoch is the epoch of the stream AfterSequence was received from
+	Epoch string
+}
+
+// WatchedUserEvent is a UserEvent at its position in the stream of a UserEventBroadcaster
+type WatchedUserEvent struct {
+	// Sequence increases by one with every broadcast event. It is local to the service
+	// instance and restarts with it.
+	Sequence uint64
+	// Epoch identifies the stream Sequence is a position of, it changes when the instance restarts
+	Epoch string
+	Event *UserEvent
+}
+
+// UserEventBroadcaster fans the user events out to many subscribers. The last events are kept
+// for the subscribers to resume from. A subscriber falling behind by more than its buffer is
+// disconnected with ErrWatchSubscriberTooSlow, so that it never delays the others.
+type UserEventBroadcaster struct {
+	mu sync.Mutex
+	// epoch is random, for a sequence to only be resumed from on the broadcaster that issued it
+	epoch       string
+	history     []*WatchedUserEvent
+	historySize int
+	// sequences are the positions of the events kept in history, by id
+	sequences   map[string]uint64
+	sequence    uint64
+	bufferSize  int
+	subscribers map[*UserEventSubscription]struct{}
+	closed      bool
+}
+
+// NewUserEventBroadcaster creates a broadcaster keeping the last historySize events, and
+// buffering up to bufferSize events for every subscriber
+func NewUserEventBroadcaster(historySize int, bufferSize int) *UserEventBroadcaster {
+	if historySize <= 0 {
+		historySize = defaultWatchHistorySize
+	}
+	if bufferSize <= 0 {
+		bufferSize = defaultWatchBufferSize
+	}
+	return &UserEventBroadcaster{
+		epoch:       uuid.NewString(),
+		historySize: historySize,
+		sequences:   make(map[string]uint64, historySize),
+		bufferSize:  bufferSize,
+		subscribers: make(map[*UserEventSubscription]struct{}),
+	}
+}
+
+// Broadcast sends the event to the matching subscribers. An event emitted again by the
+// watcher, still in history, is not broadcast twice.
+func (b *UserEventBroadcaster) Broadcast(event *UserEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	if _, ok := b.sequences[event.Id]; ok {
+		return
+	}
+
+	b.sequence++
+	watched := &WatchedUserEvent{Sequence: b.sequence, Epoch: b.epoch, Event: event}
+	b.history = append(b.history, watched)
+	b.sequences[event.Id] = watched.Sequence
+	if len(b.history) > b.historySize {
+		delete(b.sequences, b.history[0].Event.Id)
+		b.history = b.history[1:]
+	}
+
+	for subscriber := range b.subscribers {
+		if !subscriber.request.matches(event) {
+			continue
+		}
+		select {
+		case subscriber.events <- watched:
+		default:
+			b.drop(subscriber, ErrWatchSubscriberTooSlow)
+		}
+	}
+}
+
+// Subscribe streams the events matching the request, from its resume point when it has one
+// and from the next broadcast event otherwise
+func (b *UserEventBroadcaster) Subscribe(req *WatchUsersRequest) (*UserEventSubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrUserEventsStopped
+	}
+
+	after := b.sequence
+	switch {
+	case req.AfterEventId != "":
+		sequence, ok := b.sequences[req.AfterEventId]
+		if !ok {
+			return nil, ErrWatchResumeUnavailable
+		}
+		after = sequence
+	case req.AfterSequence > 0:
+		// A sequence of another instance, or of this one before it restarted, is no position in this stream
+		if req.Epoch != b.epoch {
+			return nil, ErrWatchResumeUnavailable
+		}
+		// The history holds the events following oldest
+		oldest := b.sequence - uint64(len(b.history))
+		if req.AfterSequence < oldest || req.AfterSequence > b.sequence {
+			return nil, ErrWatchResumeUnavailable
+		}
+		after = req.AfterSequence
+	}
+
+	subscription := &UserEventSubscription{
+		broadcaster: b,
+		request:     req,
+		events:      make(chan *WatchedUserEvent, b.bufferSize),
+	}
+	for _, watched := range b.history {
+		if watched.Sequence > after && req.matches(watched.Event) {
+			subscription.backlog = append(subscription.backlog, watched)
+		}
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription, nil
+}
+
+// Epoch identifies the stream of the broadcaster, the sequences are only positions in it
+func (b *UserEventBroadcaster) Epoch() string {
+	return b.epoch
+}
+
+// Close ends every subscription with ErrUserEventsStopped, once no more event is broadcast
+func (b *UserEventBroadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for subscriber := range b.subscribers {
+		b.drop(subscriber, ErrUserEventsStopped)
+	}
+}
+
+// Subscribers returns the number of active subscriptions
+func (b *UserEventBroadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+func (b *UserEventBroadcaster) drop(subscription *UserEventSubscription, err error) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	subscription.err = err
+	close(subscription.events)
+}
+
+// UserEventSubscription is the stream of the events of a UserEventBroadcaster matching a WatchUsersRequest
+type UserEventSubscription struct {
+	broadcaster *UserEventBroadcaster
+	request     *WatchUsersRequest
+	// backlog are the events of the history following the resume point, sent first
+	backlog []*WatchedUserEvent
+	events  chan *WatchedUserEvent
+	// err is the reason the subscription ended, set before events is closed
+	err error
+}
+
+// Next returns the next event, waiting for it to be broadcast. Once the subscription ends,
+// the buffered events are returned before its error.
+func (s *UserEventSubscription) Next(ctx context.Context) (*WatchedUserEvent, error) {
+	if len(s.backlog) > 0 {
+		watched := s.backlog[0]
+		s.backlog = s.backlog[1:]
+		return watched, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case watched, ok := <-s.events:
+		if !ok {
+			return nil, s.err
+		}
+		return watched, nil
+	}
+}
+
+// Close unsubscribes, no more event is received
+func (s *UserEventSubscription) Close() {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	s.broadcaster.drop(s, ErrUserEventsStopped)
+}
+
+func (r *WatchUsersRequest) matches(event *UserEvent) bool {
+	if len(r.UserIds) > 0 && !slices.Contains(r.UserIds, event.UserId) {
+		return false
+	}
+	if len(r.OperationTypes) > 0 && !slices.Contains(r.OperationTypes, event.OperationType) {
+		return false
+	}
+	if r.Country != nil {
+		before := event.BeforeChange != nil && event.BeforeChange.Country == *r.Country
+		after := event.AfterChange != nil && event.AfterChange.Country == *r.Country
+		if !before && !after {
+			return false
+		}
+	}
+	return true
+}
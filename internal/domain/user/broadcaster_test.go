@@ -0,0 +1,158 @@
+//go:build unit
+
+package domain_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEventBroadcaster_Subscribe(t *testing.T) {
+	italy := "IT"
+	events := []*domain.UserEvent{
+		{Id: "event-1", UserId: "user-1", OperationType: domain.OPERATION_CREATE, AfterChange: &domain.User{Country: "IT"}},
+		{Id: "event-2", UserId: "user-2", OperationType: domain.OPERATION_CREATE, AfterChange: &domain.User{Country: "FR"}},
+		{Id: "event-3", UserId: "user-1", OperationType: domain.OPERATION_UPDATE,
+			BeforeChange: &domain.User{Country: "IT"}, AfterChange: &domain.User{Country: "FR"}},
+		{Id: "event-4", UserId: "user-2", OperationType: domain.OPERATION_DELETE, BeforeChange: &domain.User{Country: "FR"}},
+	}
+
+	tests := []struct {
+		name       string
+		req        *domain.WatchUsersRequest
+		wantEvents []string
+		wantErr    error
+		// noEpoch resumes after a sequence without the epoch of the broadcaster, set otherwise
+		noEpoch bool
+	}{
+		{
+			name:       "resume after event id",
+			req:        &domain.WatchUsersRequest{AfterEventId: "event-3"},
+			wantEvents: []string{"event-4", "event-5"},
+		},
+		{
+			name:       "resume after sequence",
+			req:        &domain.WatchUsersRequest{AfterSequence: 2},
+			wantEvents: []string{"event-3", "event-4", "event-5"},
+		},
+		{
+			name:       "filtered by user id",
+			req:        &domain.WatchUsersRequest{AfterSequence: 2, UserIds: []string{"user-1"}},
+			wantEvents: []string{"event-3"},
+		},
+		{
+			name:       "filtered by operation type",
+			req:        &domain.WatchUsersRequest{AfterSequence: 2, OperationTypes: []domain.OperationType{domain.OPERATION_CREATE, domain.OPERATION_DELETE}},
+			wantEvents: []string{"event-4"},
+		},
+		{
+			name:       "filtered by previous or new country",
+			req:        &domain.WatchUsersRequest{AfterSequence: 2, Country: &italy},
+			wantEvents: []string{"event-3", "event-5"},
+		},
+		{
+			name:    "event no longer in history",
+			req:     &domain.WatchUsersRequest{AfterEventId: "event-2"},
+			wantErr: domain.ErrWatchResumeUnavailable,
+		},
+		{
+			name:    "sequence no longer in history",
+			req:     &domain.WatchUsersRequest{AfterSequence: 1},
+			wantErr: domain.ErrWatchResumeUnavailable,
+		},
+		{
+			name:    "sequence after the last event",
+			req:     &domain.WatchUsersRequest{AfterSequence: 5},
+			wantErr: domain.ErrWatchResumeUnavailable,
+		},
+		{
+			name:    "sequence of another instance",
+			req:     &domain.WatchUsersRequest{AfterSequence: 3, Epoch: "epoch-of-another-instance"},
+			wantErr: domain.ErrWatchResumeUnavailable,
+		},
+		{
+			name:    "sequence without epoch",
+			req:     &domain.WatchUsersRequest{AfterSequence: 3},
+			noEpoch: true,
+			wantErr: domain.ErrWatchResumeUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The history keeps the last 2 events, event-1 and event-2 are forgotten
+			broadcaster := domain.NewUserEventBroadcaster(2, 10)
+			for _, event := range events {
+				broadcaster.Broadcast(event)
+			}
+			if tt.req.AfterSequence > 0 && tt.req.Epoch == "" && !tt.noEpoch {
+				tt.req.Epoch = broadcaster.Epoch()
+			}
+
+			subscription, err := broadcaster.Subscribe(tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// A live event follows the events of the history
+			broadcaster.Broadcast(&domain.UserEvent{Id: "event-5", UserId: "user-3", OperationType: domain.OPERATION_UPDATE,
+				AfterChange: &domain.User{Country: "IT"}})
+			broadcaster.Close()
+
+			got := make([]string, 0)
+			for {
+				watched, err := subscription.Next(context.TODO())
+				if err != nil {
+					assert.ErrorIs(t, err, domain.ErrUserEventsStopped)
+					break
+				}
+				got = append(got, watched.Event.Id)
+			}
+			assert.Equal(t, tt.wantEvents, got)
+		})
+	}
+}
+
+func TestUserEventBroadcaster_SlowSubscriber(t *testing.T) {
+	broadcaster := domain.NewUserEventBroadcaster(10, 2)
+	slow, err := broadcaster.Subscribe(&domain.WatchUsersRequest{})
+	require.NoError(t, err)
+	fast, err := broadcaster.Subscribe(&domain.WatchUsersRequest{})
+	require.NoError(t, err)
+
+	for i := 1; i <= 4; i++ {
+		broadcaster.Broadcast(&domain.UserEvent{Id: fmt.Sprintf("event-%d", i)})
+		watched, err := fast.Next(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), watched.Sequence)
+		assert.Equal(t, broadcaster.Epoch(), watched.Epoch)
+	}
+
+	// The slow subscriber gets its buffered events, then is disconnected
+	for i := 1; i <= 2; i++ {
+		watched, err := slow.Next(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), watched.Sequence)
+	}
+	_, err = slow.Next(context.TODO())
+	assert.ErrorIs(t, err, domain.ErrWatchSubscriberTooSlow)
+	assert.Equal(t, 1, broadcaster.Subscribers())
+
+	// An event emitted again is not broadcast twice
+	broadcaster.Broadcast(&domain.UserEvent{Id: "event-4"})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = fast.Next(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	fast.Close()
+	assert.Equal(t, 0, broadcaster.Subscribers())
+}
@@ -0,0 +1,66 @@
+package domain
+
+// Paths of the published user fields, as named in the User protobuf message
+const (
+	UserFieldId        = "id"
+	UserFieldFirstName = "first_name"
+	UserFieldLastName  = "last_name"
+	UserFieldEmail     = "email"
+	UserFieldCountry   = "country"
+	UserFieldNickname  = "nickname"
+	UserFieldCreatedAt = "created_at"
+	UserFieldUpdatedAt = "updated_at"
+)
+
+// ChangedUserFields returns the paths of the published fields that differ between the two
+// states of a user, a missing state counting as empty. The version is left out since it
+// changes with every write, and the hashed password since it is never published.
+func ChangedUserFields(before, after *User) []string {
+	if before == nil {
+		before = &User{}
+	}
+	if after == nil {
+		after = &User{}
+	}
+
+	changed := make([]string, 0)
+	if before.ID != after.ID {
+		changed = append(changed, UserFieldId)
+	}
+	if before.FirstName != after.FirstName {
+		changed = append(changed, UserFieldFirstName)
+	}
+	if before.LastName != after.LastName {
+		changed = append(changed, UserFieldLastName)
+	}
+	if before.Email != after.Email {
+		changed = append(changed, UserFieldEmail)
+	}
+	if before.Country != after.Country {
+		changed = append(changed, UserFieldCountry)
+	}
+	if before.Nickname != after.Nickname {
+		changed = append(changed, UserFieldNickname)
+	}
+	if !before.CreatedAt.Equal(after.CreatedAt) {
+		changed = append(changed, UserFieldCreatedAt)
+	}
+	if !before.UpdatedAt.Equal(after.UpdatedAt) {
+		changed = append(changed, UserFieldUpdatedAt)
+	}
+	return changed
+}
+
+// IsTimestampOnlyChange reports whether the update only touched updated_at,
+// e.g. when a user is saved again without any change
+func IsTimestampOnlyChange(event *UserEvent) bool {
+	if event.OperationType != OPERATION_UPDATE || len(event.ChangedFields) == 0 {
+		return false
+	}
+	for _, field := range event.ChangedFields {
+		if field != UserFieldUpdatedAt {
+			return false
+		}
+	}
+	return true
+}
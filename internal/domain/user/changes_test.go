@@ -0,0 +1,62 @@
+//go:build unit
+
+package domain_test
+
+import (
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedUserFields(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	user := &domain.User{ID: "user-123", FirstName: "Federico", LastName: "Lapenna", Email: "federico@example.com",
+		Country: "IT", CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1}
+
+	tests := []struct {
+		name     string
+		before   *domain.User
+		after    *domain.User
+		expected []string
+	}{
+		{
+			name:  "create lists every field set",
+			after: user,
+			expected: []string{domain.UserFieldId, domain.UserFieldFirstName, domain.UserFieldLastName, domain.UserFieldEmail,
+				domain.UserFieldCountry, domain.UserFieldCreatedAt, domain.UserFieldUpdatedAt},
+		},
+		{
+			name:   "update lists the modified fields",
+			before: user,
+			after: &domain.User{ID: "user-123", FirstName: "Federico", LastName: "Lapenna", Email: "federico@example.org",
+				Country: "IT", CreatedAt: createdAt, UpdatedAt: updatedAt, Version: 2},
+			expected: []string{domain.UserFieldEmail, domain.UserFieldUpdatedAt},
+		},
+		{
+			name:   "version and password are left out",
+			before: user,
+			after: &domain.User{ID: "user-123", FirstName: "Federico", LastName: "Lapenna", Email: "federico@example.com",
+				HashedPassword: "hash", Country: "IT", CreatedAt: createdAt, UpdatedAt: createdAt, Version: 2},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, domain.ChangedUserFields(tt.before, tt.after))
+		})
+	}
+}
+
+func TestIsTimestampOnlyChange(t *testing.T) {
+	assert.True(t, domain.IsTimestampOnlyChange(&domain.UserEvent{OperationType: domain.OPERATION_UPDATE,
+		ChangedFields: []string{domain.UserFieldUpdatedAt}}))
+	assert.False(t, domain.IsTimestampOnlyChange(&domain.UserEvent{OperationType: domain.OPERATION_UPDATE,
+		ChangedFields: []string{domain.UserFieldNickname, domain.UserFieldUpdatedAt}}))
+	assert.False(t, domain.IsTimestampOnlyChange(&domain.UserEvent{OperationType: domain.OPERATION_UPDATE}))
+	assert.False(t, domain.IsTimestampOnlyChange(&domain.UserEvent{OperationType: domain.OPERATION_CREATE,
+		ChangedFields: []string{domain.UserFieldUpdatedAt}}))
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ProcessedUserCommand is the result of a user command, kept for a command sent again with the
+// same id to get the same result rather than to run twice
+type ProcessedUserCommand struct {
+	Id string
+	// Result is the result published for the command, as encoded on the result topic
+	Result      []byte
+	ProcessedAt time.Time
+}
+
+// ProcessedUserCommandRepository stores the results of the user commands, by command id
+type ProcessedUserCommandRepository interface {
+	// RecordProcessedUserCommand fails with ErrUserCommandAlreadyProcessed when the id is recorded already
+	RecordProcessedUserCommand(ctx context.Context, command *ProcessedUserCommand) error
+	// GetProcessedUserCommand fails with ErrUserCommandNotProcessed when the id is not recorded
+	GetProcessedUserCommand(ctx context.Context, id string) (*ProcessedUserCommand, error)
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserDeadLetterRepository stores the user events that could not be published, by event id
+type UserDeadLetterRepository interface {
+	// RecordDeadLetter adds the event, or counts one more failed attempt when it is already stored
+	RecordDeadLetter(ctx context.Context, event *UserEvent, failure error, failedAt time.Time) error
+	GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	ListDeadLetters(ctx context.Context, request *ListDeadLettersQueryRequest) (*ListDeadLettersQueryResponse, error)
+	DeleteDeadLetter(ctx context.Context, id string) error
+}
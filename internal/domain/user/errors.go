@@ -3,3 +3,24 @@ package domain
 import "errors"
 
 var ErrUserNotFound = errors.New("user not found")
+var ErrUnauthenticated = errors.New("missing actor identity")
+var ErrPermissionDenied = errors.New("permission denied")
+var ErrInvalidToken = errors.New("invalid actor token")
+var ErrUserVersionNotFound = errors.New("user version not found")
+var ErrVersionConflict = errors.New("user has been modified concurrently")
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+var ErrUserReplayNotFound = errors.New("user replay not found")
+var ErrUserReplayRunning = errors.New("user replay is already running")
+var ErrUserReplayCompleted = errors.New("user replay is already completed")
+var ErrUserReplayTakenOver = errors.New("user replay was taken over by another instance")
+var ErrUserCommandNotProcessed = errors.New("user command not processed")
+var ErrUserCommandAlreadyProcessed = errors.New("user command already processed")
+var ErrWatchResumeUnavailable = errors.New("user event resume point is no longer available")
+var ErrWatchSubscriberTooSlow = errors.New("user event subscriber is too slow")
+var ErrUserEventsStopped = errors.New("user events are no longer watched")
+var ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+var ErrInvalidWebhookUrl = errors.New("invalid webhook url")
+var ErrWebhookRetryNotFound = errors.New("webhook retry not found")
+var ErrLeaseHeld = errors.New("leader lease is held by another instance")
+var ErrLeadershipLost = errors.New("leadership lost")
+var ErrStaleFencingToken = errors.New("fencing token is older than the current leader's")
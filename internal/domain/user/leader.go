@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LeaderLease gives an instance the exclusive right to run a task, until it expires
+// without being renewed
+type LeaderLease struct {
+	Name     string
+	HolderId string
+	// FencingToken is increased every time the lease changes holder, so that the writes of a
+	// former leader still running after losing the lease can be told apart and rejected
+	FencingToken int64
+	ExpiresAt    time.Time
+}
+
+// LeaderState is the state of an instance in a leader election, as reported to the health endpoint
+type LeaderState string
+
+const (
+	LeaderStateStandby LeaderState = "standby"
+	LeaderStateLeader  LeaderState = "leader"
+	LeaderStateStopped LeaderState = "stopped"
+)
+
+type LeaderStatus struct {
+	State LeaderState
+	// InstanceId is the lease holder id of this instance
+	InstanceId string
+	// FencingToken is the token of the current term, when leader
+	FencingToken int64
+	LastError    string
+}
+
+// LeaderStatusReporter is implemented by the leader electors exposing their state
+type LeaderStatusReporter interface {
+	LeaderStatus() LeaderStatus
+}
+
+type fencingTokenKey struct{}
+
+// ContextWithFencingToken returns a copy of ctx carrying the fencing token of a leader. Only the resume
+// token saves and the spill segments are fenced with it, and rejected once a newer leader has been
+// elected. The other writes of a deposed leader go through: the audit entries, versions and dead
+// letters are keyed by event id and repeat those of the new leader, and the webhook deliveries and
+// retries are at least once.
+func ContextWithFencingToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, fencingTokenKey{}, token)
+}
+
+// FencingTokenFromContext returns the fencing token carried by ctx, false when the writes are not fenced
+func FencingTokenFromContext(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(fencingTokenKey{}).(int64)
+	return token, ok
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewInterval = 5 * time.Second
+)
+
+// LeaderElectionOptions tunes how often the lease is renewed and how long it lasts
+type LeaderElectionOptions struct {
+	// LeaseDuration is how long the lease stays held without being renewed, that is the longest a
+	// standby waits before taking over a leader that stopped, 15s by default
+	LeaseDuration time.Duration
+	// RenewInterval is the delay between two renewals of the leader, and between two attempts of a
+	// standby to acquire the lease, 5s by default. It must be well below LeaseDuration.
+	RenewInterval time.Duration
+}
+
+// LeaderElector elects, among the instances sharing the same lease name, the one running a task
+// while the others stay on standby, ready to take over
+type LeaderElector struct {
+	leases   LeaderLeaseRepository
+	name     string
+	holderId string
+	opts     LeaderElectionOptions
+
+	mu     sync.RWMutex
+	status LeaderStatus
+}
+
+func NewLeaderElector(leases LeaderLeaseRepository, name, holderId string, opts LeaderElectionOptions) *LeaderElector {
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = defaultLeaseDuration
+	}
+	if opts.RenewInterval <= 0 || opts.RenewInterval >= opts.LeaseDuration {
+		opts.RenewInterval = opts.LeaseDuration / 3
+	}
+	return &LeaderElector{
+		leases:   leases,
+		name:     name,
+		holderId: holderId,
+		opts:     opts,
+		status:   LeaderStatus{State: LeaderStateStandby, InstanceId: holderId},
+	}
+}
+
+func (e *LeaderElector) LeaderStatus() LeaderStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
+
+// Run campaigns for the lease until elected, then calls lead with a context carrying the fencing
+// token of the term, and keeps renewing the lease. When the lease is taken over, or could not be
+// renewed before expiring, the context given to lead is cancelled and ErrLeadershipLost returned.
+// When ctx is done, the lease, if held, is released for a standby to take over right away and nil
+// is returned.
+func (e *LeaderElector) Run(ctx context.Context, lead func(ctx context.Context)) error {
+	lease, deadline, err := e.campaign(ctx)
+	if err != nil {
+		return nil
+	}
+
+	leaderCtx, cancel := context.WithCancel(ContextWithFencingToken(ctx, lease.FencingToken))
+	defer cancel()
+	lead(leaderCtx)
+
+	err = e.renew(ctx, lease, deadline)
+	cancel()
+	if err != nil {
+		log.Errorf("Lost the %s leadership: %v", e.name, err)
+		e.setStatus(LeaderStateStandby, 0, err)
+		return err
+	}
+
+	// Released with the values of ctx, such as the fencing token, but not its cancellation
+	if err := e.leases.ReleaseLeaderLease(context.WithoutCancel(ctx), lease); err != nil {
+		log.Errorf("failed to release the %s lease: %v", e.name, err)
+	}
+	e.setStatus(LeaderStateStopped, 0, nil)
+	return nil
+}
+
+// campaign tries to acquire the lease every RenewInterval, until acquired or ctx is done. It
+// returns the lease with the local time it is known to be held until.
+func (e *LeaderElector) campaign(ctx context.Context) (*LeaderLease, time.Time, error) {
+	for {
+		requestedAt := time.Now()
+		lease, err := e.leases.AcquireLeaderLease(ctx, e.name, e.holderId, e.opts.LeaseDuration)
+		switch {
+		case err == nil:
+			log.Infof("Elected %s leader with fencing token %d", e.name, lease.FencingToken)
+			e.setStatus(LeaderStateLeader, lease.FencingToken, nil)
+			return lease, requestedAt.Add(e.opts.LeaseDuration), nil
+		case errors.Is(err, ErrLeaseHeld):
+			e.setStatus(LeaderStateStandby, 0, nil)
+		default:
+			log.Errorf("failed to acquire the %s lease: %v", e.name, err)
+			e.setStatus(LeaderStateStandby, 0, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			e.setStatus(LeaderStateStopped, 0, nil)
+			return nil, time.Time{}, ctx.Err()
+		case <-time.After(e.opts.RenewInterval):
+		}
+	}
+}
+
+// renew extends the lease every RenewInterval until ctx is done. The lease is held until the
+// deadline measured from the local time its last renewal was requested at, which is never later
+// than the time it expires in the repository: past the deadline, a standby may have taken over.
+func (e *LeaderElector) renew(ctx context.Context, lease *LeaderLease, deadline time.Time) error {
+	ticker := time.NewTicker(e.opts.RenewInterval)
+	defer ticker.Stop()
+	for {
+		expired := time.NewTimer(time.Until(deadline))
+		select {
+		case <-ctx.Done():
+			expired.Stop()
+			return nil
+		case <-expired.C:
+			return fmt.Errorf("%w: the lease could not be renewed before expiring", ErrLeadershipLost)
+		case <-ticker.C:
+			expired.Stop()
+		}
+
+		requestedAt := time.Now()
+		renewed, err := e.leases.AcquireLeaderLease(ctx, e.name, e.holderId, e.opts.LeaseDuration)
+		switch {
+		case err == nil && renewed.FencingToken == lease.FencingToken:
+			deadline = requestedAt.Add(e.opts.LeaseDuration)
+			e.setStatus(LeaderStateLeader, lease.FencingToken, nil)
+		case err == nil:
+			// The lease expired before being renewed and was acquired again as a new term: another
+			// instance may have led in between, so the current term is over
+			if err := e.leases.ReleaseLeaderLease(context.WithoutCancel(ctx), renewed); err != nil {
+				log.Errorf("failed to release the %s lease: %v", e.name, err)
+			}
+			return fmt.Errorf("%w: the lease expired before being renewed", ErrLeadershipLost)
+		case errors.Is(err, ErrLeaseHeld):
+			return fmt.Errorf("%w: the lease was taken over", ErrLeadershipLost)
+		case ctx.Err() != nil:
+			return nil
+		default:
+			// Retried on the next tick, as long as the lease has not expired
+			log.Errorf("failed to renew the %s lease: %v", e.name, err)
+			e.setStatus(LeaderStateLeader, lease.FencingToken, err)
+		}
+	}
+}
+
+func (e *LeaderElector) setStatus(state LeaderState, fencingToken int64, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.status.State = state
+	e.status.FencingToken = fencingToken
+	e.status.LastError = ""
+	if err != nil {
+		e.status.LastError = err.Error()
+	}
+}
@@ -0,0 +1,108 @@
+//go:build unit
+
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLeaderElector_Run(t *testing.T) {
+	lease := &domain.LeaderLease{Name: "user-watcher", HolderId: "instance-1", FencingToken: 7}
+	otherTerm := &domain.LeaderLease{Name: "user-watcher", HolderId: "instance-1", FencingToken: 8}
+	tests := []struct {
+		name string
+		// renewals are the results of the acquisitions following the election
+		renewals     []error
+		renewedLease *domain.LeaderLease
+		leaseTTL     time.Duration
+		wantErr      error
+	}{
+		{name: "lease taken over", renewals: []error{nil, domain.ErrLeaseHeld}, renewedLease: lease,
+			leaseTTL: time.Second, wantErr: domain.ErrLeadershipLost},
+		{name: "lease expired before being renewed", renewals: []error{nil}, renewedLease: otherTerm,
+			leaseTTL: time.Second, wantErr: domain.ErrLeadershipLost},
+		{name: "lease not renewed before expiring", renewals: []error{errors.New("connection refused")},
+			renewedLease: lease, leaseTTL: 100 * time.Millisecond, wantErr: domain.ErrLeadershipLost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLeases := new(mocks.MockLeaderLeaseRepository)
+			elector := domain.NewLeaderElector(mockLeases, "user-watcher", "instance-1", domain.LeaderElectionOptions{
+				LeaseDuration: tt.leaseTTL, RenewInterval: 10 * time.Millisecond})
+
+			// On standby until the lease expires
+			mockLeases.On("AcquireLeaderLease", mock.Anything, "user-watcher", "instance-1", tt.leaseTTL).
+				Return(nil, domain.ErrLeaseHeld).Twice()
+			mockLeases.On("AcquireLeaderLease", mock.Anything, "user-watcher", "instance-1", tt.leaseTTL).
+				Return(lease, nil).Once()
+			for i, err := range tt.renewals {
+				call := mockLeases.On("AcquireLeaderLease", mock.Anything, "user-watcher", "instance-1", tt.leaseTTL)
+				if err != nil {
+					call.Return(nil, err)
+				} else {
+					call.Return(tt.renewedLease, nil)
+				}
+				if i < len(tt.renewals)-1 {
+					call.Once()
+				}
+			}
+			mockLeases.On("ReleaseLeaderLease", mock.Anything, otherTerm).Return(nil).Maybe()
+
+			var leaderCtx context.Context
+			err := elector.Run(context.Background(), func(ctx context.Context) {
+				token, fenced := domain.FencingTokenFromContext(ctx)
+				assert.True(t, fenced)
+				assert.Equal(t, int64(7), token)
+				assert.Equal(t, domain.LeaderStatus{State: domain.LeaderStateLeader, InstanceId: "instance-1", FencingToken: 7},
+					elector.LeaderStatus())
+				leaderCtx = ctx
+			})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Error(t, leaderCtx.Err(), "the leader context is cancelled")
+			assert.Equal(t, domain.LeaderStateStandby, elector.LeaderStatus().State)
+			assert.NotEmpty(t, elector.LeaderStatus().LastError)
+			mockLeases.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLeaderElector_Run_Stopped(t *testing.T) {
+	lease := &domain.LeaderLease{Name: "user-watcher", HolderId: "instance-1", FencingToken: 3}
+
+	// The leader releases the lease when stopped
+	mockLeases := new(mocks.MockLeaderLeaseRepository)
+	mockLeases.On("AcquireLeaderLease", mock.Anything, "user-watcher", "instance-1", time.Second).Return(lease, nil)
+	mockLeases.On("ReleaseLeaderLease", mock.Anything, lease).Return(nil).Once()
+	elector := domain.NewLeaderElector(mockLeases, "user-watcher", "instance-1", domain.LeaderElectionOptions{
+		LeaseDuration: time.Second, RenewInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	led := false
+	assert.NoError(t, elector.Run(ctx, func(ctx context.Context) { led = true }))
+	assert.True(t, led)
+	assert.Equal(t, domain.LeaderStateStopped, elector.LeaderStatus().State)
+	mockLeases.AssertExpectations(t)
+
+	// A standby never leads
+	mockLeases = new(mocks.MockLeaderLeaseRepository)
+	mockLeases.On("AcquireLeaderLease", mock.Anything, "user-watcher", "instance-2", time.Second).Return(nil, domain.ErrLeaseHeld)
+	elector = domain.NewLeaderElector(mockLeases, "user-watcher", "instance-2", domain.LeaderElectionOptions{
+		LeaseDuration: time.Second, RenewInterval: 10 * time.Millisecond})
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, elector.Run(ctx, func(ctx context.Context) { t.Error("a standby must not lead") }))
+	assert.Equal(t, domain.LeaderStateStopped, elector.LeaderStatus().State)
+	mockLeases.AssertNotCalled(t, "ReleaseLeaderLease", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LeaderLeaseRepository stores the leader leases, by lease name
+type LeaderLeaseRepository interface {
+	// AcquireLeaderLease gives the lease to holderId for duration, with a new fencing token, when
+	// it is free or expired. It extends it, keeping its fencing token, when holderId already holds
+	// it. It returns ErrLeaseHeld when the lease is held by another instance.
+	AcquireLeaderLease(ctx context.Context, name, holderId string, duration time.Duration) (*LeaderLease, error)
+	// ReleaseLeaderLease expires the lease right away, when it is still held with the fencing token of lease
+	ReleaseLeaderLease(ctx context.Context, lease *LeaderLease) error
+}
@@ -14,6 +14,12 @@ type User struct {
 	Nickname       string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	LastModifiedBy string
+	CorrelationId  string
+	TraceParent    string
+	// Version is incremented on every write. When set on an update it is the expected
+	// current version, used for optimistic concurrency.
+	Version int64
 }
 
 type ListUsersQueryRequest struct {
@@ -33,12 +39,160 @@ type ListUsersQueryResponse struct {
 	Results    []*User
 }
 
+// ScanUsersQuery selects the users to walk through, with the filters of ListUsersQueryRequest
+type ScanUsersQuery struct {
+	// AfterId resumes the scan after the user with this id
+	AfterId   string
+	Country   *string
+	FirstName *string
+	LastName  *string
+	Nickname  *string
+	Email     *string
+}
+
+// UserDataExport gathers everything the service holds about a single user
+type UserDataExport struct {
+	// User is the current state of the user, or its last state before it was deleted
+	User         *User
+	Deleted      bool
+	Versions     []*UserVersion
+	AuditEntries []*UserAuditEntry
+	ExportedAt   time.Time
+}
+
+// UserVersion is the state of a user from ValidFrom until the next version.
+// A deleted version marks the user as removed.
+type UserVersion struct {
+	UserId        string
+	Version       int64
+	User          *User
+	Deleted       bool
+	ValidFrom     time.Time
+	ModifiedBy    string
+	CorrelationId string
+}
+
+type ListUserVersionsQueryRequest struct {
+	UserId   string
+	Page     uint32
+	PageSize uint32
+}
+
+type ListUserVersionsQueryResponse struct {
+	Page       uint32
+	PageSize   uint32
+	TotalCount uint32
+	Results    []*UserVersion
+}
+
 type UserEvent struct {
 	Id            string
 	UserId        string
 	BeforeChange  *User
 	AfterChange   *User
 	OperationType OperationType
+	ModifiedBy    string
+	CorrelationId string
+	// TraceParent is the W3C trace context of the request that made the change
+	TraceParent string
+	// Sequence increases with every change of the user: it is the version resulting from the change
+	Sequence int64
+	// ClusterTime is the MongoDB cluster time of the change (seconds << 32 | increment),
+	// only known when the event is captured by the change stream
+	ClusterTime uint64
+	OccurredAt  time.Time
+	// ChangedFields are the paths of the user fields modified by the change, see ChangedUserFields
+	ChangedFields []string
+	// Replayed marks the synthetic creations published by a UserReplay
+	Replayed bool
+}
+
+// UserAuditEntry is the immutable record of a single UserEvent
+type UserAuditEntry struct {
+	Id            string
+	UserId        string
+	BeforeChange  *User
+	AfterChange   *User
+	OperationType OperationType
+	ModifiedBy    string
+	CorrelationId string
+	RecordedAt    time.Time
+}
+
+type ListUserAuditEntriesQueryRequest struct {
+	Page       uint32
+	PageSize   uint32
+	UserId     *string
+	ModifiedBy *string
+	From       *time.Time
+	To         *time.Time
+}
+
+type ListUserAuditEntriesQueryResponse struct {
+	Page       uint32
+	PageSize   uint32
+	TotalCount uint32
+	Results    []*UserAuditEntry
+}
+
+// DeadLetter is a UserEvent that could not be published, kept to be redriven or discarded
+type DeadLetter struct {
+	Id            string
+	Event         *UserEvent
+	Error         string
+	Attempts      int32
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+}
+
+type ListDeadLettersQueryRequest struct {
+	Page     uint32
+	PageSize uint32
+}
+
+type ListDeadLettersQueryResponse struct {
+	Page       uint32
+	PageSize   uint32
+	TotalCount uint32
+	Results    []*DeadLetter
+}
+
+// UserReplayState is the state of a UserReplay, as of its last checkpoint
+type UserReplayState string
+
+const (
+	// UserReplayStatePending is a replay waiting for the leader to claim it
+	UserReplayStatePending   UserReplayState = "pending"
+	UserReplayStateRunning   UserReplayState = "running"
+	UserReplayStateCompleted UserReplayState = "completed"
+	UserReplayStateFailed    UserReplayState = "failed"
+)
+
+// UserReplay publishes a synthetic creation for every existing user matching Query,
+// checkpointing the last replayed user to be resumed after an interruption
+type UserReplay struct {
+	Id string
+	// Query selects the replayed users, its AfterId is the last replayed user as of the last checkpoint
+	Query         ScanUsersQuery
+	RatePerSecond uint32
+	State         UserReplayState
+	// Owner is the instance running the replay, empty unless running
+	Owner         string
+	ReplayedCount int64
+	Error         string
+	StartedBy     string
+	StartedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type StartUserReplayRequest struct {
+	Id            string
+	Country       *string
+	FirstName     *string
+	LastName      *string
+	Nickname      *string
+	Email         *string
+	RatePerSecond uint32
 }
 
 type OperationType int32
@@ -48,4 +202,7 @@ const (
 	OPERATION_CREATE      OperationType = 1
 	OPERATION_UPDATE      OperationType = 2
 	OPERATION_DELETE      OperationType = 3
+	// OPERATION_EXPORT is only recorded in the audit log, when the data of a user is exported.
+	// It has no value in the OperationType of the API, the audit entries map it to UserAuditAction.
+	OPERATION_EXPORT OperationType = 4
 )
@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultPipelineWorkers   = 4
+	defaultPipelineQueueSize = 100
+)
+
+// UserEventPipelineOptions tunes the parallelism of the user event pipeline
+type UserEventPipelineOptions struct {
+	// Workers is the number of events processed in parallel, 4 by default
+	Workers int
+	// QueueSize is the number of events waiting for each worker, 100 by default. A full queue
+	// blocks the watcher until the worker catches up.
+	QueueSize int
+}
+
+// UserEventPipelineStats is the state of a UserEventPipeline, as reported to the metrics
+type UserEventPipelineStats struct {
+	// QueueDepth is the number of events waiting for a worker
+	QueueDepth int
+	// InFlight is the number of events received from the watcher and not acknowledged yet
+	InFlight int
+	// Lag is the time between the change and the end of its processing, for the last processed event
+	Lag time.Duration
+}
+
+// UserEventPipelineStatsReporter is implemented by the services exposing their event pipeline
+type UserEventPipelineStatsReporter interface {
+	UserEventPipelineStats() UserEventPipelineStats
+}
+
+// pipelineItem is an event on its way through the pipeline, done receives its processing outcome
+type pipelineItem struct {
+	event *UserEvent
+	done  chan error
+	// submitted receives the acknowledged outcome of a submitted event, nil for the events of the watcher
+	submitted chan error
+	// prev is the previous in-flight event of the same user, nil when there is none
+	prev *pipelineItem
+	// acked is closed once the outcome is acknowledged to the watcher, err being the outcome
+	acked chan struct{}
+	err   error
+}
+
+// UserEventPipeline processes the user events of a watcher on a pool of workers. The events
+// of a user always go to the same worker, so they are processed in order, while the events of
+// different users are processed in parallel. The outcomes are acknowledged to the watcher in
+// the order it emitted the events, for a checkpoint to never get ahead of an unprocessed event.
+// An event is only processed once the previous event of its user is acknowledged: when that
+// event failed, and the watcher emits it again, the event fails too without being processed,
+// so that the events of a user are never published out of order.
+// The events submitted while running, e.g. the replayed users, are ordered with those of the watcher.
+// The pipeline runs again, with new queues, once the previous Run returned.
+type UserEventPipeline struct {
+	process func(ctx context.Context, event *UserEvent) error
+	opts    UserEventPipelineOptions
+
+	// queues are the queues of the current Run, nil when not running
+	mu     sync.Mutex
+	queues []chan *pipelineItem
+
+	// run is the current Run, nil when not running. The events are enqueued one at a time, for the
+	// previous event of a user to always be queued before the next one.
+	enqueueMu sync.Mutex
+	run       *pipelineRun
+
+	inFlight atomic.Int64
+	lag      atomic.Int64
+}
+
+func NewUserEventPipeline(process func(ctx context.Context, event *UserEvent) error, opts UserEventPipelineOptions) *UserEventPipeline {
+	if opts.Workers <= 0 {
+		opts.Workers = defaultPipelineWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultPipelineQueueSize
+	}
+	return &UserEventPipeline{
+		process: process,
+		opts:    opts,
+	}
+}
+
+// pipelineRun is the state of a Run the events are enqueued to
+type pipelineRun struct {
+	queues  []chan *pipelineItem
+	pending chan *pipelineItem
+	users   *inFlightUsers
+}
+
+// Run processes the events of watcher until its channel is closed, then waits for the
+// in-flight events to be processed and acknowledged
+func (p *UserEventPipeline) Run(ctx context.Context, watcher UserWatcher) {
+	queues := make([]chan *pipelineItem, p.opts.Workers)
+	for i := range queues {
+		queues[i] = make(chan *pipelineItem, p.opts.QueueSize)
+	}
+	// The events being processed are pending too
+	pending := make(chan *pipelineItem, p.opts.Workers*(p.opts.QueueSize+1))
+	p.mu.Lock()
+	p.queues = queues
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.queues = nil
+		p.mu.Unlock()
+	}()
+
+	userEvents := watcher.WatchUsers(ctx)
+	_, redelivers := watcher.(UserEventAcknowledger)
+
+	var workers sync.WaitGroup
+	for _, queue := range queues {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			p.work(ctx, queue, redelivers)
+		}()
+	}
+	users := &inFlightUsers{last: make(map[string]*pipelineItem)}
+	acknowledged := make(chan struct{})
+	go func() {
+		defer close(acknowledged)
+		p.acknowledge(ctx, watcher, pending, users)
+	}()
+
+	run := &pipelineRun{queues: queues, pending: pending, users: users}
+	p.enqueueMu.Lock()
+	p.run = run
+	p.enqueueMu.Unlock()
+
+	for userEvent := range userEvents {
+		p.enqueueMu.Lock()
+		p.enqueue(run, &pipelineItem{event: userEvent, done: make(chan error, 1), acked: make(chan struct{})})
+		p.enqueueMu.Unlock()
+	}
+
+	// No event is submitted anymore once the queues are closed
+	p.enqueueMu.Lock()
+	p.run = nil
+	for _, queue := range queues {
+		close(queue)
+	}
+	p.enqueueMu.Unlock()
+	workers.Wait()
+	close(pending)
+	<-acknowledged
+}
+
+// Submit processes an event that does not come from the watcher, after the in-flight events of its
+// user, and returns its outcome once acknowledged in order. It fails with ErrUserEventsStopped when
+// the pipeline is not running.
+func (p *UserEventPipeline) Submit(ctx context.Context, event *UserEvent) error {
+	item := &pipelineItem{event: event, done: make(chan error, 1), submitted: make(chan error, 1), acked: make(chan struct{})}
+	p.enqueueMu.Lock()
+	if p.run == nil {
+		p.enqueueMu.Unlock()
+		return ErrUserEventsStopped
+	}
+	p.enqueue(p.run, item)
+	p.enqueueMu.Unlock()
+
+	select {
+	case err := <-item.submitted:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue tracks the item as the last event of its user, and queues it to its worker. Both sends
+// block when the pipeline is full, which stops the watcher from reading further.
+func (p *UserEventPipeline) enqueue(run *pipelineRun, item *pipelineItem) {
+	item.prev = run.users.track(item)
+	p.inFlight.Add(1)
+	run.pending <- item
+	run.queues[partition(item.event.UserId, len(run.queues))] <- item
+}
+
+func (p *UserEventPipeline) UserEventPipelineStats() UserEventPipelineStats {
+	p.mu.Lock()
+	depth := 0
+	for _, queue := range p.queues {
+		depth += len(queue)
+	}
+	p.mu.Unlock()
+	return UserEventPipelineStats{
+		QueueDepth: depth,
+		InFlight:   int(p.inFlight.Load()),
+		Lag:        time.Duration(p.lag.Load()),
+	}
+}
+
+// partition is the worker of the events of a user, among workers
+func partition(userId string, workers int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(userId))
+	return int(h.Sum32() % uint32(workers))
+}
+
+// work processes the events of queue. When redelivers, an event whose previous event failed
+// fails with the same error, to be emitted again after it.
+func (p *UserEventPipeline) work(ctx context.Context, queue <-chan *pipelineItem, redelivers bool) {
+	for item := range queue {
+		if item.prev != nil {
+			<-item.prev.acked
+			if item.prev.err != nil && redelivers {
+				item.done <- item.prev.err
+				continue
+			}
+		}
+		err := p.process(ctx, item.event)
+		if !item.event.OccurredAt.IsZero() {
+			p.lag.Store(int64(time.Since(item.event.OccurredAt)))
+		}
+		item.done <- err
+	}
+}
+
+// acknowledge reports the outcomes to the watcher in the order of the events, and those of the
+// submitted events to their submitters
+func (p *UserEventPipeline) acknowledge(ctx context.Context, watcher UserWatcher, pending <-chan *pipelineItem, users *inFlightUsers) {
+	for item := range pending {
+		err := <-item.done
+		if item.submitted != nil {
+			item.submitted <- err
+		} else {
+			acknowledge(ctx, watcher, item.event, err)
+		}
+		item.err = err
+		close(item.acked)
+		users.release(item)
+		p.inFlight.Add(-1)
+	}
+}
+
+// inFlightUsers is the last in-flight event of every user
+type inFlightUsers struct {
+	mu   sync.Mutex
+	last map[string]*pipelineItem
+}
+
+// track makes item the last event of its user, and returns the previous one
+func (u *inFlightUsers) track(item *pipelineItem) *pipelineItem {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	prev := u.last[item.event.UserId]
+	u.last[item.event.UserId] = item
+	return prev
+}
+
+// release forgets the acknowledged item, unless a later event of its user is in flight
+func (u *inFlightUsers) release(item *pipelineItem) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.last[item.event.UserId] == item {
+		delete(u.last, item.event.UserId)
+	}
+}
@@ -0,0 +1,213 @@
+//go:build unit
+
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserEventPipeline_Run(t *testing.T) {
+	var events []*domain.UserEvent
+	for i := 0; i < 30; i++ {
+		events = append(events, &domain.UserEvent{
+			Id:         fmt.Sprintf("event-%d", i),
+			UserId:     fmt.Sprintf("user-%d", i%3),
+			Sequence:   int64(i / 3),
+			OccurredAt: time.Now().UTC(),
+		})
+	}
+
+	userEvents := make(chan *domain.UserEvent, len(events))
+	for _, event := range events {
+		userEvents <- event
+	}
+	close(userEvents)
+
+	mockWatcher := new(mocks.MockUserWatcher)
+	mockAcknowledger := new(mocks.MockUserEventAcknowledger)
+	watcher := &acknowledgingWatcher{mockWatcher, mockAcknowledger}
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(userEvents))
+
+	var mu sync.Mutex
+	var acknowledged []string
+	mockAcknowledger.On("AckUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent")).Return(nil).
+		Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			acknowledged = append(acknowledged, args.Get(1).(*domain.UserEvent).Id)
+		})
+	nackErr := errors.New("producer error")
+	mockAcknowledger.On("NackUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent"), nackErr).
+		Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			acknowledged = append(acknowledged, "nack:"+args.Get(1).(*domain.UserEvent).Id)
+		})
+
+	processed := make(map[string][]int64)
+	process := func(ctx context.Context, event *domain.UserEvent) error {
+		// The first events of a user take longer, the events of the others go ahead
+		time.Sleep(time.Duration(10-event.Sequence) * time.Millisecond)
+		mu.Lock()
+		processed[event.UserId] = append(processed[event.UserId], event.Sequence)
+		mu.Unlock()
+		if event == events[7] {
+			return nackErr
+		}
+		return nil
+	}
+	pipeline := domain.NewUserEventPipeline(process, domain.UserEventPipelineOptions{Workers: 3, QueueSize: 2})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pipeline.Run(context.TODO(), watcher)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the pipeline to process the events")
+	}
+
+	// The events of user-1 following the failed one fail too, without being processed, to be emitted again after it
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, processed["user-0"])
+	assert.Equal(t, []int64{0, 1, 2}, processed["user-1"])
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, processed["user-2"])
+	var want []string
+	for i, event := range events {
+		if i >= 7 && event.UserId == events[7].UserId {
+			want = append(want, "nack:"+event.Id)
+			continue
+		}
+		want = append(want, event.Id)
+	}
+	assert.Equal(t, want, acknowledged)
+
+	stats := pipeline.UserEventPipelineStats()
+	assert.Equal(t, 0, stats.QueueDepth)
+	assert.Equal(t, 0, stats.InFlight)
+	assert.Positive(t, stats.Lag)
+}
+
+func TestUserEventPipeline_Backpressure(t *testing.T) {
+	userEvents := make(chan *domain.UserEvent)
+	mockWatcher := new(mocks.MockUserWatcher)
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(userEvents))
+
+	release := make(chan struct{})
+	process := func(ctx context.Context, event *domain.UserEvent) error {
+		<-release
+		return nil
+	}
+	pipeline := domain.NewUserEventPipeline(process, domain.UserEventPipelineOptions{Workers: 1, QueueSize: 2})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pipeline.Run(context.TODO(), mockWatcher)
+	}()
+
+	// One event is being processed, two are queued and one waits for room in the queue
+	for i := 0; i < 4; i++ {
+		userEvents <- &domain.UserEvent{Id: fmt.Sprintf("event-%d", i), UserId: "user-1"}
+	}
+	select {
+	case userEvents <- &domain.UserEvent{Id: "event-4", UserId: "user-1"}:
+		t.Fatal("the pipeline accepted an event while full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, 2, pipeline.UserEventPipelineStats().QueueDepth)
+	assert.Equal(t, 4, pipeline.UserEventPipelineStats().InFlight)
+
+	close(release)
+	close(userEvents)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the pipeline to stop")
+	}
+	assert.Equal(t, 0, pipeline.UserEventPipelineStats().InFlight)
+}
+
+func TestUserEventPipeline_RunAgain(t *testing.T) {
+	mockWatcher := new(mocks.MockUserWatcher)
+	var processed []string
+	process := func(ctx context.Context, event *domain.UserEvent) error {
+		processed = append(processed, event.Id)
+		return nil
+	}
+	pipeline := domain.NewUserEventPipeline(process, domain.UserEventPipelineOptions{Workers: 1})
+
+	// Every term of a leader runs the pipeline on a new stream of the watcher
+	for term := 0; term < 2; term++ {
+		userEvents := make(chan *domain.UserEvent, 1)
+		userEvents <- &domain.UserEvent{Id: fmt.Sprintf("event-%d", term), UserId: "user-1"}
+		close(userEvents)
+		mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(userEvents)).Once()
+
+		pipeline.Run(context.TODO(), mockWatcher)
+	}
+
+	assert.Equal(t, []string{"event-0", "event-1"}, processed)
+	assert.Equal(t, domain.UserEventPipelineStats{}, pipeline.UserEventPipelineStats())
+	mockWatcher.AssertExpectations(t)
+}
+
+func TestUserEventPipeline_Submit(t *testing.T) {
+	userEvents := make(chan *domain.UserEvent)
+	mockWatcher := new(mocks.MockUserWatcher)
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(userEvents))
+
+	var mu sync.Mutex
+	var processed []string
+	release := make(chan struct{})
+	process := func(ctx context.Context, event *domain.UserEvent) error {
+		if event.Id == "event-0" {
+			<-release
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		processed = append(processed, event.Id)
+		return nil
+	}
+	pipeline := domain.NewUserEventPipeline(process, domain.UserEventPipelineOptions{Workers: 2})
+
+	// No event can be submitted before the pipeline runs
+	assert.Equal(t, domain.ErrUserEventsStopped, pipeline.Submit(context.TODO(), &domain.UserEvent{Id: "replay-0", UserId: "user-1"}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pipeline.Run(context.TODO(), mockWatcher)
+	}()
+	userEvents <- &domain.UserEvent{Id: "event-0", UserId: "user-1"}
+
+	// The submitted event of user-1 waits for the event of the watcher in flight
+	submitted := make(chan error)
+	go func() {
+		submitted <- pipeline.Submit(context.TODO(), &domain.UserEvent{Id: "replay-1", UserId: "user-1"})
+	}()
+	assert.Eventually(t, func() bool { return pipeline.UserEventPipelineStats().InFlight == 2 }, time.Second, 5*time.Millisecond)
+	close(release)
+	select {
+	case err := <-submitted:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the submitted event")
+	}
+	assert.Equal(t, []string{"event-0", "replay-1"}, processed)
+
+	close(userEvents)
+	<-done
+	assert.Equal(t, domain.ErrUserEventsStopped, pipeline.Submit(context.TODO(), &domain.UserEvent{Id: "replay-2", UserId: "user-1"}))
+}
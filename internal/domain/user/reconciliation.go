@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// PublishedUser is the latest state of a user as published to Kafka
+type PublishedUser struct {
+	UserId  string
+	Version int64
+	Deleted bool
+}
+
+// UserDriftKind tells how the published state of a user differs from the stored one
+type UserDriftKind string
+
+const (
+	// UserDriftMissing is a stored user never published, or published as deleted
+	UserDriftMissing UserDriftKind = "missing"
+	// UserDriftStale is a stored user published with another version
+	UserDriftStale UserDriftKind = "stale"
+	// UserDriftOrphaned is a published user which is no longer stored
+	UserDriftOrphaned UserDriftKind = "orphaned"
+)
+
+// UserDrift is a user whose published state differs from the stored one
+type UserDrift struct {
+	UserId           string
+	Kind             UserDriftKind
+	StoredVersion    int64
+	PublishedVersion int64
+	// Repaired tells whether a corrective event was published
+	Repaired bool
+}
+
+// UserReconciliationReport is the outcome of a reconciliation of the published users with the stored ones
+type UserReconciliationReport struct {
+	Id             string
+	StoredUsers    int
+	PublishedUsers int
+	// InFlightUsers were changed after the topic was read, their drift is not known yet
+	InFlightUsers int
+	Drifts        []*UserDrift
+	StartedAt     time.Time
+	FinishedAt    time.Time
+}
+
+// Count returns the number of drifts of the given kind
+func (r *UserReconciliationReport) Count(kind UserDriftKind) int {
+	count := 0
+	for _, drift := range r.Drifts {
+		if drift.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Repaired returns the number of drifts repaired with a corrective event
+func (r *UserReconciliationReport) Repaired() int {
+	repaired := 0
+	for _, drift := range r.Drifts {
+		if drift.Repaired {
+			repaired++
+		}
+	}
+	return repaired
+}
+
+// UserReconciler compares the users published to Kafka with the stored ones
+type UserReconciler struct {
+	repo     UserRepository
+	producer UserProducer
+}
+
+// NewUserReconciler creates a reconciler, producer publishing the corrective events in repair mode
+func NewUserReconciler(repo UserRepository, producer UserProducer) *UserReconciler {
+	return &UserReconciler{
+		repo:     repo,
+		producer: producer,
+	}
+}
+
+// Reconcile compares the published users, read from the topic up to readAt, with the stored
+// users. Users updated after readAt are counted as in flight rather than drifted. With repair,
+// the current state of the missing and stale users is published again, and the orphaned
+// users are published as deleted.
+func (r *UserReconciler) Reconcile(ctx context.Context, published map[string]*PublishedUser, readAt time.Time, repair bool) (*UserReconciliationReport, error) {
+	report := &UserReconciliationReport{
+		Id:        uuid.NewString(),
+		Drifts:    make([]*UserDrift, 0),
+		StartedAt: time.Now().UTC().Round(time.Millisecond),
+	}
+	seen := make(map[string]bool, len(published))
+
+	err := r.repo.ScanUsers(ctx, &ScanUsersQuery{}, func(user *User) error {
+		report.StoredUsers++
+		seen[user.ID] = true
+		if user.UpdatedAt.After(readAt) {
+			report.InFlightUsers++
+			return nil
+		}
+
+		publishedUser, ok := published[user.ID]
+		var drift *UserDrift
+		switch {
+		case !ok || publishedUser.Deleted:
+			drift = &UserDrift{UserId: user.ID, Kind: UserDriftMissing, StoredVersion: user.Version}
+			if ok {
+				drift.PublishedVersion = publishedUser.Version
+			}
+		case publishedUser.Version != user.Version:
+			drift = &UserDrift{UserId: user.ID, Kind: UserDriftStale, StoredVersion: user.Version, PublishedVersion: publishedUser.Version}
+		default:
+			return nil
+		}
+		report.Drifts = append(report.Drifts, drift)
+		if repair {
+			return r.repairUser(report.Id, drift, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, publishedUser := range published {
+		if publishedUser.Deleted {
+			continue
+		}
+		report.PublishedUsers++
+		if seen[publishedUser.UserId] {
+			continue
+		}
+		drift := &UserDrift{UserId: publishedUser.UserId, Kind: UserDriftOrphaned, PublishedVersion: publishedUser.Version}
+		report.Drifts = append(report.Drifts, drift)
+		if repair {
+			if err := r.repairUser(report.Id, drift, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC().Round(time.Millisecond)
+	return report, nil
+}
+
+// repairUser publishes the corrective event of the drift, correlated with the reconciliation
+func (r *UserReconciler) repairUser(reconciliationId string, drift *UserDrift, user *User) error {
+	event := &UserEvent{
+		Id:            uuid.NewString(),
+		UserId:        drift.UserId,
+		CorrelationId: reconciliationId,
+		OccurredAt:    time.Now().UTC().Round(time.Millisecond),
+	}
+	switch drift.Kind {
+	case UserDriftMissing:
+		event.OperationType = OPERATION_CREATE
+	case UserDriftStale:
+		event.OperationType = OPERATION_UPDATE
+	case UserDriftOrphaned:
+		event.OperationType = OPERATION_DELETE
+		// As for a deletion captured without its pre-image, the version following the published one
+		event.Sequence = drift.PublishedVersion + 1
+	}
+	if user != nil {
+		event.AfterChange = user
+		event.ModifiedBy = user.LastModifiedBy
+		event.Sequence = user.Version
+		event.ChangedFields = ChangedUserFields(nil, user)
+	}
+
+	if err := r.producer.SendMessage(event); err != nil {
+		return fmt.Errorf("failed to repair %s user %s: %w", drift.Kind, drift.UserId, err)
+	}
+	log.WithFields(log.Fields{"reconciliation_id": reconciliationId, "user_id": drift.UserId, "drift": drift.Kind}).
+		Info("user drift repaired")
+	drift.Repaired = true
+	return nil
+}
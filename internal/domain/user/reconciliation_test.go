@@ -0,0 +1,130 @@
+//go:build unit
+
+package domain_test
+
+import (
+	"context"
+	"errors"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"testing"
+	"time"
+
+	"github.com/flapenna/go-ddd-crud/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserReconciler_Reconcile(t *testing.T) {
+	readAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := readAt.Add(-time.Hour)
+	stored := []*domain.User{
+		{ID: "user-ok", Version: 2, UpdatedAt: before},
+		{ID: "user-missing", Version: 1, UpdatedAt: before},
+		{ID: "user-recreated", Version: 1, UpdatedAt: before},
+		{ID: "user-stale", Version: 3, UpdatedAt: before},
+		{ID: "user-in-flight", Version: 5, UpdatedAt: readAt.Add(time.Second)},
+	}
+	published := map[string]*domain.PublishedUser{
+		"user-ok":        {UserId: "user-ok", Version: 2},
+		"user-recreated": {UserId: "user-recreated", Version: 4, Deleted: true},
+		"user-stale":     {UserId: "user-stale", Version: 2},
+		"user-in-flight": {UserId: "user-in-flight", Version: 4},
+		"user-orphaned":  {UserId: "user-orphaned", Version: 7},
+		"user-deleted":   {UserId: "user-deleted", Version: 3, Deleted: true},
+	}
+	wantDrifts := []*domain.UserDrift{
+		{UserId: "user-missing", Kind: domain.UserDriftMissing, StoredVersion: 1},
+		{UserId: "user-recreated", Kind: domain.UserDriftMissing, StoredVersion: 1, PublishedVersion: 4},
+		{UserId: "user-stale", Kind: domain.UserDriftStale, StoredVersion: 3, PublishedVersion: 2},
+		{UserId: "user-orphaned", Kind: domain.UserDriftOrphaned, PublishedVersion: 7},
+	}
+
+	tests := []struct {
+		name       string
+		repair     bool
+		producer   error
+		wantEvents map[string]domain.OperationType
+		wantErr    error
+	}{
+		{
+			name:       "report only",
+			wantEvents: map[string]domain.OperationType{},
+		},
+		{
+			name:   "repaired",
+			repair: true,
+			wantEvents: map[string]domain.OperationType{
+				"user-missing":   domain.OPERATION_CREATE,
+				"user-recreated": domain.OPERATION_CREATE,
+				"user-stale":     domain.OPERATION_UPDATE,
+				"user-orphaned":  domain.OPERATION_DELETE,
+			},
+		},
+		{
+			name:     "repair failed",
+			repair:   true,
+			producer: errors.New("producer error"),
+			wantErr:  errors.New("failed to repair missing user user-missing: producer error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockRepo.On("ScanUsers", mock.Anything, &domain.ScanUsersQuery{}, mock.Anything).
+				Return(func(ctx context.Context, query *domain.ScanUsersQuery, fn func(*domain.User) error) error {
+					for _, user := range stored {
+						if err := fn(user); err != nil {
+							return err
+						}
+					}
+					return nil
+				}).Once()
+			events := make(map[string]*domain.UserEvent)
+			mockProducer.On("SendMessage", mock.AnythingOfType("*domain.UserEvent")).
+				Run(func(args mock.Arguments) {
+					event := args.Get(0).(*domain.UserEvent)
+					events[event.UserId] = event
+				}).Return(tt.producer)
+
+			report, err := domain.NewUserReconciler(mockRepo, mockProducer).Reconcile(context.TODO(), published, readAt, tt.repair)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+				assert.Nil(t, report)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 5, report.StoredUsers)
+			assert.Equal(t, 4, report.PublishedUsers)
+			assert.Equal(t, 1, report.InFlightUsers)
+			assert.Equal(t, 2, report.Count(domain.UserDriftMissing))
+			assert.Equal(t, 1, report.Count(domain.UserDriftStale))
+			assert.Equal(t, 1, report.Count(domain.UserDriftOrphaned))
+			assert.Len(t, report.Drifts, len(wantDrifts))
+			for i, drift := range report.Drifts {
+				assert.Equal(t, wantDrifts[i].UserId, drift.UserId)
+				assert.Equal(t, wantDrifts[i].Kind, drift.Kind)
+				assert.Equal(t, wantDrifts[i].StoredVersion, drift.StoredVersion)
+				assert.Equal(t, wantDrifts[i].PublishedVersion, drift.PublishedVersion)
+				assert.Equal(t, tt.repair, drift.Repaired)
+			}
+
+			assert.Len(t, events, len(tt.wantEvents))
+			for userId, operationType := range tt.wantEvents {
+				event := events[userId]
+				assert.Equal(t, operationType, event.OperationType, userId)
+				assert.Equal(t, report.Id, event.CorrelationId)
+			}
+			if tt.repair {
+				assert.Equal(t, int64(3), events["user-stale"].Sequence)
+				assert.Equal(t, stored[3], events["user-stale"].AfterChange)
+				assert.Equal(t, int64(8), events["user-orphaned"].Sequence)
+				assert.Nil(t, events["user-orphaned"].AfterChange)
+				assert.Equal(t, 4, report.Repaired())
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
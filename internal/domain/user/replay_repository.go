@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserReplayRepository stores the progress of the user replays, by replay id
+type UserReplayRepository interface {
+	GetUserReplay(ctx context.Context, id string) (*UserReplay, error)
+	// SaveUserReplay inserts or replaces the replay
+	SaveUserReplay(ctx context.Context, replay *UserReplay) error
+	// ClaimUserReplay atomically marks the oldest pending replay as running for owner, or a running
+	// replay not checkpointed since staleBefore, its owner being gone. It fails with
+	// ErrUserReplayNotFound when there is none.
+	ClaimUserReplay(ctx context.Context, owner string, staleBefore time.Time) (*UserReplay, error)
+	// CheckpointUserReplay replaces the replay as long as it is still owned by owner, and fails
+	// with ErrUserReplayTakenOver otherwise
+	CheckpointUserReplay(ctx context.Context, replay *UserReplay, owner string) error
+}
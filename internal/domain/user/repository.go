@@ -6,7 +6,10 @@ import (
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *User) error
+	GetUserById(ctx context.Context, id string) (*User, error)
 	UpdateUser(ctx context.Context, user *User) error
 	DeleteUserById(ctx context.Context, id string) error
 	ListUsers(ctx context.Context, request *ListUsersQueryRequest) (*ListUsersQueryResponse, error)
+	// ScanUsers calls fn with each user matching the query, in id order, and stops at the first error
+	ScanUsers(ctx context.Context, query *ScanUsersQuery, fn func(user *User) error) error
 }
@@ -2,33 +2,114 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 	"time"
 )
 
+// exportAuditPageSize is the page size used to collect the audit trail and the versions of a user data export
+const exportAuditPageSize = 100
+
+const (
+	// defaultReplayRate is the number of events a replay publishes per second, unless requested otherwise
+	defaultReplayRate = 100
+	// replayCheckpointInterval is the number of users replayed between two checkpoints
+	replayCheckpointInterval = 100
+	// replayLeaseDuration is how long a running replay is left to its owner since its last
+	// checkpoint, before another leader takes it over
+	replayLeaseDuration = time.Minute
+	// defaultReplayPollInterval is how often the leader looks for a pending replay
+	defaultReplayPollInterval = time.Second
+)
+
+// replayEventNamespace derives the ids of the replayed events, so that a resumed replay
+// publishes the users it had not checkpointed with the same ids
+var replayEventNamespace = uuid.MustParse("0b6f6d4e-57a4-4c55-9d0e-6b1f3c2a8e71")
+
 type UserService interface {
 	CreateUser(ctx context.Context, user *User) (*User, error)
 	UpdateUser(ctx context.Context, user *User) (*User, error)
 	DeleteUser(ctx context.Context, id string) error
 	ListUsers(ctx context.Context, request *ListUsersQueryRequest) (*ListUsersQueryResponse, error)
-	StartWatchingUsers(ctx context.Context)
+	ExportUserData(ctx context.Context, id string) (*UserDataExport, error)
+	ListUserAuditEntries(ctx context.Context, request *ListUserAuditEntriesQueryRequest) (*ListUserAuditEntriesQueryResponse, error)
+	GetUser(ctx context.Context, id string, asOf *time.Time) (*User, error)
+	ListUserVersions(ctx context.Context, request *ListUserVersionsQueryRequest) (*ListUserVersionsQueryResponse, error)
+	RevertUser(ctx context.Context, id string, version int64, expectedVersion int64) (*User, error)
+	ListDeadLetters(ctx context.Context, request *ListDeadLettersQueryRequest) (*ListDeadLettersQueryResponse, error)
+	GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error)
+	RedriveDeadLetter(ctx context.Context, id string) error
+	DiscardDeadLetter(ctx context.Context, id string) error
+	StartUserReplay(ctx context.Context, request *StartUserReplayRequest) (*UserReplay, error)
+	GetUserReplay(ctx context.Context, id string) (*UserReplay, error)
+	WatchUsers(ctx context.Context, request *WatchUsersRequest) (*UserEventSubscription, error)
+	StartWatchingUsers(ctx context.Context) <-chan struct{}
+	StartBroadcastingUsers(ctx context.Context, watcher UserWatcher) <-chan struct{}
+	StartReplayingUsers(ctx context.Context) <-chan struct{}
 }
 
 type service struct {
-	repo     UserRepository
-	producer UserProducer
-	watcher  UserWatcher
+	repo           UserRepository
+	auditRepo      UserAuditRepository
+	versionRepo    UserVersionRepository
+	deadLetterRepo UserDeadLetterRepository
+	replayRepo     UserReplayRepository
+	producer       UserProducer
+	watcher        UserWatcher
+	events         *UserEventBroadcaster
+	pipeline       *UserEventPipeline
+	opts           UserServiceOptions
+}
+
+// UserServiceOptions tunes how the user events are published
+type UserServiceOptions struct {
+	// SuppressTimestampOnlyEvents skips publishing the updates that only changed updated_at.
+	// They are still recorded in the audit log and the version history.
+	SuppressTimestampOnlyEvents bool
+	// WatchHistorySize is the number of published events kept for the watchers to resume from, 1000 by default
+	WatchHistorySize int
+	// WatchBufferSize is the number of events a watcher can fall behind before being disconnected, 100 by default
+	WatchBufferSize int
+	// Pipeline tunes the parallel processing of the events emitted by the watcher
+	Pipeline UserEventPipelineOptions
+	// InstanceId identifies this instance as the owner of the replays it runs, a random id by default
+	InstanceId string
+	// ReplayPollInterval is how often the pending replays are looked for, 1s by default
+	ReplayPollInterval time.Duration
 }
 
-func NewUserService(repo UserRepository, producer UserProducer, watcher UserWatcher) UserService {
-	return &service{repo: repo, producer: producer, watcher: watcher}
+func NewUserService(repo UserRepository, auditRepo UserAuditRepository, versionRepo UserVersionRepository,
+	deadLetterRepo UserDeadLetterRepository, replayRepo UserReplayRepository, producer UserProducer, watcher UserWatcher) UserService {
+	return NewUserServiceWithOptions(repo, auditRepo, versionRepo, deadLetterRepo, replayRepo, producer, watcher, UserServiceOptions{})
+}
+
+func NewUserServiceWithOptions(repo UserRepository, auditRepo UserAuditRepository, versionRepo UserVersionRepository,
+	deadLetterRepo UserDeadLetterRepository, replayRepo UserReplayRepository, producer UserProducer, watcher UserWatcher,
+	opts UserServiceOptions) UserService {
+	if opts.InstanceId == "" {
+		opts.InstanceId = uuid.NewString()
+	}
+	if opts.ReplayPollInterval <= 0 {
+		opts.ReplayPollInterval = defaultReplayPollInterval
+	}
+	s := &service{repo: repo, auditRepo: auditRepo, versionRepo: versionRepo, deadLetterRepo: deadLetterRepo,
+		replayRepo: replayRepo, producer: producer, watcher: watcher, opts: opts,
+		events: NewUserEventBroadcaster(opts.WatchHistorySize, opts.WatchBufferSize)}
+	s.pipeline = NewUserEventPipeline(s.processUserEvent, opts.Pipeline)
+	return s
 }
 
 func (s *service) CreateUser(ctx context.Context, user *User) (*User, error) {
 	user.ID = uuid.NewString()
 	user.CreatedAt = time.Now().UTC().Round(time.Millisecond)
 	user.UpdatedAt = user.CreatedAt
+	user.Version = 1
+	user.LastModifiedBy = ActorIdFromContext(ctx)
+	user.CorrelationId = CorrelationIdFromContext(ctx)
+	user.TraceParent = TraceParentFromContext(ctx)
 	err := s.repo.CreateUser(ctx, user)
 	if err != nil {
 		return nil, err
@@ -38,6 +119,9 @@ func (s *service) CreateUser(ctx context.Context, user *User) (*User, error) {
 
 func (s *service) UpdateUser(ctx context.Context, user *User) (*User, error) {
 	user.UpdatedAt = time.Now().UTC().Round(time.Millisecond)
+	user.LastModifiedBy = ActorIdFromContext(ctx)
+	user.CorrelationId = CorrelationIdFromContext(ctx)
+	user.TraceParent = TraceParentFromContext(ctx)
 	err := s.repo.UpdateUser(ctx, user)
 	if err != nil {
 		return nil, err
@@ -57,15 +141,559 @@ func (s *service) ListUsers(ctx context.Context, req *ListUsersQueryRequest) (*L
 	return users, nil
 }
 
-func (s *service) StartWatchingUsers(ctx context.Context) {
-	userEvents := s.watcher.WatchUsers(ctx)
+// ExportUserData returns the data subject export for the given user, with its version history
+// and audit trail. A deleted user is exported from its version history. Every export is
+// recorded in the audit log, and fails when it could not be.
+// Only the user themselves or an admin are allowed to request it.
+func (s *service) ExportUserData(ctx context.Context, id string) (*UserDataExport, error) {
+	if err := requireSelfOrAdmin(ctx, id); err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			log.WithFields(log.Fields{"actor_id": ActorIdFromContext(ctx), "user_id": id}).Warn("user data export denied")
+		}
+		return nil, err
+	}
+	actor := ActorFromContext(ctx)
+
+	user, err := s.repo.GetUserById(ctx, id)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, err
+	}
+	versions, err := s.listAllVersions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deleted := user == nil
+	if deleted {
+		// The versions are sorted from the latest, the last state of the user precedes its deletion
+		for _, version := range versions {
+			if version.User != nil {
+				user = version.User
+				break
+			}
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	auditEntries, err := s.listAllAuditEntries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	exportedAt := time.Now().UTC().Round(time.Millisecond)
+	err = s.auditRepo.AppendAuditEntry(ctx, &UserAuditEntry{
+		Id:            uuid.NewString(),
+		UserId:        id,
+		OperationType: OPERATION_EXPORT,
+		ModifiedBy:    actor.ID,
+		CorrelationId: CorrelationIdFromContext(ctx),
+		RecordedAt:    exportedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record the export in the audit log: %w", err)
+	}
+
+	log.WithFields(log.Fields{"actor_id": actor.ID, "actor_role": actor.Role, "user_id": id, "deleted": deleted}).
+		Info("user data exported")
+
+	return &UserDataExport{
+		User:         user,
+		Deleted:      deleted,
+		Versions:     versions,
+		AuditEntries: auditEntries,
+		ExportedAt:   exportedAt,
+	}, nil
+}
+
+func (s *service) listAllVersions(ctx context.Context, userId string) ([]*UserVersion, error) {
+	versions := make([]*UserVersion, 0)
+	for page := uint32(0); ; page++ {
+		res, err := s.versionRepo.ListUserVersions(ctx, &ListUserVersionsQueryRequest{
+			UserId:   userId,
+			Page:     page,
+			PageSize: exportAuditPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, res.Results...)
+		if len(res.Results) < exportAuditPageSize || uint32(len(versions)) >= res.TotalCount {
+			return versions, nil
+		}
+	}
+}
+
+func (s *service) listAllAuditEntries(ctx context.Context, userId string) ([]*UserAuditEntry, error) {
+	entries := make([]*UserAuditEntry, 0)
+	for page := uint32(0); ; page++ {
+		res, err := s.auditRepo.ListAuditEntries(ctx, &ListUserAuditEntriesQueryRequest{
+			Page:     page,
+			PageSize: exportAuditPageSize,
+			UserId:   &userId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res.Results...)
+		if len(res.Results) < exportAuditPageSize || uint32(len(entries)) >= res.TotalCount {
+			return entries, nil
+		}
+	}
+}
+
+// ListUserAuditEntries returns the audit trail. Admins can query every entry,
+// other actors only the entries of their own user.
+func (s *service) ListUserAuditEntries(ctx context.Context, req *ListUserAuditEntriesQueryRequest) (*ListUserAuditEntriesQueryResponse, error) {
+	actor := ActorFromContext(ctx)
+	if actor == nil || actor.ID == "" {
+		return nil, ErrUnauthenticated
+	}
+	if !actor.IsAdmin() && (req.UserId == nil || *req.UserId != actor.ID) {
+		return nil, ErrPermissionDenied
+	}
+	return s.auditRepo.ListAuditEntries(ctx, req)
+}
+
+// GetUser returns the current state of the user, or the state it had at asOf when provided.
+// The past states are only returned to the user themselves or to an admin.
+func (s *service) GetUser(ctx context.Context, id string, asOf *time.Time) (*User, error) {
+	if asOf == nil {
+		return s.repo.GetUserById(ctx, id)
+	}
+	if err := requireSelfOrAdmin(ctx, id); err != nil {
+		return nil, err
+	}
+
+	version, err := s.versionRepo.GetUserVersionAt(ctx, id, *asOf)
+	if errors.Is(err, ErrUserVersionNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if version.Deleted {
+		return nil, ErrUserNotFound
+	}
+	return version.User, nil
+}
+
+// ListUserVersions returns the version history of a user, to the user themselves or to an admin
+func (s *service) ListUserVersions(ctx context.Context, req *ListUserVersionsQueryRequest) (*ListUserVersionsQueryResponse, error) {
+	if err := requireSelfOrAdmin(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	return s.versionRepo.ListUserVersions(ctx, req)
+}
+
+// RevertUser writes the state of the given version back as a new update, on behalf of an admin.
+// The update only succeeds if the user is still at expectedVersion.
+func (s *service) RevertUser(ctx context.Context, id string, version int64, expectedVersion int64) (*User, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	userVersion, err := s.versionRepo.GetUserVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if userVersion.Deleted || userVersion.User == nil {
+		return nil, ErrUserVersionNotFound
+	}
+
+	user := &User{
+		ID:        id,
+		FirstName: userVersion.User.FirstName,
+		LastName:  userVersion.User.LastName,
+		Email:     userVersion.User.Email,
+		Country:   userVersion.User.Country,
+		Nickname:  userVersion.User.Nickname,
+		Version:   expectedVersion,
+	}
+	return s.UpdateUser(ctx, user)
+}
+
+func (s *service) ListDeadLetters(ctx context.Context, req *ListDeadLettersQueryRequest) (*ListDeadLettersQueryResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.deadLetterRepo.ListDeadLetters(ctx, req)
+}
+
+func (s *service) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.deadLetterRepo.GetDeadLetter(ctx, id)
+}
+
+// RedriveDeadLetter publishes the dead-lettered event again, and removes it once published.
+// A failed attempt is counted on the dead letter and returned.
+func (s *service) RedriveDeadLetter(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	deadLetter, err := s.deadLetterRepo.GetDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.producer.SendMessage(deadLetter.Event); err != nil {
+		log.Errorf("Error redriving user event %s: %v", id, err)
+		if recordErr := s.deadLetterRepo.RecordDeadLetter(ctx, deadLetter.Event, err, time.Now().UTC().Round(time.Millisecond)); recordErr != nil {
+			log.Errorf("Error recording failed redrive of user event %s: %v", id, recordErr)
+		}
+		return err
+	}
+
+	log.WithFields(log.Fields{"actor_id": ActorIdFromContext(ctx), "event_id": id}).Info("dead letter redriven")
+	return s.deadLetterRepo.DeleteDeadLetter(ctx, id)
+}
+
+func (s *service) DiscardDeadLetter(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.deadLetterRepo.DeleteDeadLetter(ctx, id); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"actor_id": ActorIdFromContext(ctx), "event_id": id}).Warn("dead letter discarded")
+	return nil
+}
+
+// StartUserReplay queues the publishing of a synthetic creation of every user matching the request,
+// for the leader to run it, see StartReplayingUsers. Starting an interrupted or failed replay again
+// resumes it from its last checkpoint, with the filters it was first started with.
+func (s *service) StartUserReplay(ctx context.Context, req *StartUserReplayRequest) (*UserReplay, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
 
+	now := time.Now().UTC().Round(time.Millisecond)
+	replay, err := s.replayRepo.GetUserReplay(ctx, req.Id)
+	switch {
+	case errors.Is(err, ErrUserReplayNotFound):
+		replay = &UserReplay{
+			Id: req.Id,
+			Query: ScanUsersQuery{
+				Country:   req.Country,
+				FirstName: req.FirstName,
+				LastName:  req.LastName,
+				Nickname:  req.Nickname,
+				Email:     req.Email,
+			},
+			RatePerSecond: defaultReplayRate,
+			StartedAt:     now,
+		}
+	case err != nil:
+		return nil, err
+	case replay.State == UserReplayStateCompleted:
+		return nil, ErrUserReplayCompleted
+	case replay.State == UserReplayStatePending:
+		return nil, ErrUserReplayRunning
+	case replay.State == UserReplayStateRunning && replay.UpdatedAt.After(now.Add(-replayLeaseDuration)):
+		return nil, ErrUserReplayRunning
+	}
+	if req.RatePerSecond > 0 {
+		replay.RatePerSecond = req.RatePerSecond
+	}
+	replay.State = UserReplayStatePending
+	replay.Owner = ""
+	replay.Error = ""
+	replay.StartedBy = ActorIdFromContext(ctx)
+	replay.UpdatedAt = now
+	if err := s.replayRepo.SaveUserReplay(ctx, replay); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{"actor_id": replay.StartedBy, "replay_id": replay.Id, "after_id": replay.Query.AfterId}).
+		Info("user replay started")
+	return replay, nil
+}
+
+func (s *service) GetUserReplay(ctx context.Context, id string) (*UserReplay, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.replayRepo.GetUserReplay(ctx, id)
+}
+
+// StartReplayingUsers claims the pending replays, and those left by a gone owner, one at a time
+// until ctx is done. The replayed users are published through the event pipeline, ordered with
+// the events of the watcher, so it only runs on the leader, along with StartWatchingUsers.
+// A replay interrupted by the end of the term is left pending for the next leader.
+func (s *service) StartReplayingUsers(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
 	go func() {
-		for userEvent := range userEvents {
-			err := s.producer.SendMessage(userEvent)
-			if err != nil {
-				log.Errorf("Error sending user event: %v", err)
+		defer close(done)
+		ticker := time.NewTicker(s.opts.ReplayPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			staleBefore := time.Now().UTC().Add(-replayLeaseDuration)
+			replay, err := s.replayRepo.ClaimUserReplay(ctx, s.opts.InstanceId, staleBefore)
+			switch {
+			case errors.Is(err, ErrUserReplayNotFound), ctx.Err() != nil:
+			case err != nil:
+				log.Errorf("Error claiming user replay: %v", err)
+			default:
+				s.replayUsers(ctx, replay)
 			}
 		}
 	}()
+	return done
+}
+
+// replayUsers publishes the users at the rate of the replay, checkpointing its progress
+// every replayCheckpointInterval users, at least every quarter of replayLeaseDuration, and once done
+func (s *service) replayUsers(ctx context.Context, replay *UserReplay) {
+	log.WithFields(log.Fields{"replay_id": replay.Id, "after_id": replay.Query.AfterId}).Info("user replay claimed")
+	limiter := rate.NewLimiter(rate.Limit(replay.RatePerSecond), 1)
+	query := replay.Query
+	checkpointed := time.Now()
+	err := s.repo.ScanUsers(ctx, &query, func(user *User) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if err := s.pipeline.Submit(ctx, replayEvent(replay.Id, user)); err != nil {
+			return fmt.Errorf("failed to publish user %s: %w", user.ID, err)
+		}
+		replay.Query.AfterId = user.ID
+		replay.ReplayedCount++
+		if replay.ReplayedCount%replayCheckpointInterval == 0 || time.Since(checkpointed) >= replayLeaseDuration/4 {
+			checkpointed = time.Now()
+			return s.checkpointReplay(ctx, replay, replay.Owner)
+		}
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, ErrUserReplayTakenOver):
+		log.Warnf("User replay %s was taken over after user %q.", replay.Id, replay.Query.AfterId)
+		return
+	case ctx.Err() != nil, errors.Is(err, ErrUserEventsStopped):
+		log.Infof("User replay %s interrupted after user %q.", replay.Id, replay.Query.AfterId)
+		replay.State = UserReplayStatePending
+		// The replay is released although the term is over
+		ctx = context.WithoutCancel(ctx)
+	case err != nil:
+		log.Errorf("User replay %s failed after user %q: %v", replay.Id, replay.Query.AfterId, err)
+		replay.State = UserReplayStateFailed
+		replay.Error = err.Error()
+	default:
+		log.Infof("User replay %s completed, %d users replayed.", replay.Id, replay.ReplayedCount)
+		replay.State = UserReplayStateCompleted
+	}
+	owner := replay.Owner
+	replay.Owner = ""
+	if err := s.checkpointReplay(ctx, replay, owner); err != nil {
+		log.Errorf("Error saving user replay %s: %v", replay.Id, err)
+	}
+}
+
+// checkpointReplay saves the progress of the replay, unless owner no longer owns it
+func (s *service) checkpointReplay(ctx context.Context, replay *UserReplay, owner string) error {
+	replay.UpdatedAt = time.Now().UTC().Round(time.Millisecond)
+	return s.replayRepo.CheckpointUserReplay(ctx, replay, owner)
+}
+
+// replayEvent is the synthetic creation of the current state of the user
+func replayEvent(replayId string, user *User) *UserEvent {
+	return &UserEvent{
+		Id:            uuid.NewSHA1(replayEventNamespace, []byte(fmt.Sprintf("%s:%s:%d", replayId, user.ID, user.Version))).String(),
+		UserId:        user.ID,
+		AfterChange:   user,
+		OperationType: OPERATION_CREATE,
+		ModifiedBy:    user.LastModifiedBy,
+		CorrelationId: replayId,
+		Sequence:      user.Version,
+		OccurredAt:    user.UpdatedAt,
+		ChangedFields: ChangedUserFields(nil, user),
+		Replayed:      true,
+	}
+}
+
+// WatchUsers streams the user events matching the request, see StartBroadcastingUsers. Admins
+// can watch every user, other actors only their own user.
+func (s *service) WatchUsers(ctx context.Context, req *WatchUsersRequest) (*UserEventSubscription, error) {
+	actor := ActorFromContext(ctx)
+	if actor == nil || actor.ID == "" {
+		return nil, ErrUnauthenticated
+	}
+	if !actor.IsAdmin() && (len(req.UserIds) != 1 || req.UserIds[0] != actor.ID) {
+		return nil, ErrPermissionDenied
+	}
+	return s.events.Subscribe(req)
+}
+
+func requireAdmin(ctx context.Context) error {
+	actor := ActorFromContext(ctx)
+	if actor == nil || actor.ID == "" {
+		return ErrUnauthenticated
+	}
+	if !actor.IsAdmin() {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// requireSelfOrAdmin only lets the user userId themselves or an admin through
+func requireSelfOrAdmin(ctx context.Context, userId string) error {
+	actor := ActorFromContext(ctx)
+	if actor == nil || actor.ID == "" {
+		return ErrUnauthenticated
+	}
+	if actor.ID != userId && !actor.IsAdmin() {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// StartWatchingUsers publishes the events of the watcher until ctx is done. The returned channel
+// is closed once the in-flight events are processed, for the producer to be flushed after, and
+// the watching can be started again, e.g. at the next term of a leader.
+func (s *service) StartWatchingUsers(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.pipeline.Run(ctx, s.watcher)
+		log.Warn("User watcher stopped, user events are no longer published.")
+	}()
+	return done
+}
+
+// StartBroadcastingUsers streams the events of watcher to the WatchUsers subscriptions until ctx
+// is done. Every instance broadcasts, leader or not, from a watcher of its own that needs no
+// acknowledgement and emits the events with the ids they are published with, for a subscriber
+// to resume on any instance. The subscriptions end with ErrUserEventsStopped once it stops.
+func (s *service) StartBroadcastingUsers(ctx context.Context, watcher UserWatcher) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for userEvent := range watcher.WatchUsers(ctx) {
+			if s.opts.SuppressTimestampOnlyEvents && IsTimestampOnlyChange(userEvent) {
+				continue
+			}
+			s.events.Broadcast(userEvent)
+		}
+		s.events.Close()
+		log.Warn("User event feed stopped, user events are no longer streamed to the watchers.")
+	}()
+	return done
+}
+
+func (s *service) UserEventPipelineStats() UserEventPipelineStats {
+	return s.pipeline.UserEventPipelineStats()
+}
+
+// processUserEvent records the event in the audit log and the version history, then publishes it.
+// The returned error is reported to the watcher, for the event to be emitted again: the audit entry
+// and the version are keyed by the event id and the user version, so recording them again is a no-op.
+// A replayed user is only published, it is no change of the user.
+func (s *service) processUserEvent(ctx context.Context, userEvent *UserEvent) error {
+	if userEvent.Replayed {
+		return s.publish(ctx, userEvent)
+	}
+	recordedAt := time.Now().UTC().Round(time.Millisecond)
+	err := s.auditRepo.AppendAuditEntry(ctx, auditEntryFromEvent(userEvent, recordedAt))
+	if err != nil {
+		log.Errorf("Error recording user event %s in audit log: %v", userEvent.Id, err)
+		return err
+	}
+	if version := versionFromEvent(userEvent, recordedAt); version != nil {
+		err = s.versionRepo.AppendUserVersion(ctx, version)
+		if err != nil {
+			log.Errorf("Error recording version %d of user %s: %v", version.Version, version.UserId, err)
+			return err
+		}
+	}
+	if s.opts.SuppressTimestampOnlyEvents && IsTimestampOnlyChange(userEvent) {
+		log.Debugf("Suppressing timestamp only user event %s.", userEvent.Id)
+		return nil
+	}
+	return s.publish(ctx, userEvent)
+}
+
+// publish sends the event, dead-lettering it when it could not be
+func (s *service) publish(ctx context.Context, userEvent *UserEvent) error {
+	err := s.producer.SendMessage(userEvent)
+	if err != nil {
+		log.Errorf("Error sending user event: %v", err)
+		return s.deadLetter(ctx, userEvent, err)
+	}
+	return nil
+}
+
+// deadLetter stores the event that could not be published. The event is only lost,
+// and has to be emitted again by the watcher, when it cannot be stored either.
+func (s *service) deadLetter(ctx context.Context, event *UserEvent, failure error) error {
+	err := s.deadLetterRepo.RecordDeadLetter(ctx, event, failure, time.Now().UTC().Round(time.Millisecond))
+	if err != nil {
+		log.Errorf("Error dead-lettering user event %s: %v", event.Id, err)
+		return failure
+	}
+	log.Warnf("User event %s dead-lettered: %v", event.Id, failure)
+	return nil
+}
+
+// acknowledge reports the processing outcome to the watcher, when it needs it
+func acknowledge(ctx context.Context, watcher UserWatcher, event *UserEvent, err error) {
+	acknowledger, ok := watcher.(UserEventAcknowledger)
+	if !ok {
+		return
+	}
+	if err != nil {
+		acknowledger.NackUserEvent(ctx, event, err)
+		return
+	}
+	if err := acknowledger.AckUserEvent(ctx, event); err != nil {
+		log.Errorf("Error acknowledging user event %s: %v", event.Id, err)
+	}
+}
+
+func auditEntryFromEvent(event *UserEvent, recordedAt time.Time) *UserAuditEntry {
+	return &UserAuditEntry{
+		Id:            event.Id,
+		UserId:        event.UserId,
+		BeforeChange:  event.BeforeChange,
+		AfterChange:   event.AfterChange,
+		OperationType: event.OperationType,
+		ModifiedBy:    event.ModifiedBy,
+		CorrelationId: event.CorrelationId,
+		RecordedAt:    recordedAt,
+	}
+}
+
+// versionFromEvent builds the user version resulting from the event: the post-image
+// for creations and updates, a deleted version following the pre-image for deletions
+func versionFromEvent(event *UserEvent, recordedAt time.Time) *UserVersion {
+	switch {
+	case event.AfterChange != nil:
+		validFrom := event.AfterChange.UpdatedAt
+		if validFrom.IsZero() {
+			validFrom = recordedAt
+		}
+		return &UserVersion{
+			UserId:        event.UserId,
+			Version:       event.AfterChange.Version,
+			User:          event.AfterChange,
+			ValidFrom:     validFrom,
+			ModifiedBy:    event.ModifiedBy,
+			CorrelationId: event.CorrelationId,
+		}
+	case event.BeforeChange != nil:
+		return &UserVersion{
+			UserId:        event.UserId,
+			Version:       event.BeforeChange.Version + 1,
+			Deleted:       true,
+			ValidFrom:     recordedAt,
+			ModifiedBy:    event.ModifiedBy,
+			CorrelationId: event.CorrelationId,
+		}
+	default:
+		return nil
+	}
 }
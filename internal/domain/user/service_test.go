@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_CreateUser(t *testing.T) {
@@ -54,12 +56,17 @@ func TestService_CreateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
 			mockProducer := new(mocks.MockUserProducer)
 			mockWatcher := new(mocks.MockUserWatcher)
-			service := domain.NewUserService(mockRepo, mockProducer, mockWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
 			tt.setupMock(mockRepo)
 
-			ctx := context.TODO()
+			ctx := domain.ContextWithActor(context.TODO(), &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin})
+			ctx = domain.ContextWithCorrelationId(ctx, "request-1")
 			createdUser, err := service.CreateUser(ctx, tt.req)
 
 			if tt.wantErr {
@@ -70,6 +77,9 @@ func TestService_CreateUser(t *testing.T) {
 				assert.NotEmpty(t, createdUser.ID)
 				assert.WithinDuration(t, time.Now(), createdUser.CreatedAt, time.Second)
 				assert.WithinDuration(t, createdUser.CreatedAt, createdUser.UpdatedAt, time.Second)
+				assert.Equal(t, int64(1), createdUser.Version)
+				assert.Equal(t, "admin-1", createdUser.LastModifiedBy)
+				assert.Equal(t, "request-1", createdUser.CorrelationId)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -118,12 +128,17 @@ func TestService_UpdateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
 			mockProducer := new(mocks.MockUserProducer)
 			mockWatcher := new(mocks.MockUserWatcher)
-			service := domain.NewUserService(mockRepo, mockProducer, mockWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
 			tt.setupMock(mockRepo)
 
-			ctx := context.TODO()
+			ctx := domain.ContextWithActor(context.TODO(), &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin})
+			ctx = domain.ContextWithCorrelationId(ctx, "request-1")
 			updatedUser, err := service.UpdateUser(ctx, tt.req)
 
 			if tt.wantErr {
@@ -133,6 +148,8 @@ func TestService_UpdateUser(t *testing.T) {
 				assert.NotNil(t, updatedUser)
 				assert.Equal(t, tt.req.ID, updatedUser.ID)
 				assert.WithinDuration(t, time.Now(), updatedUser.UpdatedAt, time.Second)
+				assert.Equal(t, "admin-1", updatedUser.LastModifiedBy)
+				assert.Equal(t, "request-1", updatedUser.CorrelationId)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -168,9 +185,13 @@ func TestService_DeleteUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
 			mockProducer := new(mocks.MockUserProducer)
 			mockWatcher := new(mocks.MockUserWatcher)
-			service := domain.NewUserService(mockRepo, mockProducer, mockWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
 			tt.setupMock(mockRepo)
 
 			ctx := context.TODO()
@@ -237,9 +258,13 @@ func TestService_ListUsers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
 			mockProducer := new(mocks.MockUserProducer)
 			mockWatcher := new(mocks.MockUserWatcher)
-			service := domain.NewUserService(mockRepo, mockProducer, mockWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
 			tt.setupMock(mockRepo)
 
 			ctx := context.TODO()
@@ -259,3 +284,1265 @@ func TestService_ListUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ExportUserData(t *testing.T) {
+	user := &domain.User{
+		ID:        "user-123",
+		FirstName: "Federico",
+		Email:     "flapenna@email.com",
+	}
+	auditEntries := []*domain.UserAuditEntry{
+		{Id: "event-1", UserId: "user-123", OperationType: domain.OPERATION_CREATE, ModifiedBy: "user-123"},
+	}
+	versions := []*domain.UserVersion{
+		{UserId: "user-123", Version: 1, User: user, ModifiedBy: "user-123"},
+	}
+	deletedVersions := []*domain.UserVersion{
+		{UserId: "user-123", Version: 2, Deleted: true, ModifiedBy: "admin-1"},
+		{UserId: "user-123", Version: 1, User: user, ModifiedBy: "user-123"},
+	}
+	auditQuery := mock.MatchedBy(func(req *domain.ListUserAuditEntriesQueryRequest) bool {
+		return req.UserId != nil && *req.UserId == "user-123" && req.Page == 0
+	})
+	versionQuery := mock.MatchedBy(func(req *domain.ListUserVersionsQueryRequest) bool {
+		return req.UserId == "user-123" && req.Page == 0
+	})
+	// exportEntry matches the audit entry recording the export by the given actor
+	exportEntry := func(actorId string) interface{} {
+		return mock.MatchedBy(func(entry *domain.UserAuditEntry) bool {
+			return entry.Id != "" && entry.UserId == "user-123" && entry.OperationType == domain.OPERATION_EXPORT &&
+				entry.ModifiedBy == actorId && !entry.RecordedAt.IsZero()
+		})
+	}
+	tests := []struct {
+		name         string
+		setupMock    func(repository *mocks.MockUserRepository, auditRepository *mocks.MockUserAuditRepository, versionRepository *mocks.MockUserVersionRepository)
+		actor        *domain.Actor
+		userID       string
+		wantVersions []*domain.UserVersion
+		wantDeleted  bool
+		wantErr      error
+	}{
+		{
+			name: "user exports own data",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(user, nil)
+				mockVersionRepo.On("ListUserVersions", mock.Anything, versionQuery).
+					Return(&domain.ListUserVersionsQueryResponse{TotalCount: 1, Results: versions}, nil)
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, auditQuery).
+					Return(&domain.ListUserAuditEntriesQueryResponse{TotalCount: 1, Results: auditEntries}, nil)
+				mockAuditRepo.On("AppendAuditEntry", mock.Anything, exportEntry("user-123")).Return(nil)
+			},
+			actor:        &domain.Actor{ID: "user-123"},
+			userID:       "user-123",
+			wantVersions: versions,
+		},
+		{
+			name: "admin exports another user data",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(user, nil)
+				mockVersionRepo.On("ListUserVersions", mock.Anything, versionQuery).
+					Return(&domain.ListUserVersionsQueryResponse{TotalCount: 1, Results: versions}, nil)
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, auditQuery).
+					Return(&domain.ListUserAuditEntriesQueryResponse{TotalCount: 1, Results: auditEntries}, nil)
+				mockAuditRepo.On("AppendAuditEntry", mock.Anything, exportEntry("admin-1")).Return(nil)
+			},
+			actor:        &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin},
+			userID:       "user-123",
+			wantVersions: versions,
+		},
+		{
+			name: "deleted user exported from its versions",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(nil, domain.ErrUserNotFound)
+				mockVersionRepo.On("ListUserVersions", mock.Anything, versionQuery).
+					Return(&domain.ListUserVersionsQueryResponse{TotalCount: 2, Results: deletedVersions}, nil)
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, auditQuery).
+					Return(&domain.ListUserAuditEntriesQueryResponse{TotalCount: 1, Results: auditEntries}, nil)
+				mockAuditRepo.On("AppendAuditEntry", mock.Anything, exportEntry("admin-1")).Return(nil)
+			},
+			actor:        &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin},
+			userID:       "user-123",
+			wantVersions: deletedVersions,
+			wantDeleted:  true,
+		},
+		{
+			name: "missing actor",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+			},
+			actor:   nil,
+			userID:  "user-123",
+			wantErr: domain.ErrUnauthenticated,
+		},
+		{
+			name: "another user is denied",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+			},
+			actor:   &domain.Actor{ID: "user-456"},
+			userID:  "user-123",
+			wantErr: domain.ErrPermissionDenied,
+		},
+		{
+			name: "user not found",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(nil, domain.ErrUserNotFound)
+				mockVersionRepo.On("ListUserVersions", mock.Anything, versionQuery).
+					Return(&domain.ListUserVersionsQueryResponse{Results: []*domain.UserVersion{}}, nil)
+			},
+			actor:   &domain.Actor{ID: "user-123"},
+			userID:  "user-123",
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name: "audit repository error",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(user, nil)
+				mockVersionRepo.On("ListUserVersions", mock.Anything, versionQuery).
+					Return(&domain.ListUserVersionsQueryResponse{TotalCount: 1, Results: versions}, nil)
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, auditQuery).Return(nil, errors.New("repository error"))
+			},
+			actor:   &domain.Actor{ID: "user-123"},
+			userID:  "user-123",
+			wantErr: errors.New("repository error"),
+		},
+		{
+			name: "export not recorded",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockAuditRepo *mocks.MockUserAuditRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(user, nil)
+				mockVersionRepo.On("ListUserVersions", mock.Anything, versionQuery).
+					Return(&domain.ListUserVersionsQueryResponse{TotalCount: 1, Results: versions}, nil)
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, auditQuery).
+					Return(&domain.ListUserAuditEntriesQueryResponse{TotalCount: 1, Results: auditEntries}, nil)
+				mockAuditRepo.On("AppendAuditEntry", mock.Anything, exportEntry("user-123")).Return(errors.New("repository error"))
+			},
+			actor:   &domain.Actor{ID: "user-123"},
+			userID:  "user-123",
+			wantErr: errors.New("failed to record the export in the audit log: repository error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+			tt.setupMock(mockRepo, mockAuditRepo, mockVersionRepo)
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			res, err := service.ExportUserData(ctx, tt.userID)
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				assert.Nil(t, res)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, user, res.User)
+				assert.Equal(t, tt.wantDeleted, res.Deleted)
+				assert.Equal(t, tt.wantVersions, res.Versions)
+				assert.Equal(t, auditEntries, res.AuditEntries)
+				assert.WithinDuration(t, time.Now(), res.ExportedAt, time.Second)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockAuditRepo.AssertExpectations(t)
+			mockVersionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ListUserAuditEntries(t *testing.T) {
+	userID := "user-123"
+	otherUserID := "user-456"
+	res := &domain.ListUserAuditEntriesQueryResponse{
+		Page:       0,
+		PageSize:   10,
+		TotalCount: 1,
+		Results: []*domain.UserAuditEntry{
+			{Id: "event-1", UserId: userID, OperationType: domain.OPERATION_UPDATE, ModifiedBy: "admin-1"},
+		},
+	}
+	tests := []struct {
+		name      string
+		setupMock func(auditRepository *mocks.MockUserAuditRepository)
+		actor     *domain.Actor
+		req       *domain.ListUserAuditEntriesQueryRequest
+		wantRes   *domain.ListUserAuditEntriesQueryResponse
+		wantErr   error
+	}{
+		{
+			name: "admin lists every entry",
+			setupMock: func(mockAuditRepo *mocks.MockUserAuditRepository) {
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, mock.AnythingOfType("*domain.ListUserAuditEntriesQueryRequest")).Return(res, nil)
+			},
+			actor:   &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin},
+			req:     &domain.ListUserAuditEntriesQueryRequest{PageSize: 10},
+			wantRes: res,
+			wantErr: nil,
+		},
+		{
+			name: "user lists own entries",
+			setupMock: func(mockAuditRepo *mocks.MockUserAuditRepository) {
+				mockAuditRepo.On("ListAuditEntries", mock.Anything, mock.AnythingOfType("*domain.ListUserAuditEntriesQueryRequest")).Return(res, nil)
+			},
+			actor:   &domain.Actor{ID: userID},
+			req:     &domain.ListUserAuditEntriesQueryRequest{PageSize: 10, UserId: &userID},
+			wantRes: res,
+			wantErr: nil,
+		},
+		{
+			name:      "user lists entries of another user",
+			setupMock: func(mockAuditRepo *mocks.MockUserAuditRepository) {},
+			actor:     &domain.Actor{ID: userID},
+			req:       &domain.ListUserAuditEntriesQueryRequest{PageSize: 10, UserId: &otherUserID},
+			wantRes:   nil,
+			wantErr:   domain.ErrPermissionDenied,
+		},
+		{
+			name:      "user lists every entry",
+			setupMock: func(mockAuditRepo *mocks.MockUserAuditRepository) {},
+			actor:     &domain.Actor{ID: userID},
+			req:       &domain.ListUserAuditEntriesQueryRequest{PageSize: 10},
+			wantRes:   nil,
+			wantErr:   domain.ErrPermissionDenied,
+		},
+		{
+			name:      "missing actor",
+			setupMock: func(mockAuditRepo *mocks.MockUserAuditRepository) {},
+			actor:     nil,
+			req:       &domain.ListUserAuditEntriesQueryRequest{PageSize: 10},
+			wantRes:   nil,
+			wantErr:   domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+			tt.setupMock(mockAuditRepo)
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			got, err := service.ListUserAuditEntries(ctx, tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, got)
+			}
+
+			mockAuditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_StartWatchingUsers(t *testing.T) {
+	event := &domain.UserEvent{
+		Id:            "event-1",
+		UserId:        "user-123",
+		AfterChange:   &domain.User{ID: "user-123", Version: 1, UpdatedAt: time.Now().UTC()},
+		OperationType: domain.OPERATION_CREATE,
+		ModifiedBy:    "admin-1",
+		CorrelationId: "request-1",
+	}
+
+	mockRepo := new(mocks.MockUserRepository)
+	mockAuditRepo := new(mocks.MockUserAuditRepository)
+	mockVersionRepo := new(mocks.MockUserVersionRepository)
+	mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+	mockReplayRepo := new(mocks.MockUserReplayRepository)
+	mockProducer := new(mocks.MockUserProducer)
+	mockWatcher := new(mocks.MockUserWatcher)
+	service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+
+	events := make(chan *domain.UserEvent, 1)
+	events <- event
+	close(events)
+
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+	mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.MatchedBy(func(entry *domain.UserAuditEntry) bool {
+		return entry.Id == event.Id && entry.UserId == event.UserId && entry.ModifiedBy == "admin-1" &&
+			entry.CorrelationId == "request-1" && !entry.RecordedAt.IsZero()
+	})).Return(nil)
+	mockVersionRepo.On("AppendUserVersion", mock.Anything, &domain.UserVersion{
+		UserId:        "user-123",
+		Version:       1,
+		User:          event.AfterChange,
+		ValidFrom:     event.AfterChange.UpdatedAt,
+		ModifiedBy:    "admin-1",
+		CorrelationId: "request-1",
+	}).Return(nil)
+	mockProducer.On("SendMessage", event).Return(nil)
+
+	// Done once the events of the closed channel are processed
+	done := service.StartWatchingUsers(context.TODO())
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the event to be sent")
+	}
+
+	mockWatcher.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+	mockVersionRepo.AssertExpectations(t)
+	mockProducer.AssertExpectations(t)
+}
+
+func TestService_StartWatchingUsers_Delete(t *testing.T) {
+	event := &domain.UserEvent{
+		Id:            "event-2",
+		UserId:        "user-123",
+		BeforeChange:  &domain.User{ID: "user-123", Version: 3},
+		OperationType: domain.OPERATION_DELETE,
+		ModifiedBy:    "admin-1",
+	}
+
+	mockRepo := new(mocks.MockUserRepository)
+	mockAuditRepo := new(mocks.MockUserAuditRepository)
+	mockVersionRepo := new(mocks.MockUserVersionRepository)
+	mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+	mockReplayRepo := new(mocks.MockUserReplayRepository)
+	mockProducer := new(mocks.MockUserProducer)
+	mockWatcher := new(mocks.MockUserWatcher)
+	service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+
+	events := make(chan *domain.UserEvent, 1)
+	events <- event
+	close(events)
+
+	sent := make(chan struct{})
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+	mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.AnythingOfType("*domain.UserAuditEntry")).Return(nil)
+	mockVersionRepo.On("AppendUserVersion", mock.Anything, mock.MatchedBy(func(version *domain.UserVersion) bool {
+		return version.UserId == "user-123" && version.Version == 4 && version.Deleted && version.User == nil &&
+			!version.ValidFrom.IsZero()
+	})).Return(nil)
+	mockProducer.On("SendMessage", event).Return(nil).Run(func(args mock.Arguments) { close(sent) })
+
+	service.StartWatchingUsers(context.TODO())
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the event to be sent")
+	}
+
+	mockVersionRepo.AssertExpectations(t)
+}
+
+func TestService_GetUser(t *testing.T) {
+	user := &domain.User{ID: "user-123", FirstName: "Federico", Version: 2}
+	asOf := time.Now().Add(-24 * time.Hour)
+	self := &domain.Actor{ID: "user-123"}
+	tests := []struct {
+		name      string
+		setupMock func(repository *mocks.MockUserRepository, versionRepository *mocks.MockUserVersionRepository)
+		actor     *domain.Actor
+		asOf      *time.Time
+		wantRes   *domain.User
+		wantErr   error
+	}{
+		{
+			name: "current user",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockRepo.On("GetUserById", mock.Anything, "user-123").Return(user, nil)
+			},
+			asOf:    nil,
+			wantRes: user,
+			wantErr: nil,
+		},
+		{
+			name: "user as of a past time",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersionAt", mock.Anything, "user-123", asOf).
+					Return(&domain.UserVersion{UserId: "user-123", Version: 2, User: user}, nil)
+			},
+			actor:   self,
+			asOf:    &asOf,
+			wantRes: user,
+			wantErr: nil,
+		},
+		{
+			name: "user deleted at that time",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersionAt", mock.Anything, "user-123", asOf).
+					Return(&domain.UserVersion{UserId: "user-123", Version: 3, Deleted: true}, nil)
+			},
+			actor:   &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin},
+			asOf:    &asOf,
+			wantRes: nil,
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name: "user not existing yet at that time",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersionAt", mock.Anything, "user-123", asOf).Return(nil, domain.ErrUserVersionNotFound)
+			},
+			actor:   self,
+			asOf:    &asOf,
+			wantRes: nil,
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name:      "past state of another user",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {},
+			actor:     &domain.Actor{ID: "user-456"},
+			asOf:      &asOf,
+			wantErr:   domain.ErrPermissionDenied,
+		},
+		{
+			name:      "past state without actor",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {},
+			asOf:      &asOf,
+			wantErr:   domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+			tt.setupMock(mockRepo, mockVersionRepo)
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			res, err := service.GetUser(ctx, "user-123", tt.asOf)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantRes, res)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockVersionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_RevertUser(t *testing.T) {
+	oldUser := &domain.User{
+		ID:        "user-123",
+		FirstName: "Federico",
+		LastName:  "La Penna",
+		Email:     "flapenna@email.com",
+		Country:   "IT",
+		Nickname:  "Pennino",
+		Version:   1,
+	}
+	admin := &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}
+	tests := []struct {
+		name      string
+		setupMock func(repository *mocks.MockUserRepository, versionRepository *mocks.MockUserVersionRepository)
+		actor     *domain.Actor
+		wantErr   error
+	}{
+		{
+			name: "successful revert",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersion", mock.Anything, "user-123", int64(1)).
+					Return(&domain.UserVersion{UserId: "user-123", Version: 1, User: oldUser}, nil)
+				mockRepo.On("UpdateUser", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+					return user.ID == "user-123" && user.FirstName == oldUser.FirstName && user.Nickname == oldUser.Nickname &&
+						user.Version == 3
+				})).Return(nil).Run(func(args mock.Arguments) {
+					args.Get(1).(*domain.User).Version = 4
+				})
+			},
+			actor:   admin,
+			wantErr: nil,
+		},
+		{
+			name: "version conflict",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersion", mock.Anything, "user-123", int64(1)).
+					Return(&domain.UserVersion{UserId: "user-123", Version: 1, User: oldUser}, nil)
+				mockRepo.On("UpdateUser", mock.Anything, mock.AnythingOfType("*domain.User")).Return(domain.ErrVersionConflict)
+			},
+			actor:   admin,
+			wantErr: domain.ErrVersionConflict,
+		},
+		{
+			name: "deleted version",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersion", mock.Anything, "user-123", int64(1)).
+					Return(&domain.UserVersion{UserId: "user-123", Version: 1, Deleted: true}, nil)
+			},
+			actor:   admin,
+			wantErr: domain.ErrUserVersionNotFound,
+		},
+		{
+			name: "version not found",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {
+				mockVersionRepo.On("GetUserVersion", mock.Anything, "user-123", int64(1)).Return(nil, domain.ErrUserVersionNotFound)
+			},
+			actor:   admin,
+			wantErr: domain.ErrUserVersionNotFound,
+		},
+		{
+			name:      "not admin",
+			setupMock: func(mockRepo *mocks.MockUserRepository, mockVersionRepo *mocks.MockUserVersionRepository) {},
+			actor:     &domain.Actor{ID: "user-123"},
+			wantErr:   domain.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+			tt.setupMock(mockRepo, mockVersionRepo)
+
+			res, err := service.RevertUser(domain.ContextWithActor(context.TODO(), tt.actor), "user-123", 1, 3)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, res)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), res.Version)
+				assert.Equal(t, oldUser.FirstName, res.FirstName)
+				assert.WithinDuration(t, time.Now(), res.UpdatedAt, time.Second)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockVersionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ListUserVersions(t *testing.T) {
+	versions := &domain.ListUserVersionsQueryResponse{Results: []*domain.UserVersion{{UserId: "user-123", Version: 1}}}
+	tests := []struct {
+		name    string
+		actor   *domain.Actor
+		wantErr error
+	}{
+		{name: "own versions", actor: &domain.Actor{ID: "user-123"}},
+		{name: "admin", actor: &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}},
+		{name: "versions of another user", actor: &domain.Actor{ID: "user-456"}, wantErr: domain.ErrPermissionDenied},
+		{name: "no actor", wantErr: domain.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			service := domain.NewUserService(new(mocks.MockUserRepository), new(mocks.MockUserAuditRepository), mockVersionRepo,
+				new(mocks.MockUserDeadLetterRepository), new(mocks.MockUserReplayRepository), new(mocks.MockUserProducer), new(mocks.MockUserWatcher))
+			req := &domain.ListUserVersionsQueryRequest{UserId: "user-123"}
+			if tt.wantErr == nil {
+				mockVersionRepo.On("ListUserVersions", mock.Anything, req).Return(versions, nil)
+			}
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+
+			res, err := service.ListUserVersions(ctx, req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, versions, res)
+			}
+			mockVersionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+type acknowledgingWatcher struct {
+	*mocks.MockUserWatcher
+	*mocks.MockUserEventAcknowledger
+}
+
+func TestService_StartWatchingUsers_Acknowledge(t *testing.T) {
+	tests := []struct {
+		name          string
+		auditErr      error
+		versionErr    error
+		sendErr       error
+		deadLetterErr error
+		setupMock     func(acknowledger *mocks.MockUserEventAcknowledger, done chan struct{})
+	}{
+		{
+			name:    "ack published event",
+			sendErr: nil,
+			setupMock: func(mockAcknowledger *mocks.MockUserEventAcknowledger, done chan struct{}) {
+				mockAcknowledger.On("AckUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent")).Return(nil).
+					Run(func(args mock.Arguments) { close(done) })
+			},
+		},
+		{
+			name:    "ack dead-lettered event",
+			sendErr: errors.New("producer error"),
+			setupMock: func(mockAcknowledger *mocks.MockUserEventAcknowledger, done chan struct{}) {
+				mockAcknowledger.On("AckUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent")).Return(nil).
+					Run(func(args mock.Arguments) { close(done) })
+			},
+		},
+		{
+			name:          "nack event neither published nor dead-lettered",
+			sendErr:       errors.New("producer error"),
+			deadLetterErr: errors.New("repository error"),
+			setupMock: func(mockAcknowledger *mocks.MockUserEventAcknowledger, done chan struct{}) {
+				mockAcknowledger.On("NackUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent"), errors.New("producer error")).
+					Run(func(args mock.Arguments) { close(done) })
+			},
+		},
+		{
+			name:     "nack event not recorded in the audit log",
+			auditErr: errors.New("audit repository error"),
+			setupMock: func(mockAcknowledger *mocks.MockUserEventAcknowledger, done chan struct{}) {
+				mockAcknowledger.On("NackUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent"), errors.New("audit repository error")).
+					Run(func(args mock.Arguments) { close(done) })
+			},
+		},
+		{
+			name:       "nack event whose version is not recorded",
+			versionErr: errors.New("version repository error"),
+			setupMock: func(mockAcknowledger *mocks.MockUserEventAcknowledger, done chan struct{}) {
+				mockAcknowledger.On("NackUserEvent", mock.Anything, mock.AnythingOfType("*domain.UserEvent"), errors.New("version repository error")).
+					Run(func(args mock.Arguments) { close(done) })
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.UserEvent{Id: "event-1", UserId: "user-123", AfterChange: &domain.User{ID: "user-123", Version: 1}}
+
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			mockAcknowledger := new(mocks.MockUserEventAcknowledger)
+			watcher := &acknowledgingWatcher{mockWatcher, mockAcknowledger}
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, watcher)
+
+			events := make(chan *domain.UserEvent, 1)
+			events <- event
+			close(events)
+
+			done := make(chan struct{})
+			mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+			mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.AnythingOfType("*domain.UserAuditEntry")).Return(tt.auditErr)
+			mockVersionRepo.On("AppendUserVersion", mock.Anything, mock.AnythingOfType("*domain.UserVersion")).Return(tt.versionErr)
+			mockProducer.On("SendMessage", event).Return(tt.sendErr)
+			if tt.sendErr != nil {
+				mockDeadLetterRepo.On("RecordDeadLetter", mock.Anything, event, tt.sendErr, mock.AnythingOfType("time.Time")).
+					Return(tt.deadLetterErr)
+			}
+			tt.setupMock(mockAcknowledger, done)
+
+			service.StartWatchingUsers(context.TODO())
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the event to be acknowledged")
+			}
+
+			mockAcknowledger.AssertExpectations(t)
+			mockDeadLetterRepo.AssertExpectations(t)
+			if tt.auditErr != nil || tt.versionErr != nil {
+				mockProducer.AssertNotCalled(t, "SendMessage", event)
+			}
+		})
+	}
+}
+
+func TestService_StartWatchingUsers_SuppressTimestampOnlyEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		changedFields []string
+		suppressed    bool
+	}{
+		{name: "suppress timestamp only update", changedFields: []string{domain.UserFieldUpdatedAt}, suppressed: true},
+		{name: "publish email update", changedFields: []string{domain.UserFieldEmail, domain.UserFieldUpdatedAt}},
+		{name: "publish resynced user", changedFields: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.UserEvent{Id: "event-1", UserId: "user-123", AfterChange: &domain.User{ID: "user-123", Version: 2},
+				OperationType: domain.OPERATION_UPDATE, ChangedFields: tt.changedFields}
+
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			mockAcknowledger := new(mocks.MockUserEventAcknowledger)
+			watcher := &acknowledgingWatcher{mockWatcher, mockAcknowledger}
+			service := domain.NewUserServiceWithOptions(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, watcher,
+				domain.UserServiceOptions{SuppressTimestampOnlyEvents: true})
+
+			events := make(chan *domain.UserEvent, 1)
+			events <- event
+			close(events)
+
+			done := make(chan struct{})
+			mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+			mockAuditRepo.On("AppendAuditEntry", mock.Anything, mock.AnythingOfType("*domain.UserAuditEntry")).Return(nil)
+			mockVersionRepo.On("AppendUserVersion", mock.Anything, mock.AnythingOfType("*domain.UserVersion")).Return(nil)
+			if !tt.suppressed {
+				mockProducer.On("SendMessage", event).Return(nil)
+			}
+			mockAcknowledger.On("AckUserEvent", mock.Anything, event).Return(nil).Run(func(args mock.Arguments) { close(done) })
+
+			service.StartWatchingUsers(context.TODO())
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the event to be acknowledged")
+			}
+
+			mockAuditRepo.AssertExpectations(t)
+			mockVersionRepo.AssertExpectations(t)
+			mockProducer.AssertExpectations(t)
+			if tt.suppressed {
+				mockProducer.AssertNotCalled(t, "SendMessage", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestService_RedriveDeadLetter(t *testing.T) {
+	admin := &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}
+	event := &domain.UserEvent{Id: "event-1", UserId: "user-123"}
+	deadLetter := &domain.DeadLetter{Id: "event-1", Event: event, Error: "producer error", Attempts: 1}
+
+	tests := []struct {
+		name      string
+		actor     *domain.Actor
+		setupMock func(*mocks.MockUserDeadLetterRepository, *mocks.MockUserProducer)
+		wantErr   error
+	}{
+		{
+			name:  "redriven and removed",
+			actor: admin,
+			setupMock: func(mockDeadLetterRepo *mocks.MockUserDeadLetterRepository, mockProducer *mocks.MockUserProducer) {
+				mockDeadLetterRepo.On("GetDeadLetter", mock.Anything, "event-1").Return(deadLetter, nil).Once()
+				mockProducer.On("SendMessage", event).Return(nil).Once()
+				mockDeadLetterRepo.On("DeleteDeadLetter", mock.Anything, "event-1").Return(nil).Once()
+			},
+		},
+		{
+			name:  "failed attempt recorded",
+			actor: admin,
+			setupMock: func(mockDeadLetterRepo *mocks.MockUserDeadLetterRepository, mockProducer *mocks.MockUserProducer) {
+				mockDeadLetterRepo.On("GetDeadLetter", mock.Anything, "event-1").Return(deadLetter, nil).Once()
+				mockProducer.On("SendMessage", event).Return(errors.New("producer error")).Once()
+				mockDeadLetterRepo.On("RecordDeadLetter", mock.Anything, event, errors.New("producer error"), mock.AnythingOfType("time.Time")).
+					Return(nil).Once()
+			},
+			wantErr: errors.New("producer error"),
+		},
+		{
+			name:  "not found",
+			actor: admin,
+			setupMock: func(mockDeadLetterRepo *mocks.MockUserDeadLetterRepository, mockProducer *mocks.MockUserProducer) {
+				mockDeadLetterRepo.On("GetDeadLetter", mock.Anything, "event-1").Return(nil, domain.ErrDeadLetterNotFound).Once()
+			},
+			wantErr: domain.ErrDeadLetterNotFound,
+		},
+		{
+			name:      "not an admin",
+			actor:     &domain.Actor{ID: "user-123"},
+			setupMock: func(*mocks.MockUserDeadLetterRepository, *mocks.MockUserProducer) {},
+			wantErr:   domain.ErrPermissionDenied,
+		},
+		{
+			name:      "unauthenticated",
+			setupMock: func(*mocks.MockUserDeadLetterRepository, *mocks.MockUserProducer) {},
+			wantErr:   domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+			tt.setupMock(mockDeadLetterRepo, mockProducer)
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			err := service.RedriveDeadLetter(ctx, "event-1")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDeadLetterRepo.AssertExpectations(t)
+			mockProducer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_DiscardDeadLetter(t *testing.T) {
+	tests := []struct {
+		name      string
+		actor     *domain.Actor
+		setupMock func(*mocks.MockUserDeadLetterRepository)
+		wantErr   error
+	}{
+		{
+			name:  "discarded",
+			actor: &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin},
+			setupMock: func(mockDeadLetterRepo *mocks.MockUserDeadLetterRepository) {
+				mockDeadLetterRepo.On("DeleteDeadLetter", mock.Anything, "event-1").Return(nil).Once()
+			},
+		},
+		{
+			name:  "not found",
+			actor: &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin},
+			setupMock: func(mockDeadLetterRepo *mocks.MockUserDeadLetterRepository) {
+				mockDeadLetterRepo.On("DeleteDeadLetter", mock.Anything, "event-1").Return(domain.ErrDeadLetterNotFound).Once()
+			},
+			wantErr: domain.ErrDeadLetterNotFound,
+		},
+		{
+			name:      "not an admin",
+			actor:     &domain.Actor{ID: "user-123"},
+			setupMock: func(*mocks.MockUserDeadLetterRepository) {},
+			wantErr:   domain.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(mockRepo, mockAuditRepo, mockVersionRepo, mockDeadLetterRepo, mockReplayRepo, mockProducer, mockWatcher)
+			tt.setupMock(mockDeadLetterRepo)
+
+			ctx := domain.ContextWithActor(context.TODO(), tt.actor)
+			err := service.DiscardDeadLetter(ctx, "event-1")
+
+			assert.Equal(t, tt.wantErr, err)
+			mockDeadLetterRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_StartUserReplay(t *testing.T) {
+	admin := &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}
+	country := "UK"
+
+	tests := []struct {
+		name        string
+		actor       *domain.Actor
+		existing    *domain.UserReplay
+		getErr      error
+		wantAfterId string
+		wantRate    uint32
+		wantErr     error
+	}{
+		{
+			name:     "new replay",
+			actor:    admin,
+			getErr:   domain.ErrUserReplayNotFound,
+			wantRate: 100,
+		},
+		{
+			name:  "failed replay resumed",
+			actor: admin,
+			existing: &domain.UserReplay{
+				Id:            "replay-1",
+				Query:         domain.ScanUsersQuery{AfterId: "user-1", Country: &country},
+				RatePerSecond: 50,
+				State:         domain.UserReplayStateFailed,
+				ReplayedCount: 1,
+				Error:         "producer error",
+			},
+			wantAfterId: "user-1",
+			wantRate:    50,
+		},
+		{
+			name:  "replay of a gone owner resumed",
+			actor: admin,
+			existing: &domain.UserReplay{
+				Id:            "replay-1",
+				Query:         domain.ScanUsersQuery{AfterId: "user-1"},
+				RatePerSecond: 50,
+				State:         domain.UserReplayStateRunning,
+				Owner:         "instance-1",
+				UpdatedAt:     time.Now().Add(-time.Hour),
+			},
+			wantAfterId: "user-1",
+			wantRate:    50,
+		},
+		{
+			name:  "running replay",
+			actor: admin,
+			existing: &domain.UserReplay{
+				Id:        "replay-1",
+				State:     domain.UserReplayStateRunning,
+				Owner:     "instance-1",
+				UpdatedAt: time.Now(),
+			},
+			wantErr: domain.ErrUserReplayRunning,
+		},
+		{
+			name:     "pending replay",
+			actor:    admin,
+			existing: &domain.UserReplay{Id: "replay-1", State: domain.UserReplayStatePending},
+			wantErr:  domain.ErrUserReplayRunning,
+		},
+		{
+			name:     "completed replay",
+			actor:    admin,
+			existing: &domain.UserReplay{Id: "replay-1", State: domain.UserReplayStateCompleted},
+			wantErr:  domain.ErrUserReplayCompleted,
+		},
+		{
+			name:    "not an admin",
+			actor:   &domain.Actor{ID: "user-123"},
+			wantErr: domain.ErrPermissionDenied,
+		},
+		{
+			name:    "unauthenticated",
+			wantErr: domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockReplayRepo := new(mocks.MockUserReplayRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			service := domain.NewUserService(mockRepo, new(mocks.MockUserAuditRepository), new(mocks.MockUserVersionRepository),
+				new(mocks.MockUserDeadLetterRepository), mockReplayRepo, mockProducer, new(mocks.MockUserWatcher))
+
+			if tt.existing != nil || tt.getErr != nil {
+				mockReplayRepo.On("GetUserReplay", mock.Anything, "replay-1").Return(tt.existing, tt.getErr).Once()
+			}
+			if tt.wantErr == nil {
+				// The replay is left for the leader to run
+				mockReplayRepo.On("SaveUserReplay", mock.Anything, mock.MatchedBy(func(replay *domain.UserReplay) bool {
+					return replay.Id == "replay-1" && replay.State == domain.UserReplayStatePending && replay.Owner == "" &&
+						replay.Error == "" && replay.Query.AfterId == tt.wantAfterId && replay.RatePerSecond == tt.wantRate
+				})).Return(nil).Once()
+			}
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			started, err := service.StartUserReplay(ctx, &domain.StartUserReplayRequest{Id: "replay-1", Country: &country})
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Nil(t, started)
+				mockReplayRepo.AssertNotCalled(t, "SaveUserReplay", mock.Anything, mock.Anything)
+			} else {
+				assert.Equal(t, domain.UserReplayStatePending, started.State)
+				assert.Equal(t, "admin-1", started.StartedBy)
+			}
+			mockRepo.AssertNotCalled(t, "ScanUsers", mock.Anything, mock.Anything, mock.Anything)
+			mockProducer.AssertNotCalled(t, "SendMessage", mock.Anything)
+			mockReplayRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// runReplay runs the pending replay on a leader watching no user change, and returns the
+// events it published with the replay as of its last checkpoint
+func runReplay(t *testing.T, pending *domain.UserReplay, users []*domain.User) ([]*domain.UserEvent, domain.UserReplay) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockReplayRepo := new(mocks.MockUserReplayRepository)
+	mockProducer := new(mocks.MockUserProducer)
+	mockWatcher := new(mocks.MockUserWatcher)
+	// The audit log and the version history are not written, a replayed user is no change
+	service := domain.NewUserServiceWithOptions(mockRepo, new(mocks.MockUserAuditRepository), new(mocks.MockUserVersionRepository),
+		new(mocks.MockUserDeadLetterRepository), mockReplayRepo, mockProducer, mockWatcher,
+		domain.UserServiceOptions{InstanceId: "instance-1", ReplayPollInterval: 10 * time.Millisecond})
+
+	events := make(chan *domain.UserEvent)
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+
+	// The replay repository keeps the replay as a store would
+	var mu sync.Mutex
+	stored := *pending
+	done := make(chan domain.UserReplay, 1)
+	mockReplayRepo.On("ClaimUserReplay", mock.Anything, "instance-1", mock.AnythingOfType("time.Time")).
+		Return(func(ctx context.Context, owner string, staleBefore time.Time) (*domain.UserReplay, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if stored.State != domain.UserReplayStatePending {
+				return nil, domain.ErrUserReplayNotFound
+			}
+			stored.State = domain.UserReplayStateRunning
+			stored.Owner = owner
+			claimed := stored
+			return &claimed, nil
+		})
+	mockReplayRepo.On("CheckpointUserReplay", mock.Anything, mock.AnythingOfType("*domain.UserReplay"), "instance-1").
+		Return(func(ctx context.Context, replay *domain.UserReplay, owner string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, owner, stored.Owner)
+			stored = *replay
+			if replay.State == domain.UserReplayStateCompleted || replay.State == domain.UserReplayStateFailed {
+				done <- stored
+			}
+			return nil
+		})
+	mockRepo.On("ScanUsers", mock.Anything, mock.AnythingOfType("*domain.ScanUsersQuery"), mock.Anything).
+		Return(func(ctx context.Context, query *domain.ScanUsersQuery, fn func(*domain.User) error) error {
+			for _, user := range users {
+				if user.ID <= query.AfterId {
+					continue
+				}
+				if err := fn(user); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	published := make([]*domain.UserEvent, 0)
+	mockProducer.On("SendMessage", mock.AnythingOfType("*domain.UserEvent")).
+		Run(func(args mock.Arguments) {
+			published = append(published, args.Get(0).(*domain.UserEvent))
+		}).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	watching := service.StartWatchingUsers(ctx)
+	replaying := service.StartReplayingUsers(ctx)
+
+	var replay domain.UserReplay
+	select {
+	case replay = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("replay did not complete")
+	}
+	cancel()
+	<-replaying
+	close(events)
+	<-watching
+	return published, replay
+}
+
+func TestService_StartReplayingUsers(t *testing.T) {
+	users := []*domain.User{
+		{ID: "user-1", FirstName: "John", Country: "UK", Version: 2},
+		{ID: "user-2", FirstName: "Jane", Country: "UK", Version: 1},
+		{ID: "user-3", FirstName: "Jim", Country: "UK", Version: 5},
+	}
+
+	tests := []struct {
+		name          string
+		pending       *domain.UserReplay
+		wantPublished []string
+	}{
+		{
+			name:          "new replay",
+			pending:       &domain.UserReplay{Id: "replay-1", RatePerSecond: 1000, State: domain.UserReplayStatePending},
+			wantPublished: []string{"user-1", "user-2", "user-3"},
+		},
+		{
+			name: "interrupted replay resumed",
+			pending: &domain.UserReplay{
+				Id:            "replay-1",
+				Query:         domain.ScanUsersQuery{AfterId: "user-1"},
+				RatePerSecond: 1000,
+				State:         domain.UserReplayStatePending,
+				ReplayedCount: 1,
+			},
+			wantPublished: []string{"user-2", "user-3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published, replay := runReplay(t, tt.pending, users)
+
+			ids := make([]string, 0, len(published))
+			for _, event := range published {
+				assert.True(t, event.Replayed)
+				assert.Equal(t, domain.OPERATION_CREATE, event.OperationType)
+				assert.Equal(t, "replay-1", event.CorrelationId)
+				ids = append(ids, event.UserId)
+			}
+			assert.Equal(t, tt.wantPublished, ids)
+			assert.Equal(t, domain.UserReplayStateCompleted, replay.State)
+			assert.Empty(t, replay.Owner)
+			assert.Empty(t, replay.Error)
+			assert.Equal(t, "user-3", replay.Query.AfterId)
+			assert.Equal(t, int64(len(users)), replay.ReplayedCount)
+		})
+	}
+}
+
+func TestService_StartReplayingUsers_TakenOver(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockReplayRepo := new(mocks.MockUserReplayRepository)
+	mockProducer := new(mocks.MockUserProducer)
+	mockWatcher := new(mocks.MockUserWatcher)
+	service := domain.NewUserServiceWithOptions(mockRepo, new(mocks.MockUserAuditRepository), new(mocks.MockUserVersionRepository),
+		new(mocks.MockUserDeadLetterRepository), mockReplayRepo, mockProducer, mockWatcher,
+		domain.UserServiceOptions{InstanceId: "instance-1", ReplayPollInterval: 10 * time.Millisecond})
+
+	events := make(chan *domain.UserEvent)
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+	mockReplayRepo.On("ClaimUserReplay", mock.Anything, "instance-1", mock.AnythingOfType("time.Time")).
+		Return(&domain.UserReplay{Id: "replay-1", RatePerSecond: 1000, State: domain.UserReplayStateRunning, Owner: "instance-1"}, nil).Once()
+	mockReplayRepo.On("ClaimUserReplay", mock.Anything, "instance-1", mock.AnythingOfType("time.Time")).
+		Return(nil, domain.ErrUserReplayNotFound)
+	// Another instance claimed the replay since, the checkpoint of this one is refused
+	checkpointed := make(chan struct{})
+	mockReplayRepo.On("CheckpointUserReplay", mock.Anything, mock.AnythingOfType("*domain.UserReplay"), "instance-1").
+		Run(func(args mock.Arguments) { close(checkpointed) }).Return(domain.ErrUserReplayTakenOver).Once()
+	mockRepo.On("ScanUsers", mock.Anything, mock.AnythingOfType("*domain.ScanUsersQuery"), mock.Anything).
+		Return(func(ctx context.Context, query *domain.ScanUsersQuery, fn func(*domain.User) error) error {
+			return fn(&domain.User{ID: "user-1", Version: 1})
+		}).Once()
+	mockProducer.On("SendMessage", mock.AnythingOfType("*domain.UserEvent")).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	watching := service.StartWatchingUsers(ctx)
+	replaying := service.StartReplayingUsers(ctx)
+	select {
+	case <-checkpointed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("replay was not checkpointed")
+	}
+	cancel()
+	<-replaying
+	close(events)
+	<-watching
+
+	// The replay is left to its new owner, and not saved again
+	mockReplayRepo.AssertNumberOfCalls(t, "CheckpointUserReplay", 1)
+	mockReplayRepo.AssertNotCalled(t, "SaveUserReplay", mock.Anything, mock.Anything)
+}
+
+func TestService_ReplayEventIdsAreStable(t *testing.T) {
+	// A resumed replay publishes the same event ids, so consumers can deduplicate them
+	user := &domain.User{ID: "user-1", Version: 3}
+
+	eventIds := make([]string, 0)
+	for i := 0; i < 2; i++ {
+		published, _ := runReplay(t, &domain.UserReplay{Id: "replay-1", RatePerSecond: 1000, State: domain.UserReplayStatePending},
+			[]*domain.User{user})
+		for _, event := range published {
+			eventIds = append(eventIds, event.Id)
+		}
+	}
+
+	assert.Len(t, eventIds, 2)
+	assert.Equal(t, eventIds[0], eventIds[1])
+	_, err := uuid.Parse(eventIds[0])
+	assert.NoError(t, err)
+}
+
+func TestService_WatchUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   *domain.Actor
+		req     *domain.WatchUsersRequest
+		wantErr error
+	}{
+		{name: "admin watching every user", actor: &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}, req: &domain.WatchUsersRequest{}},
+		{name: "user watching themselves", actor: &domain.Actor{ID: "user-123"}, req: &domain.WatchUsersRequest{UserIds: []string{"user-123"}}},
+		{name: "user watching every user", actor: &domain.Actor{ID: "user-123"}, req: &domain.WatchUsersRequest{}, wantErr: domain.ErrPermissionDenied},
+		{name: "user watching another user", actor: &domain.Actor{ID: "user-123"}, req: &domain.WatchUsersRequest{UserIds: []string{"user-456"}},
+			wantErr: domain.ErrPermissionDenied},
+		{name: "unauthenticated", req: &domain.WatchUsersRequest{}, wantErr: domain.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.UserEvent{Id: "event-1", UserId: "user-123", AfterChange: &domain.User{ID: "user-123", Version: 1},
+				OperationType: domain.OPERATION_CREATE}
+
+			mockAuditRepo := new(mocks.MockUserAuditRepository)
+			mockVersionRepo := new(mocks.MockUserVersionRepository)
+			mockProducer := new(mocks.MockUserProducer)
+			mockWatcher := new(mocks.MockUserWatcher)
+			service := domain.NewUserService(new(mocks.MockUserRepository), mockAuditRepo, mockVersionRepo,
+				new(mocks.MockUserDeadLetterRepository), new(mocks.MockUserReplayRepository), mockProducer, mockWatcher)
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			subscription, err := service.WatchUsers(ctx, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			// The events of the feed are streamed, until it stops
+			events := make(chan *domain.UserEvent, 1)
+			events <- event
+			close(events)
+			feed := new(mocks.MockUserWatcher)
+			feed.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+			<-service.StartBroadcastingUsers(ctx, feed)
+
+			watched, err := subscription.Next(ctx)
+			assert.NoError(t, err)
+			require.NotNil(t, watched)
+			// The epoch is the random id of the broadcaster of the service
+			assert.NotEmpty(t, watched.Epoch)
+			assert.Equal(t, &domain.WatchedUserEvent{Sequence: 1, Epoch: watched.Epoch, Event: event}, watched)
+			_, err = subscription.Next(ctx)
+			assert.ErrorIs(t, err, domain.ErrUserEventsStopped)
+			// Nothing is published by the feed, only by the watcher of the leader
+			mockProducer.AssertNotCalled(t, "SendMessage", mock.Anything)
+			mockWatcher.AssertNotCalled(t, "WatchUsers", mock.Anything)
+		})
+	}
+}
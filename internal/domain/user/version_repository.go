@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserVersionRepository stores the version history of every user
+type UserVersionRepository interface {
+	AppendUserVersion(ctx context.Context, version *UserVersion) error
+	GetUserVersion(ctx context.Context, userId string, version int64) (*UserVersion, error)
+	GetUserVersionAt(ctx context.Context, userId string, asOf time.Time) (*UserVersion, error)
+	ListUserVersions(ctx context.Context, request *ListUserVersionsQueryRequest) (*ListUserVersionsQueryResponse, error)
+}
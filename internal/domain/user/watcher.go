@@ -5,3 +5,34 @@ import "context"
 type UserWatcher interface {
 	WatchUsers(ctx context.Context) <-chan *UserEvent
 }
+
+// UserEventAcknowledger is implemented by the watchers that need to know whether an
+// emitted event has been published, to redeliver the events that were not. The events
+// are acknowledged in the order they were emitted.
+type UserEventAcknowledger interface {
+	AckUserEvent(ctx context.Context, event *UserEvent) error
+	NackUserEvent(ctx context.Context, event *UserEvent, err error)
+}
+
+// WatcherState is the state of a UserWatcher, as reported to the health endpoint
+type WatcherState string
+
+const (
+	WatcherStateStarting WatcherState = "starting"
+	WatcherStateRunning  WatcherState = "running"
+	WatcherStateRetrying WatcherState = "retrying"
+	WatcherStateFailed   WatcherState = "failed"
+	WatcherStateStopped  WatcherState = "stopped"
+)
+
+type WatcherStatus struct {
+	State WatcherState
+	// Attempts is the number of consecutive failures
+	Attempts  int
+	LastError string
+}
+
+// UserWatcherStatusReporter is implemented by the watchers exposing their state
+type UserWatcherStatusReporter interface {
+	WatcherStatus() WatcherStatus
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// WebhookSubscription is an HTTP endpoint receiving the user events of OperationTypes,
+// or every user event when empty
+type WebhookSubscription struct {
+	Id  string
+	Url string
+	// Secret signs the payloads sent to the endpoint
+	Secret         string
+	OperationTypes []OperationType
+	Enabled        bool
+	// ConsecutiveFailures is the number of events in a row that could not be delivered
+	ConsecutiveFailures int32
+	// DisabledReason explains why the subscription was disabled automatically
+	DisabledReason string
+	CreatedBy      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Matches tells whether the event has to be delivered to the subscription
+func (s *WebhookSubscription) Matches(event *UserEvent) bool {
+	if len(s.OperationTypes) == 0 {
+		return true
+	}
+	for _, operationType := range s.OperationTypes {
+		if operationType == event.OperationType {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWebhookUrl only accepts the absolute https URLs with a host. The addresses the host
+// resolves to are checked by the WebhookSender, when connecting.
+func validateWebhookUrl(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookUrl, err)
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%w: an https url with a host is required", ErrInvalidWebhookUrl)
+	}
+	return nil
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	Id             string
+	Url            string
+	OperationTypes []OperationType
+	Enabled        bool
+	// RotateSecret replaces the secret, returned in the updated subscription
+	RotateSecret bool
+}
+
+type ListWebhookSubscriptionsQueryRequest struct {
+	Page     uint32
+	PageSize uint32
+}
+
+type ListWebhookSubscriptionsQueryResponse struct {
+	Page       uint32
+	PageSize   uint32
+	TotalCount uint32
+	Results    []*WebhookSubscription
+}
+
+// WebhookDelivery is the outcome of the delivery of a user event to a webhook subscription,
+// after all its attempts
+type WebhookDelivery struct {
+	Id             string
+	SubscriptionId string
+	EventId        string
+	OperationType  OperationType
+	Succeeded      bool
+	Attempts       int32
+	// StatusCode is the HTTP status of the last attempt, 0 when the endpoint did not respond
+	StatusCode int32
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// WebhookRetry is a delivery of a user event waiting in the queue of its subscription for its
+// next attempt
+type WebhookRetry struct {
+	// Delivery is the delivery in progress, with its attempts so far and the last failure
+	Delivery *WebhookDelivery
+	Event    *UserEvent
+	// Position orders the retries of a subscription, as their events
+	Position      int64
+	NextAttemptAt time.Time
+}
+
+type ListWebhookDeliveriesQueryRequest struct {
+	SubscriptionId string
+	Page           uint32
+	PageSize       uint32
+	Succeeded      *bool
+}
+
+type ListWebhookDeliveriesQueryResponse struct {
+	Page       uint32
+	PageSize   uint32
+	TotalCount uint32
+	Results    []*WebhookDelivery
+}
+
+// WebhookSender posts a user event to the endpoint of a subscription, signed with its secret.
+// The error is set when the endpoint could not be reached or did not answer with a 2xx status.
+// A sender should refuse to connect to the internal network, whatever the url resolves to.
+type WebhookSender interface {
+	SendWebhook(ctx context.Context, subscription *WebhookSubscription, event *UserEvent) (statusCode int, err error)
+}
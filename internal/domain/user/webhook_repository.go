@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+)
+
+// WebhookSubscriptionRepository stores the webhook subscriptions, by subscription id
+type WebhookSubscriptionRepository interface {
+	CreateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	GetWebhookSubscription(ctx context.Context, id string) (*WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context, request *ListWebhookSubscriptionsQueryRequest) (*ListWebhookSubscriptionsQueryResponse, error)
+	// ListEnabledWebhookSubscriptions returns every subscription the user events are delivered to
+	ListEnabledWebhookSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	// RecordWebhookOutcome resets the consecutive failures of the subscription after a delivery,
+	// or counts the failure and disables the subscription once disableAfter failures follow
+	// each other. It returns the updated subscription.
+	RecordWebhookOutcome(ctx context.Context, id string, failure error, disableAfter int32) (*WebhookSubscription, error)
+}
+
+// WebhookRetryRepository queues the deliveries waiting for another attempt, by subscription
+type WebhookRetryRepository interface {
+	EnqueueWebhookRetry(ctx context.Context, retry *WebhookRetry) error
+	// ListQueuedWebhookSubscriptions returns the ids of the subscriptions with queued retries
+	ListQueuedWebhookSubscriptions(ctx context.Context) ([]string, error)
+	// GetFirstWebhookRetry returns the retry of the subscription with the lowest position,
+	// ErrWebhookRetryNotFound when its queue is empty
+	GetFirstWebhookRetry(ctx context.Context, subscriptionId string) (*WebhookRetry, error)
+	UpdateWebhookRetry(ctx context.Context, retry *WebhookRetry) error
+	DeleteWebhookRetry(ctx context.Context, id string) error
+	// DeleteWebhookRetries empties the queue of the subscription, and returns the number of retries deleted
+	DeleteWebhookRetries(ctx context.Context, subscriptionId string) (int64, error)
+}
+
+// WebhookDeliveryRepository is the delivery log of the webhook subscriptions
+type WebhookDeliveryRepository interface {
+	AppendWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, request *ListWebhookDeliveriesQueryRequest) (*ListWebhookDeliveriesQueryResponse, error)
+}
@@ -0,0 +1,483 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookSecretSize is the number of random bytes of a webhook secret
+	webhookSecretSize = 32
+
+	defaultWebhookMaxAttempts          = 5
+	defaultWebhookInitialBackoff       = time.Second
+	defaultWebhookMaxBackoff           = time.Minute
+	defaultWebhookDisableAfterFailures = 10
+	defaultWebhookRetryPollInterval    = time.Second
+)
+
+type WebhookService interface {
+	CreateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) (*WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id string) (*WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context, request *ListWebhookSubscriptionsQueryRequest) (*ListWebhookSubscriptionsQueryResponse, error)
+	UpdateWebhookSubscription(ctx context.Context, request *UpdateWebhookSubscriptionRequest) (*WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	ListWebhookDeliveries(ctx context.Context, request *ListWebhookDeliveriesQueryRequest) (*ListWebhookDeliveriesQueryResponse, error)
+	StartDeliveringWebhooks(ctx context.Context) <-chan struct{}
+}
+
+// WebhookServiceOptions tunes how the user events are delivered to the webhooks
+type WebhookServiceOptions struct {
+	// MaxAttempts is the number of attempts to deliver an event to a subscription, 5 by default
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt, doubled for every next attempt
+	// up to MaxBackoff. They are 1s and 1m by default.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// DisableAfterFailures is the number of events in a row a subscription can fail to receive
+	// before being disabled, 10 by default
+	DisableAfterFailures int32
+	// RetryPollInterval is the delay between two checks of the queued retries, 1s by default
+	RetryPollInterval time.Duration
+}
+
+type webhookService struct {
+	subscriptions WebhookSubscriptionRepository
+	deliveries    WebhookDeliveryRepository
+	retries       WebhookRetryRepository
+	sender        WebhookSender
+	watcher       UserWatcher
+	opts          WebhookServiceOptions
+
+	mu sync.Mutex
+	// retrying holds the subscriptions whose queue is being retried
+	retrying map[string]bool
+	// position is the position of the last queued retry
+	position int64
+}
+
+func NewWebhookService(subscriptions WebhookSubscriptionRepository, deliveries WebhookDeliveryRepository,
+	retries WebhookRetryRepository, sender WebhookSender, watcher UserWatcher, opts WebhookServiceOptions) WebhookService {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaultWebhookInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultWebhookMaxBackoff
+	}
+	if opts.DisableAfterFailures <= 0 {
+		opts.DisableAfterFailures = defaultWebhookDisableAfterFailures
+	}
+	if opts.RetryPollInterval <= 0 {
+		opts.RetryPollInterval = defaultWebhookRetryPollInterval
+	}
+	return &webhookService{subscriptions: subscriptions, deliveries: deliveries, retries: retries, sender: sender,
+		watcher: watcher, opts: opts, retrying: make(map[string]bool)}
+}
+
+// CreateWebhookSubscription registers an enabled subscription of an https url with a new secret.
+// The secret is only returned here and when rotated.
+func (s *webhookService) CreateWebhookSubscription(ctx context.Context, subscription *WebhookSubscription) (*WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookUrl(subscription.Url); err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription.Id = uuid.NewString()
+	subscription.Secret = secret
+	subscription.Enabled = true
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledReason = ""
+	subscription.CreatedBy = ActorIdFromContext(ctx)
+	subscription.CreatedAt = time.Now().UTC().Round(time.Millisecond)
+	subscription.UpdatedAt = subscription.CreatedAt
+	if err := s.subscriptions.CreateWebhookSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{"actor_id": subscription.CreatedBy, "subscription_id": subscription.Id}).
+		Info("webhook subscription created")
+	return subscription, nil
+}
+
+func (s *webhookService) GetWebhookSubscription(ctx context.Context, id string) (*WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	subscription, err := s.subscriptions.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return withoutSecret(subscription), nil
+}
+
+func (s *webhookService) ListWebhookSubscriptions(ctx context.Context, req *ListWebhookSubscriptionsQueryRequest) (*ListWebhookSubscriptionsQueryResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	res, err := s.subscriptions.ListWebhookSubscriptions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for i, subscription := range res.Results {
+		res.Results[i] = withoutSecret(subscription)
+	}
+	return res, nil
+}
+
+// UpdateWebhookSubscription replaces the endpoint and the filters of the subscription. Enabling
+// a disabled subscription resets its failures, rotating the secret returns the new one.
+func (s *webhookService) UpdateWebhookSubscription(ctx context.Context, req *UpdateWebhookSubscriptionRequest) (*WebhookSubscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookUrl(req.Url); err != nil {
+		return nil, err
+	}
+	subscription, err := s.subscriptions.GetWebhookSubscription(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled && !subscription.Enabled {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledReason = ""
+	}
+	subscription.Url = req.Url
+	subscription.OperationTypes = req.OperationTypes
+	subscription.Enabled = req.Enabled
+	if req.RotateSecret {
+		if subscription.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	subscription.UpdatedAt = time.Now().UTC().Round(time.Millisecond)
+	if err := s.subscriptions.UpdateWebhookSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{"actor_id": ActorIdFromContext(ctx), "subscription_id": subscription.Id,
+		"enabled": subscription.Enabled, "secret_rotated": req.RotateSecret}).Info("webhook subscription updated")
+	if req.RotateSecret {
+		return subscription, nil
+	}
+	return withoutSecret(subscription), nil
+}
+
+func (s *webhookService) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if err := s.subscriptions.DeleteWebhookSubscription(ctx, id); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{"actor_id": ActorIdFromContext(ctx), "subscription_id": id}).Info("webhook subscription deleted")
+	return nil
+}
+
+// ListWebhookDeliveries returns the delivery log of a subscription, the latest deliveries first
+func (s *webhookService) ListWebhookDeliveries(ctx context.Context, req *ListWebhookDeliveriesQueryRequest) (*ListWebhookDeliveriesQueryResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.subscriptions.GetWebhookSubscription(ctx, req.SubscriptionId); err != nil {
+		return nil, err
+	}
+	return s.deliveries.ListWebhookDeliveries(ctx, req)
+}
+
+// StartDeliveringWebhooks delivers every event of the watcher to the enabled subscriptions
+// matching it, concurrently. Every subscription gets one attempt right away: a failed delivery is
+// queued for the next attempts, and the events following it are queued behind it, so that a
+// subscription receives the events in order while a failing endpoint does not hold the others back.
+// The event is acknowledged once every subscription received it or has it queued: the deliveries
+// interrupted by a shutdown start over after a restart.
+// The returned channel is closed once the watcher stopped and the deliveries in progress ended.
+func (s *webhookService) StartDeliveringWebhooks(ctx context.Context) <-chan struct{} {
+	userEvents := s.watcher.WatchUsers(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for userEvent := range userEvents {
+			err := s.deliver(ctx, userEvent)
+			if ctx.Err() != nil {
+				continue
+			}
+			acknowledge(ctx, s.watcher, userEvent, err)
+		}
+		log.Warn("Webhook watcher stopped, user events are no longer delivered to the webhooks.")
+	}()
+	go func() {
+		defer wg.Done()
+		s.retryQueuedDeliveries(ctx)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wg.Wait()
+	}()
+	return done
+}
+
+// deliver makes the first attempt to deliver the event to the subscriptions without queued retries,
+// and queues it for the others. It fails when the event could not be queued for a subscription.
+func (s *webhookService) deliver(ctx context.Context, event *UserEvent) error {
+	subscriptions, err := s.subscriptions.ListEnabledWebhookSubscriptions(ctx)
+	if err != nil {
+		log.Errorf("Error listing the webhook subscriptions of user event %s: %v", event.Id, err)
+		return err
+	}
+	queuedIds, err := s.retries.ListQueuedWebhookSubscriptions(ctx)
+	if err != nil {
+		log.Errorf("Error listing the queued webhook subscriptions of user event %s: %v", event.Id, err)
+		return err
+	}
+	queued := make(map[string]bool, len(queuedIds))
+	for _, id := range queuedIds {
+		queued[id] = true
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.deliverTo(ctx, subscription, event, queued[subscription.Id]); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// deliverTo sends the event to the subscription once, and queues it when the attempt failed, or
+// right away when the subscription has queued retries
+func (s *webhookService) deliverTo(ctx context.Context, subscription *WebhookSubscription, event *UserEvent, queued bool) error {
+	delivery := &WebhookDelivery{
+		Id:             uuid.NewString(),
+		SubscriptionId: subscription.Id,
+		EventId:        event.Id,
+		OperationType:  event.OperationType,
+		StartedAt:      time.Now().UTC().Round(time.Millisecond),
+	}
+	nextAttemptAt := time.Now()
+	if !queued {
+		failure := s.attempt(ctx, subscription, event, delivery)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if failure == nil || int(delivery.Attempts) >= s.opts.MaxAttempts {
+			s.finish(ctx, subscription, event, delivery, failure)
+			return nil
+		}
+		nextAttemptAt = nextAttemptAt.Add(s.backoff(delivery.Attempts))
+	}
+
+	retry := &WebhookRetry{Delivery: delivery, Event: event, Position: s.nextPosition(),
+		NextAttemptAt: nextAttemptAt.UTC().Round(time.Millisecond)}
+	if err := s.retries.EnqueueWebhookRetry(ctx, retry); err != nil {
+		log.Errorf("Error queuing the delivery of user event %s to webhook %s: %v", event.Id, subscription.Id, err)
+		return err
+	}
+	return nil
+}
+
+// retryQueuedDeliveries retries the queued deliveries every RetryPollInterval until ctx is done,
+// one worker per subscription, and drops the queues of the subscriptions no longer enabled
+func (s *webhookService) retryQueuedDeliveries(ctx context.Context) {
+	var workers sync.WaitGroup
+	defer workers.Wait()
+
+	ticker := time.NewTicker(s.opts.RetryPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		queuedIds, err := s.retries.ListQueuedWebhookSubscriptions(ctx)
+		if err != nil {
+			log.Errorf("Error listing the queued webhook subscriptions: %v", err)
+			continue
+		}
+		if len(queuedIds) == 0 {
+			continue
+		}
+		subscriptions, err := s.subscriptions.ListEnabledWebhookSubscriptions(ctx)
+		if err != nil {
+			log.Errorf("Error listing the webhook subscriptions to retry: %v", err)
+			continue
+		}
+		enabled := make(map[string]*WebhookSubscription, len(subscriptions))
+		for _, subscription := range subscriptions {
+			enabled[subscription.Id] = subscription
+		}
+
+		for _, id := range queuedIds {
+			subscription, ok := enabled[id]
+			if !ok {
+				dropped, err := s.retries.DeleteWebhookRetries(ctx, id)
+				if err != nil {
+					log.Errorf("Error dropping the queued deliveries of webhook %s: %v", id, err)
+				} else if dropped > 0 {
+					log.Warnf("Dropped the %d queued deliveries of disabled or deleted webhook %s", dropped, id)
+				}
+				continue
+			}
+			if !s.startRetrying(id) {
+				continue
+			}
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				defer s.stopRetrying(id)
+				s.retryQueue(ctx, subscription)
+			}()
+		}
+	}
+}
+
+// retryQueue delivers the queued events of the subscription in order, until its queue is empty
+// or the first retry is not due yet
+func (s *webhookService) retryQueue(ctx context.Context, subscription *WebhookSubscription) {
+	for ctx.Err() == nil {
+		retry, err := s.retries.GetFirstWebhookRetry(ctx, subscription.Id)
+		if errors.Is(err, ErrWebhookRetryNotFound) {
+			return
+		}
+		if err != nil {
+			log.Errorf("Error reading the queued deliveries of webhook %s: %v", subscription.Id, err)
+			return
+		}
+		if retry.NextAttemptAt.After(time.Now()) {
+			return
+		}
+
+		failure := s.attempt(ctx, subscription, retry.Event, retry.Delivery)
+		if ctx.Err() != nil {
+			return
+		}
+		if failure != nil && int(retry.Delivery.Attempts) < s.opts.MaxAttempts {
+			retry.NextAttemptAt = time.Now().Add(s.backoff(retry.Delivery.Attempts)).UTC().Round(time.Millisecond)
+			if err := s.retries.UpdateWebhookRetry(ctx, retry); err != nil {
+				log.Errorf("Error rescheduling the delivery of user event %s to webhook %s: %v", retry.Event.Id, subscription.Id, err)
+			}
+			return
+		}
+
+		s.finish(ctx, subscription, retry.Event, retry.Delivery, failure)
+		if err := s.retries.DeleteWebhookRetry(ctx, retry.Delivery.Id); err != nil {
+			log.Errorf("Error dequeuing the delivery of user event %s to webhook %s: %v", retry.Event.Id, subscription.Id, err)
+			return
+		}
+	}
+}
+
+func (s *webhookService) startRetrying(subscriptionId string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.retrying[subscriptionId] {
+		return false
+	}
+	s.retrying[subscriptionId] = true
+	return true
+}
+
+func (s *webhookService) stopRetrying(subscriptionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.retrying, subscriptionId)
+}
+
+// nextPosition returns an increasing position, following the clock for the positions to keep
+// increasing after a restart
+func (s *webhookService) nextPosition() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.position = max(s.position+1, time.Now().UnixNano())
+	return s.position
+}
+
+// backoff is the delay after the given number of attempts, doubled for every attempt up to MaxBackoff
+func (s *webhookService) backoff(attempts int32) time.Duration {
+	backoff := s.opts.InitialBackoff
+	for i := int32(1); i < attempts && backoff < s.opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, s.opts.MaxBackoff)
+}
+
+// attempt sends the event to the subscription once, and records the attempt on the delivery
+func (s *webhookService) attempt(ctx context.Context, subscription *WebhookSubscription, event *UserEvent, delivery *WebhookDelivery) error {
+	statusCode, err := s.sender.SendWebhook(ctx, subscription, event)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	delivery.Attempts++
+	delivery.StatusCode = int32(statusCode)
+	delivery.Error = ""
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	return err
+}
+
+// finish logs the delivery which succeeded or ran out of attempts, and records its outcome on the subscription
+func (s *webhookService) finish(ctx context.Context, subscription *WebhookSubscription, event *UserEvent, delivery *WebhookDelivery, failure error) {
+	delivery.Succeeded = failure == nil
+	if failure != nil {
+		log.Warnf("Failed to deliver user event %s to webhook %s after %d attempts: %v",
+			event.Id, subscription.Id, delivery.Attempts, failure)
+	}
+	delivery.FinishedAt = time.Now().UTC().Round(time.Millisecond)
+	if err := s.deliveries.AppendWebhookDelivery(ctx, delivery); err != nil {
+		log.Errorf("Error logging the delivery of user event %s to webhook %s: %v", event.Id, subscription.Id, err)
+	}
+
+	updated, err := s.subscriptions.RecordWebhookOutcome(ctx, subscription.Id, failure, s.opts.DisableAfterFailures)
+	if err != nil {
+		log.Errorf("Error recording the delivery of user event %s to webhook %s: %v", event.Id, subscription.Id, err)
+		return
+	}
+	if failure != nil && !updated.Enabled {
+		log.WithFields(log.Fields{"subscription_id": subscription.Id, "consecutive_failures": updated.ConsecutiveFailures}).
+			Warn("webhook subscription disabled")
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func withoutSecret(subscription *WebhookSubscription) *WebhookSubscription {
+	redacted := *subscription
+	redacted.Secret = ""
+	return &redacted
+}
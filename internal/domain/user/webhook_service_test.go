@@ -0,0 +1,400 @@
+//go:build unit
+
+package domain_test
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookService_CreateWebhookSubscription(t *testing.T) {
+	admin := &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}
+	tests := []struct {
+		name    string
+		actor   *domain.Actor
+		url     string
+		wantErr error
+	}{
+		{name: "created by an admin", actor: admin},
+		{name: "not an admin", actor: &domain.Actor{ID: "user-123"}, wantErr: domain.ErrPermissionDenied},
+		{name: "unauthenticated", wantErr: domain.ErrUnauthenticated},
+		{name: "plain http", actor: admin, url: "http://partner.example.com/hooks", wantErr: domain.ErrInvalidWebhookUrl},
+		{name: "no host", actor: admin, url: "https:///hooks", wantErr: domain.ErrInvalidWebhookUrl},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSubscriptions := new(mocks.MockWebhookSubscriptionRepository)
+			service := domain.NewWebhookService(mockSubscriptions, new(mocks.MockWebhookDeliveryRepository),
+				new(mocks.MockWebhookRetryRepository), new(mocks.MockWebhookSender), new(mocks.MockUserWatcher), domain.WebhookServiceOptions{})
+			if tt.wantErr == nil {
+				mockSubscriptions.On("CreateWebhookSubscription", mock.Anything, mock.AnythingOfType("*domain.WebhookSubscription")).Return(nil)
+			}
+
+			ctx := context.TODO()
+			if tt.actor != nil {
+				ctx = domain.ContextWithActor(ctx, tt.actor)
+			}
+			url := "https://partner.example.com/hooks"
+			if tt.url != "" {
+				url = tt.url
+			}
+			subscription, err := service.CreateWebhookSubscription(ctx, &domain.WebhookSubscription{
+				Url:            url,
+				OperationTypes: []domain.OperationType{domain.OPERATION_CREATE},
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, subscription)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, subscription.Id)
+				assert.Len(t, subscription.Secret, 64)
+				assert.True(t, subscription.Enabled)
+				assert.Equal(t, "admin-1", subscription.CreatedBy)
+				assert.False(t, subscription.CreatedAt.IsZero())
+			}
+			mockSubscriptions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookService_UpdateWebhookSubscription(t *testing.T) {
+	disabled := func() *domain.WebhookSubscription {
+		return &domain.WebhookSubscription{Id: "webhook-1", Url: "https://partner.example.com/hooks", Secret: "secret",
+			ConsecutiveFailures: 10, DisabledReason: "10 consecutive deliveries failed"}
+	}
+
+	tests := []struct {
+		name         string
+		req          *domain.UpdateWebhookSubscriptionRequest
+		existing     *domain.WebhookSubscription
+		getErr       error
+		wantFailures int32
+		wantSecret   bool
+		wantErr      error
+	}{
+		{
+			name:     "enabled again",
+			req:      &domain.UpdateWebhookSubscriptionRequest{Id: "webhook-1", Url: "https://partner.example.com/v2/hooks", Enabled: true},
+			existing: disabled(),
+		},
+		{
+			name:         "kept disabled with a rotated secret",
+			req:          &domain.UpdateWebhookSubscriptionRequest{Id: "webhook-1", Url: "https://partner.example.com/v2/hooks", RotateSecret: true},
+			existing:     disabled(),
+			wantFailures: 10,
+			wantSecret:   true,
+		},
+		{
+			name:    "not found",
+			req:     &domain.UpdateWebhookSubscriptionRequest{Id: "webhook-1", Url: "https://partner.example.com/v2/hooks"},
+			getErr:  domain.ErrWebhookSubscriptionNotFound,
+			wantErr: domain.ErrWebhookSubscriptionNotFound,
+		}, {
+			name:    "plain http",
+			req:     &domain.UpdateWebhookSubscriptionRequest{Id: "webhook-1", Url: "http://partner.example.com/v2/hooks", Enabled: true},
+			wantErr: domain.ErrInvalidWebhookUrl,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSubscriptions := new(mocks.MockWebhookSubscriptionRepository)
+			service := domain.NewWebhookService(mockSubscriptions, new(mocks.MockWebhookDeliveryRepository),
+				new(mocks.MockWebhookRetryRepository), new(mocks.MockWebhookSender), new(mocks.MockUserWatcher), domain.WebhookServiceOptions{})
+			if !errors.Is(tt.wantErr, domain.ErrInvalidWebhookUrl) {
+				mockSubscriptions.On("GetWebhookSubscription", mock.Anything, "webhook-1").Return(tt.existing, tt.getErr)
+			}
+			if tt.wantErr == nil {
+				mockSubscriptions.On("UpdateWebhookSubscription", mock.Anything, mock.MatchedBy(func(s *domain.WebhookSubscription) bool {
+					return s.Url == tt.req.Url && s.Enabled == tt.req.Enabled && s.ConsecutiveFailures == tt.wantFailures &&
+						s.Secret != "" && (s.Secret == "secret") != tt.wantSecret
+				})).Return(nil)
+			}
+
+			ctx := domain.ContextWithActor(context.TODO(), &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin})
+			subscription, err := service.UpdateWebhookSubscription(ctx, tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.req.Url, subscription.Url)
+				assert.Equal(t, tt.wantFailures, subscription.ConsecutiveFailures)
+				// The secret is only returned when rotated
+				assert.Equal(t, tt.wantSecret, subscription.Secret != "")
+			}
+			mockSubscriptions.AssertExpectations(t)
+		})
+	}
+}
+
+// webhookRetryQueue is an in-memory WebhookRetryRepository, the retries being kept by position
+type webhookRetryQueue struct {
+	mu      sync.Mutex
+	retries []*domain.WebhookRetry
+}
+
+func (q *webhookRetryQueue) EnqueueWebhookRetry(_ context.Context, retry *domain.WebhookRetry) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retries = append(q.retries, copyWebhookRetry(retry))
+	slices.SortFunc(q.retries, func(a, b *domain.WebhookRetry) int { return cmp.Compare(a.Position, b.Position) })
+	return nil
+}
+
+func (q *webhookRetryQueue) ListQueuedWebhookSubscriptions(context.Context) ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var ids []string
+	for _, retry := range q.retries {
+		if !slices.Contains(ids, retry.Delivery.SubscriptionId) {
+			ids = append(ids, retry.Delivery.SubscriptionId)
+		}
+	}
+	return ids, nil
+}
+
+func (q *webhookRetryQueue) GetFirstWebhookRetry(_ context.Context, subscriptionId string) (*domain.WebhookRetry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, retry := range q.retries {
+		if retry.Delivery.SubscriptionId == subscriptionId {
+			return copyWebhookRetry(retry), nil
+		}
+	}
+	return nil, domain.ErrWebhookRetryNotFound
+}
+
+func (q *webhookRetryQueue) UpdateWebhookRetry(_ context.Context, retry *domain.WebhookRetry) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, queued := range q.retries {
+		if queued.Delivery.Id == retry.Delivery.Id {
+			q.retries[i] = copyWebhookRetry(retry)
+			return nil
+		}
+	}
+	return domain.ErrWebhookRetryNotFound
+}
+
+func (q *webhookRetryQueue) DeleteWebhookRetry(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retries = slices.DeleteFunc(q.retries, func(r *domain.WebhookRetry) bool { return r.Delivery.Id == id })
+	return nil
+}
+
+func (q *webhookRetryQueue) DeleteWebhookRetries(_ context.Context, subscriptionId string) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	before := len(q.retries)
+	q.retries = slices.DeleteFunc(q.retries, func(r *domain.WebhookRetry) bool { return r.Delivery.SubscriptionId == subscriptionId })
+	return int64(before - len(q.retries)), nil
+}
+
+func (q *webhookRetryQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.retries)
+}
+
+func copyWebhookRetry(retry *domain.WebhookRetry) *domain.WebhookRetry {
+	copied := *retry
+	delivery := *retry.Delivery
+	copied.Delivery = &delivery
+	return &copied
+}
+
+// deliveryOptions retry right away, for the queued deliveries to be retried within the tests
+var deliveryOptions = domain.WebhookServiceOptions{
+	MaxAttempts:          3,
+	InitialBackoff:       time.Millisecond,
+	MaxBackoff:           2 * time.Millisecond,
+	DisableAfterFailures: 1,
+	RetryPollInterval:    time.Millisecond,
+}
+
+// waitFor waits for finished to be closed, then stops the delivery and waits for it to end
+func waitFor(t *testing.T, finished <-chan struct{}, cancel context.CancelFunc, done <-chan struct{}) {
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the delivery to finish")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the delivery to stop")
+	}
+}
+
+func TestWebhookService_StartDeliveringWebhooks(t *testing.T) {
+	sendErr := errors.New("unexpected status 503")
+
+	tests := []struct {
+		name         string
+		sendErrs     []error
+		wantAttempts int32
+		wantFailure  error
+	}{
+		{name: "delivered", sendErrs: []error{nil}, wantAttempts: 1},
+		{name: "delivered after a retry", sendErrs: []error{sendErr, nil}, wantAttempts: 2},
+		{name: "out of attempts", sendErrs: []error{sendErr, sendErr, sendErr}, wantAttempts: 3, wantFailure: sendErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &domain.UserEvent{Id: "event-1", UserId: "user-123", OperationType: domain.OPERATION_UPDATE}
+			subscribed := &domain.WebhookSubscription{Id: "webhook-1", Enabled: true}
+			filtered := &domain.WebhookSubscription{Id: "webhook-2", Enabled: true,
+				OperationTypes: []domain.OperationType{domain.OPERATION_CREATE}}
+
+			mockSubscriptions := new(mocks.MockWebhookSubscriptionRepository)
+			mockDeliveries := new(mocks.MockWebhookDeliveryRepository)
+			mockSender := new(mocks.MockWebhookSender)
+			mockWatcher := new(mocks.MockUserWatcher)
+			mockAcknowledger := new(mocks.MockUserEventAcknowledger)
+			retries := &webhookRetryQueue{}
+			service := domain.NewWebhookService(mockSubscriptions, mockDeliveries, retries, mockSender,
+				&acknowledgingWatcher{mockWatcher, mockAcknowledger}, deliveryOptions)
+
+			events := make(chan *domain.UserEvent, 1)
+			events <- event
+			close(events)
+			mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+			mockSubscriptions.On("ListEnabledWebhookSubscriptions", mock.Anything).
+				Return([]*domain.WebhookSubscription{subscribed, filtered}, nil)
+			for _, err := range tt.sendErrs {
+				statusCode := 204
+				if err != nil {
+					statusCode = 503
+				}
+				mockSender.On("SendWebhook", mock.Anything, subscribed, event).Return(statusCode, err).Once()
+			}
+			mockDeliveries.On("AppendWebhookDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+				return d.SubscriptionId == "webhook-1" && d.EventId == "event-1" && d.Attempts == tt.wantAttempts &&
+					d.Succeeded == (tt.wantFailure == nil) && !d.FinishedAt.IsZero()
+			})).Return(nil)
+			// The delivery finished once the outcome is recorded and the event acknowledged, in any order
+			var pending sync.WaitGroup
+			pending.Add(2)
+			mockSubscriptions.On("RecordWebhookOutcome", mock.Anything, "webhook-1", tt.wantFailure, int32(1)).
+				Run(func(mock.Arguments) { pending.Done() }).
+				Return(&domain.WebhookSubscription{Id: "webhook-1", Enabled: tt.wantFailure == nil}, nil)
+			mockAcknowledger.On("AckUserEvent", mock.Anything, event).Run(func(mock.Arguments) { pending.Done() }).Return(nil)
+			finished := make(chan struct{})
+			go func() {
+				defer close(finished)
+				pending.Wait()
+			}()
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			waitFor(t, finished, cancel, service.StartDeliveringWebhooks(ctx))
+
+			assert.Zero(t, retries.len())
+			mockSender.AssertExpectations(t)
+			mockDeliveries.AssertExpectations(t)
+			mockSubscriptions.AssertExpectations(t)
+			mockAcknowledger.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookService_StartDeliveringWebhooks_Queued(t *testing.T) {
+	queuedEvent := &domain.UserEvent{Id: "event-1", UserId: "user-123", OperationType: domain.OPERATION_UPDATE}
+	event := &domain.UserEvent{Id: "event-2", UserId: "user-123", OperationType: domain.OPERATION_UPDATE}
+	failing := &domain.WebhookSubscription{Id: "webhook-1", Enabled: true}
+	healthy := &domain.WebhookSubscription{Id: "webhook-2", Enabled: true}
+
+	mockSubscriptions := new(mocks.MockWebhookSubscriptionRepository)
+	mockDeliveries := new(mocks.MockWebhookDeliveryRepository)
+	mockSender := new(mocks.MockWebhookSender)
+	mockWatcher := new(mocks.MockUserWatcher)
+	mockAcknowledger := new(mocks.MockUserEventAcknowledger)
+	// The failing endpoint has the previous event queued, not due before the end of the test
+	retries := &webhookRetryQueue{}
+	assert.NoError(t, retries.EnqueueWebhookRetry(context.TODO(), &domain.WebhookRetry{
+		Delivery: &domain.WebhookDelivery{Id: "delivery-1", SubscriptionId: "webhook-1", EventId: "event-1", Attempts: 1},
+		Event:    queuedEvent, Position: 1, NextAttemptAt: time.Now().Add(time.Hour),
+	}))
+	service := domain.NewWebhookService(mockSubscriptions, mockDeliveries, retries, mockSender,
+		&acknowledgingWatcher{mockWatcher, mockAcknowledger}, deliveryOptions)
+
+	events := make(chan *domain.UserEvent, 1)
+	events <- event
+	close(events)
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+	mockSubscriptions.On("ListEnabledWebhookSubscriptions", mock.Anything).
+		Return([]*domain.WebhookSubscription{failing, healthy}, nil)
+	mockSender.On("SendWebhook", mock.Anything, healthy, event).Return(204, nil).Once()
+	mockDeliveries.On("AppendWebhookDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.SubscriptionId == "webhook-2" && d.Succeeded
+	})).Return(nil)
+	var pending sync.WaitGroup
+	pending.Add(2)
+	mockSubscriptions.On("RecordWebhookOutcome", mock.Anything, "webhook-2", nil, int32(1)).
+		Run(func(mock.Arguments) { pending.Done() }).Return(healthy, nil)
+	mockAcknowledger.On("AckUserEvent", mock.Anything, event).Run(func(mock.Arguments) { pending.Done() }).Return(nil)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		pending.Wait()
+	}()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	waitFor(t, finished, cancel, service.StartDeliveringWebhooks(ctx))
+
+	// The event is queued behind the previous one without an attempt, the healthy endpoint received it
+	first, err := retries.GetFirstWebhookRetry(context.TODO(), "webhook-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", first.Event.Id)
+	assert.Equal(t, 2, retries.len())
+	mockSender.AssertExpectations(t)
+	mockDeliveries.AssertExpectations(t)
+	mockSubscriptions.AssertExpectations(t)
+	mockAcknowledger.AssertExpectations(t)
+}
+
+func TestWebhookService_StartDeliveringWebhooks_DisabledQueue(t *testing.T) {
+	mockSubscriptions := new(mocks.MockWebhookSubscriptionRepository)
+	mockWatcher := new(mocks.MockUserWatcher)
+	retries := &webhookRetryQueue{}
+	for i, id := range []string{"delivery-1", "delivery-2"} {
+		assert.NoError(t, retries.EnqueueWebhookRetry(context.TODO(), &domain.WebhookRetry{
+			Delivery: &domain.WebhookDelivery{Id: id, SubscriptionId: "webhook-1", Attempts: 1},
+			Event:    &domain.UserEvent{Id: "event-1"}, Position: int64(i), NextAttemptAt: time.Now(),
+		}))
+	}
+	service := domain.NewWebhookService(mockSubscriptions, new(mocks.MockWebhookDeliveryRepository), retries,
+		new(mocks.MockWebhookSender), mockWatcher, deliveryOptions)
+
+	events := make(chan *domain.UserEvent)
+	close(events)
+	mockWatcher.On("WatchUsers", mock.Anything).Return((<-chan *domain.UserEvent)(events))
+	// The subscription was disabled or deleted: its queue is dropped without any attempt
+	mockSubscriptions.On("ListEnabledWebhookSubscriptions", mock.Anything).Return([]*domain.WebhookSubscription{}, nil)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	done := service.StartDeliveringWebhooks(ctx)
+
+	assert.Eventually(t, func() bool { return retries.len() == 0 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
@@ -0,0 +1,96 @@
+//go:build unit
+
+package auth_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+var secret = []byte("test-secret")
+
+// sign encodes header and claims as a token signed with key
+func sign(t *testing.T, key []byte, header, claims map[string]any) string {
+	encode := func(v map[string]any) string {
+		data, err := json.Marshal(v)
+		assert.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	unsigned := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTVerifier_VerifyToken(t *testing.T) {
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name        string
+		token       string
+		wantedActor *domain.Actor
+	}{
+		{
+			name:        "valid token",
+			token:       sign(t, secret, hs256, map[string]any{"sub": "admin-1", "role": "admin", "exp": future}),
+			wantedActor: &domain.Actor{ID: "admin-1", Role: "admin"},
+		},
+		{
+			name:        "without role",
+			token:       sign(t, secret, hs256, map[string]any{"sub": "user-123", "exp": future}),
+			wantedActor: &domain.Actor{ID: "user-123"},
+		},
+		{
+			name:  "without expiry",
+			token: sign(t, secret, hs256, map[string]any{"sub": "user-123"}),
+		},
+		{
+			name:  "other secret",
+			token: sign(t, []byte("other-secret"), hs256, map[string]any{"sub": "admin-1", "role": "admin", "exp": future}),
+		},
+		{
+			name:  "none algorithm",
+			token: sign(t, secret, map[string]any{"alg": "none"}, map[string]any{"sub": "admin-1", "role": "admin", "exp": future}),
+		},
+		{
+			name:  "expired",
+			token: sign(t, secret, hs256, map[string]any{"sub": "admin-1", "exp": past}),
+		},
+		{
+			name:  "not valid yet",
+			token: sign(t, secret, hs256, map[string]any{"sub": "admin-1", "exp": future, "nbf": future}),
+		},
+		{
+			name:  "missing subject",
+			token: sign(t, secret, hs256, map[string]any{"role": "admin", "exp": future}),
+		},
+		{
+			name:  "malformed",
+			token: "admin-1",
+		},
+	}
+
+	verifier := auth.NewJWTVerifier(secret)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, err := verifier.VerifyToken(tt.token)
+			if tt.wantedActor == nil {
+				assert.ErrorIs(t, err, domain.ErrInvalidToken)
+				assert.Nil(t, actor)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantedActor, actor)
+		})
+	}
+}
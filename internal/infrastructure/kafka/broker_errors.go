@@ -0,0 +1,34 @@
+package kafka
+
+import (
+	"errors"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// brokerUnavailableCodes are the delivery failures caused by the brokers being unreachable or
+// unable to accept the messages, as opposed to the messages themselves being rejected
+var brokerUnavailableCodes = map[kafka.ErrorCode]bool{
+	kafka.ErrTransport:                    true,
+	kafka.ErrAllBrokersDown:               true,
+	kafka.ErrMsgTimedOut:                  true,
+	kafka.ErrTimedOut:                     true,
+	kafka.ErrTimedOutQueue:                true,
+	kafka.ErrQueueFull:                    true,
+	kafka.ErrLeaderNotAvailable:           true,
+	kafka.ErrNotLeaderForPartition:        true,
+	kafka.ErrRequestTimedOut:              true,
+	kafka.ErrBrokerNotAvailable:           true,
+	kafka.ErrNetworkException:             true,
+	kafka.ErrNotEnoughReplicas:            true,
+	kafka.ErrNotEnoughReplicasAfterAppend: true,
+}
+
+// IsBrokerUnavailable tells whether a SendMessage failure is due to Kafka being unavailable,
+// in which case sending the same event again later can succeed
+func IsBrokerUnavailable(err error) bool {
+	var kafkaErr kafka.Error
+	if !errors.As(err, &kafkaErr) {
+		return false
+	}
+	return brokerUnavailableCodes[kafkaErr.Code()] || kafkaErr.IsTimeout()
+}
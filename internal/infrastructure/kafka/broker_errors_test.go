@@ -0,0 +1,49 @@
+//go:build unit
+
+package kafka_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	kafkaClient "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBrokerUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "message timed out",
+			err:  kafka.NewError(kafka.ErrMsgTimedOut, "Local: Message timed out", false),
+			want: true,
+		},
+		{
+			name: "all brokers down on one of the routes",
+			err: errors.Join(fmt.Errorf("topic users: %w", errors.New("unknown schema")),
+				fmt.Errorf("topic users-public: %w", kafka.NewError(kafka.ErrAllBrokersDown, "Local: All broker connections are down", false))),
+			want: true,
+		},
+		{
+			name: "message rejected",
+			err:  kafka.NewError(kafka.ErrMsgSizeTooLarge, "Broker: Message size too large", false),
+			want: false,
+		},
+		{
+			name: "encoding failure",
+			err:  fmt.Errorf("topic users: %w", errors.New("unknown schema")),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, kafkaClient.IsBrokerUnavailable(tt.err))
+		})
+	}
+}
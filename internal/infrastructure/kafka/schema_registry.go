@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SchemaRegistry looks up the schemas registered in a Confluent compatible schema registry
+type SchemaRegistry interface {
+	// LatestSchemaId returns the id of the latest schema version registered under subject
+	LatestSchemaId(subject string) (int, error)
+}
+
+type schemaRegistryClient struct {
+	baseUrl string
+	client  *http.Client
+}
+
+func NewSchemaRegistryClient(baseUrl string) SchemaRegistry {
+	return &schemaRegistryClient{
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *schemaRegistryClient) LatestSchemaId(subject string) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/subjects/%s/versions/latest", c.baseUrl, url.PathEscape(subject)), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registry returned %s", res.Status)
+	}
+
+	var schema struct {
+		Id int `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&schema); err != nil {
+		return 0, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return schema.Id, nil
+}
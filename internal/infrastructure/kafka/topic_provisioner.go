@@ -0,0 +1,125 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	CleanupPolicyDelete  = "delete"
+	CleanupPolicyCompact = "compact"
+
+	cleanupPolicyConfig = "cleanup.policy"
+)
+
+var ErrTopicMismatch = errors.New("topic does not match its configuration")
+
+// TopicSpec is the expected configuration of a topic
+type TopicSpec struct {
+	Name              string
+	Partitions        int
+	ReplicationFactor int
+	CleanupPolicy     string
+}
+
+// TopicProvisioner creates the missing topics, and checks the existing ones
+type TopicProvisioner struct {
+	admin   *kafka.AdminClient
+	timeout time.Duration
+}
+
+func NewTopicProvisioner(admin *kafka.AdminClient, timeout time.Duration) *TopicProvisioner {
+	return &TopicProvisioner{
+		admin:   admin,
+		timeout: timeout,
+	}
+}
+
+// ProvisionTopics ensures the topics with an admin client sharing the connections of broker
+func ProvisionTopics(ctx context.Context, broker *kafka.Producer, timeout time.Duration, specs []TopicSpec) error {
+	admin, err := kafka.NewAdminClientFromProducer(broker)
+	if err != nil {
+		return fmt.Errorf("failed to create admin client: %w", err)
+	}
+	defer admin.Close()
+	return NewTopicProvisioner(admin, timeout).EnsureTopics(ctx, specs)
+}
+
+// EnsureTopics creates the missing topics. It returns ErrTopicMismatch when an existing
+// topic has other partitions, replication factor or cleanup policy than its spec.
+func (p *TopicProvisioner) EnsureTopics(ctx context.Context, specs []TopicSpec) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	// Requesting every topic, unlike a single one, never auto-creates it
+	metadata, err := p.admin.GetMetadata(nil, true, int(p.timeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+
+	missing := make([]kafka.TopicSpecification, 0)
+	for _, spec := range specs {
+		topic, exists := metadata.Topics[spec.Name]
+		if !exists || topic.Error.Code() == kafka.ErrUnknownTopicOrPart {
+			missing = append(missing, kafka.TopicSpecification{
+				Topic:             spec.Name,
+				NumPartitions:     spec.Partitions,
+				ReplicationFactor: spec.ReplicationFactor,
+				Config:            map[string]string{cleanupPolicyConfig: spec.CleanupPolicy},
+			})
+			continue
+		}
+		if err := p.checkTopic(ctx, spec, topic); err != nil {
+			return err
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	results, err := p.admin.CreateTopics(ctx, missing, kafka.SetAdminOperationTimeout(p.timeout))
+	if err != nil {
+		return fmt.Errorf("failed to create topics: %w", err)
+	}
+	for _, result := range results {
+		switch result.Error.Code() {
+		case kafka.ErrNoError:
+			log.Infof("Created topic %s.", result.Topic)
+		case kafka.ErrTopicAlreadyExists:
+			// Created concurrently, e.g. by another instance
+		default:
+			return fmt.Errorf("failed to create topic %s: %w", result.Topic, result.Error)
+		}
+	}
+	return nil
+}
+
+func (p *TopicProvisioner) checkTopic(ctx context.Context, spec TopicSpec, topic kafka.TopicMetadata) error {
+	if len(topic.Partitions) != spec.Partitions {
+		return fmt.Errorf("%w: %s has %d partitions, expected %d", ErrTopicMismatch, spec.Name, len(topic.Partitions), spec.Partitions)
+	}
+	for _, partition := range topic.Partitions {
+		if len(partition.Replicas) != spec.ReplicationFactor {
+			return fmt.Errorf("%w: %s has a replication factor of %d, expected %d",
+				ErrTopicMismatch, spec.Name, len(partition.Replicas), spec.ReplicationFactor)
+		}
+	}
+
+	results, err := p.admin.DescribeConfigs(ctx, []kafka.ConfigResource{{Type: kafka.ResourceTopic, Name: spec.Name}})
+	if err != nil {
+		return fmt.Errorf("failed to describe topic %s: %w", spec.Name, err)
+	}
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return fmt.Errorf("failed to describe topic %s: %w", spec.Name, result.Error)
+		}
+		if policy := result.Config[cleanupPolicyConfig].Value; policy != spec.CleanupPolicy {
+			return fmt.Errorf("%w: %s has cleanup policy %q, expected %q", ErrTopicMismatch, spec.Name, policy, spec.CleanupPolicy)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/flapenna/go-ddd-crud/pkg/userevents"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"sync"
+)
+
+const (
+	// EncodingProtobuf serializes the user events as raw protobuf
+	EncodingProtobuf = "protobuf"
+	// EncodingJSON serializes the user events as canonical protojson
+	EncodingJSON = "json"
+	// EncodingSchemaRegistry serializes the user events in the Confluent Schema Registry wire format
+	EncodingSchemaRegistry = "schema-registry"
+)
+
+const (
+	protobufContentType       = userevents.ContentTypeProtobuf
+	jsonContentType           = userevents.ContentTypeJSON
+	schemaRegistryContentType = userevents.ContentTypeSchemaRegistry
+
+	schemaRegistryMagicByte = 0x0
+)
+
+// UserEventEncoder serializes the user events written to Kafka
+type UserEventEncoder interface {
+	Encode(event *pb.UserEvent) ([]byte, error)
+	// ContentType is sent in the content-type header of the messages
+	ContentType() string
+}
+
+// NewUserEventEncoder creates the encoder of the given encoding, for the events sent to topic
+func NewUserEventEncoder(encoding string, schemaRegistryUrl string, topic string) (UserEventEncoder, error) {
+	switch encoding {
+	case EncodingProtobuf:
+		return NewProtobufEncoder(), nil
+	case EncodingJSON:
+		return NewJSONEncoder(), nil
+	case EncodingSchemaRegistry:
+		// The schema is looked up under the subject of the topic name strategy
+		return NewSchemaRegistryEncoder(NewSchemaRegistryClient(schemaRegistryUrl), topic+"-value"), nil
+	default:
+		return nil, fmt.Errorf("unknown kafka event encoding %q", encoding)
+	}
+}
+
+type protobufEncoder struct{}
+
+func NewProtobufEncoder() UserEventEncoder {
+	return protobufEncoder{}
+}
+
+func (protobufEncoder) Encode(event *pb.UserEvent) ([]byte, error) {
+	return proto.Marshal(event)
+}
+
+func (protobufEncoder) ContentType() string {
+	return protobufContentType
+}
+
+type jsonEncoder struct{}
+
+func NewJSONEncoder() UserEventEncoder {
+	return jsonEncoder{}
+}
+
+func (jsonEncoder) Encode(event *pb.UserEvent) ([]byte, error) {
+	return protojson.Marshal(event)
+}
+
+func (jsonEncoder) ContentType() string {
+	return jsonContentType
+}
+
+// schemaRegistryEncoder prefixes the protobuf event with the magic byte, the schema id
+// registered under subject and the message indexes. The schema id is looked up once.
+type schemaRegistryEncoder struct {
+	registry SchemaRegistry
+	subject  string
+
+	mu       sync.Mutex
+	schemaId int
+}
+
+func NewSchemaRegistryEncoder(registry SchemaRegistry, subject string) UserEventEncoder {
+	return &schemaRegistryEncoder{registry: registry, subject: subject}
+}
+
+func (e *schemaRegistryEncoder) Encode(event *pb.UserEvent) ([]byte, error) {
+	schemaId, err := e.lookupSchemaId()
+	if err != nil {
+		return nil, err
+	}
+	value, err := proto.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, 0, 6+len(value))
+	payload = append(payload, schemaRegistryMagicByte)
+	payload = binary.BigEndian.AppendUint32(payload, uint32(schemaId))
+	// UserEvent is the first message of user_event.proto: its indexes [0] are encoded as a single 0
+	payload = append(payload, 0)
+	return append(payload, value...), nil
+}
+
+func (e *schemaRegistryEncoder) ContentType() string {
+	return schemaRegistryContentType
+}
+
+func (e *schemaRegistryEncoder) lookupSchemaId() (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.schemaId != 0 {
+		return e.schemaId, nil
+	}
+	schemaId, err := e.registry.LatestSchemaId(e.subject)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up the schema of subject %s: %w", e.subject, err)
+	}
+	e.schemaId = schemaId
+	return schemaId, nil
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package kafka_test
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	kafkaClient "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func testUserEvent() *pb.UserEvent {
+	return &pb.UserEvent{
+		Id:            "event-1",
+		UserId:        "user-123",
+		AfterChange:   &pb.User{Id: "user-123", FirstName: "Federico"},
+		OperationType: pb.OperationType_OPERATION_CREATE,
+		Sequence:      1,
+	}
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	encoder := kafkaClient.NewProtobufEncoder()
+
+	value, err := encoder.Encode(testUserEvent())
+	require.NoError(t, err)
+
+	decoded := &pb.UserEvent{}
+	require.NoError(t, proto.Unmarshal(value, decoded))
+	assert.True(t, proto.Equal(testUserEvent(), decoded))
+	assert.Equal(t, "application/x-protobuf", encoder.ContentType())
+}
+
+func TestJSONEncoder(t *testing.T) {
+	encoder := kafkaClient.NewJSONEncoder()
+
+	value, err := encoder.Encode(testUserEvent())
+	require.NoError(t, err)
+
+	decoded := &pb.UserEvent{}
+	require.NoError(t, protojson.Unmarshal(value, decoded))
+	assert.True(t, proto.Equal(testUserEvent(), decoded))
+	assert.Contains(t, string(value), `"operationType":"OPERATION_CREATE"`)
+	assert.Equal(t, "application/json", encoder.ContentType())
+}
+
+func TestSchemaRegistryEncoder(t *testing.T) {
+	var lookups atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subjects/go-ddd-crud_user-event-value/versions/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		lookups.Add(1)
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		_, _ = w.Write([]byte(`{"subject":"go-ddd-crud_user-event-value","version":3,"id":42,"schema":"..."}`))
+	}))
+	defer registry.Close()
+
+	encoder := kafkaClient.NewSchemaRegistryEncoder(kafkaClient.NewSchemaRegistryClient(registry.URL), "go-ddd-crud_user-event-value")
+
+	for i := 0; i < 2; i++ {
+		value, err := encoder.Encode(testUserEvent())
+		require.NoError(t, err)
+
+		// magic byte, schema id, message indexes, then the protobuf event
+		require.Greater(t, len(value), 6)
+		assert.Equal(t, byte(0), value[0])
+		assert.Equal(t, uint32(42), binary.BigEndian.Uint32(value[1:5]))
+		assert.Equal(t, byte(0), value[5])
+		decoded := &pb.UserEvent{}
+		require.NoError(t, proto.Unmarshal(value[6:], decoded))
+		assert.True(t, proto.Equal(testUserEvent(), decoded))
+	}
+
+	// the schema id is only looked up once
+	assert.Equal(t, int32(1), lookups.Load())
+	assert.Equal(t, "application/vnd.confluent.protobuf", encoder.ContentType())
+}
+
+func TestSchemaRegistryEncoder_UnknownSubject(t *testing.T) {
+	registry := httptest.NewServer(http.NotFoundHandler())
+	defer registry.Close()
+
+	encoder := kafkaClient.NewSchemaRegistryEncoder(kafkaClient.NewSchemaRegistryClient(registry.URL), "unknown-value")
+
+	_, err := encoder.Encode(testUserEvent())
+	assert.ErrorContains(t, err, "404")
+}
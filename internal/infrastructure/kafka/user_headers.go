@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"strconv"
+	"time"
+)
+
+const (
+	EventTypeHeader     = "event_type"
+	SchemaVersionHeader = "schema_version"
+	ContentTypeHeader   = "content-type"
+	CorrelationIdHeader = "correlation_id"
+	TraceParentHeader   = "traceparent"
+	ReplayedHeader      = "replayed"
+
+	// UserEventSchemaVersion is the version of the pb.UserEvent schema carried in the value
+	UserEventSchemaVersion = "1"
+)
+
+const (
+	// MessageFormatPlain sends the user event with the standard headers only
+	MessageFormatPlain = "plain"
+	// MessageFormatCloudEvents also sends the CloudEvents 1.0 attributes, in Kafka binary mode
+	MessageFormatCloudEvents = "cloudevents"
+
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsTypePrefix  = "com.flapenna.go-ddd-crud."
+)
+
+// eventType is the routing key of the event, e.g. "user.created"
+func eventType(operationType domain.OperationType) string {
+	switch operationType {
+	case domain.OPERATION_CREATE:
+		return "user.created"
+	case domain.OPERATION_UPDATE:
+		return "user.updated"
+	case domain.OPERATION_DELETE:
+		return "user.deleted"
+	default:
+		return "user.unspecified"
+	}
+}
+
+// userEventHeaders returns the headers describing the event, so that consumers can route
+// and filter it without deserializing the value. With a CloudEvents source, the CloudEvents
+// attributes are added as ce_* headers, the content-type header being the datacontenttype.
+func userEventHeaders(event *domain.UserEvent, contentType string, cloudEventsSource string) []kafka.Header {
+	headers := []kafka.Header{
+		{Key: EventTypeHeader, Value: []byte(eventType(event.OperationType))},
+		{Key: SchemaVersionHeader, Value: []byte(UserEventSchemaVersion)},
+		{Key: ContentTypeHeader, Value: []byte(contentType)},
+		{Key: CorrelationIdHeader, Value: []byte(event.CorrelationId)},
+	}
+	if event.TraceParent != "" {
+		headers = append(headers, kafka.Header{Key: TraceParentHeader, Value: []byte(event.TraceParent)})
+	}
+	if event.Replayed {
+		headers = append(headers, kafka.Header{Key: ReplayedHeader, Value: []byte("true")})
+	}
+	if cloudEventsSource == "" {
+		return headers
+	}
+
+	headers = append(headers,
+		kafka.Header{Key: "ce_specversion", Value: []byte(cloudEventsSpecVersion)},
+		kafka.Header{Key: "ce_id", Value: []byte(event.Id)},
+		kafka.Header{Key: "ce_source", Value: []byte(cloudEventsSource)},
+		kafka.Header{Key: "ce_type", Value: []byte(cloudEventsTypePrefix + eventType(event.OperationType))},
+		kafka.Header{Key: "ce_subject", Value: []byte(event.UserId)},
+		kafka.Header{Key: "ce_sequence", Value: []byte(strconv.FormatInt(event.Sequence, 10))},
+	)
+	if !event.OccurredAt.IsZero() {
+		headers = append(headers, kafka.Header{Key: "ce_time", Value: []byte(event.OccurredAt.UTC().Format(time.RFC3339Nano))})
+	}
+	if event.CorrelationId != "" {
+		headers = append(headers, kafka.Header{Key: "ce_correlationid", Value: []byte(event.CorrelationId)})
+	}
+	if event.TraceParent != "" {
+		headers = append(headers, kafka.Header{Key: "ce_traceparent", Value: []byte(event.TraceParent)})
+	}
+	return headers
+}
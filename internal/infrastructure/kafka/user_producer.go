@@ -1,97 +1,139 @@
 package kafka
 
 import (
+	"errors"
+	"fmt"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/mapper"
 	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
 	log "github.com/sirupsen/logrus"
-	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type userProducer struct {
-	broker *kafka.Producer
-	topic  string
+	broker            *kafka.Producer
+	routes            []UserEventRoute
+	encoder           UserEventEncoder
+	cloudEventsSource string
 }
 
+// UserProducerOptions customizes the messages sent by the user producer
+type UserProducerOptions struct {
+	// Encoder serializes the events, raw protobuf when nil
+	Encoder UserEventEncoder
+	// CloudEventsSource enables the CloudEvents attributes, with this source, when not empty
+	CloudEventsSource string
+}
+
+// NewUserProducer creates a producer sending the user events to topic. A single loop
+// reads the delivery reports of broker and hands them to the waiting SendMessage calls;
+// it stops once broker is closed.
 func NewUserProducer(broker *kafka.Producer, topic string) domain.UserProducer {
-	return &userProducer{
-		broker,
-		topic,
+	return NewUserProducerWithOptions(broker, topic, UserProducerOptions{})
+}
+
+func NewUserProducerWithOptions(broker *kafka.Producer, topic string, opts UserProducerOptions) domain.UserProducer {
+	return NewRoutedUserProducer(broker, []UserEventRoute{{Topic: topic}}, opts)
+}
+
+// NewRoutedUserProducer creates a producer sending every user event to each of the routes
+func NewRoutedUserProducer(broker *kafka.Producer, routes []UserEventRoute, opts UserProducerOptions) domain.UserProducer {
+	encoder := opts.Encoder
+	if encoder == nil {
+		encoder = NewProtobufEncoder()
 	}
+	producer := &userProducer{
+		broker:            broker,
+		routes:            routes,
+		encoder:           encoder,
+		cloudEventsSource: opts.CloudEventsSource,
+	}
+	go producer.handleEvents()
+	return producer
 }
 
+// SendMessage blocks until the broker acknowledges the message on every route accepting it, or the
+// delivery fails once the producer retries are exhausted. A failed event is sent again
+// to every route, the consumers of the other topics deduplicate it by id.
 func (userProducer *userProducer) SendMessage(message *domain.UserEvent) error {
-	value, err := proto.Marshal(userEventToProto(message))
+	event := mapper.UserEventToProto(message)
+
+	deliveries := make(map[string]chan error, len(userProducer.routes))
+	var errs []error
+	for _, route := range userProducer.routes {
+		if !route.Accepts(message) {
+			continue
+		}
+		delivered, err := userProducer.produce(message, event, route)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("topic %s: %w", route.Topic, err))
+			continue
+		}
+		deliveries[route.Topic] = delivered
+	}
+	for topic, delivered := range deliveries {
+		if err := <-delivered; err != nil {
+			errs = append(errs, fmt.Errorf("topic %s: %w", topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// produce enqueues the event projected for the route, its delivery report is sent to the returned channel
+func (userProducer *userProducer) produce(message *domain.UserEvent, event *pb.UserEvent, route UserEventRoute) (chan error, error) {
+	if route.Projection != nil {
+		event = route.Projection.Project(event)
+	}
+	var value []byte
+	var headers []kafka.Header
+	var err error
+	if route.Snapshot {
+		value, err = userSnapshotValue(event)
+		headers = userSnapshotHeaders(message)
+	} else {
+		encoder := userProducer.encoder
+		if route.Encoder != nil {
+			encoder = route.Encoder
+		}
+		value, err = encoder.Encode(event)
+		headers = userEventHeaders(message, encoder.ContentType(), userProducer.cloudEventsSource)
+	}
 	if err != nil {
 		log.Error("unable to marshal message: ", err)
-		return err
+		return nil, err
 	}
 
+	topic := route.Topic
+	delivered := make(chan error, 1)
 	err = userProducer.broker.Produce(&kafka.Message{
 		Key:            []byte(message.UserId),
-		TopicPartition: kafka.TopicPartition{Topic: &userProducer.topic, Partition: kafka.PartitionAny},
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
 		Value:          value,
+		Headers:        headers,
+		Opaque:         delivered,
 	}, nil)
 	if err != nil {
 		log.Error("unable to enqueue message ", message)
-		return err
+		return nil, err
 	}
+	return delivered, nil
+}
 
-	go func() {
-		for e := range userProducer.broker.Events() {
-			switch ev := e.(type) {
-			case *kafka.Message:
-				if ev.TopicPartition.Error != nil {
-					log.Warnf("Failed to deliver message: %v\n", ev.TopicPartition.Error)
-				} else {
-					log.Infof("Successfully produced record to topic %s partition [%d] @ offset %v\n",
-						*ev.TopicPartition.Topic, ev.TopicPartition.Partition, ev.TopicPartition.Offset)
-				}
+func (userProducer *userProducer) handleEvents() {
+	for e := range userProducer.broker.Events() {
+		switch ev := e.(type) {
+		case *kafka.Message:
+			if ev.TopicPartition.Error != nil {
+				log.Warnf("Failed to deliver message: %v\n", ev.TopicPartition.Error)
+			} else {
+				log.Infof("Successfully produced record to topic %s partition [%d] @ offset %v\n",
+					*ev.TopicPartition.Topic, ev.TopicPartition.Partition, ev.TopicPartition.Offset)
 			}
+			if delivered, ok := ev.Opaque.(chan error); ok {
+				delivered <- ev.TopicPartition.Error
+			}
+		case kafka.Error:
+			log.Errorf("Kafka producer error: %v", ev)
 		}
-	}()
-
-	return nil
-}
-
-func userEventToProto(event *domain.UserEvent) *pb.UserEvent {
-	return &pb.UserEvent{
-		Id:            event.Id,
-		UserId:        event.UserId,
-		BeforeChange:  userToProto(event.BeforeChange),
-		AfterChange:   userToProto(event.AfterChange),
-		OperationType: operationTypeToProto(event.OperationType),
-	}
-}
-
-func userToProto(user *domain.User) *pb.User {
-	if user == nil {
-		return nil
-	}
-	return &pb.User{
-		Id:        user.ID,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
-		Country:   user.Country,
-		Nickname:  user.Nickname,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
-	}
-}
-
-func operationTypeToProto(operationType domain.OperationType) pb.OperationType {
-	switch operationType {
-	case domain.OPERATION_CREATE:
-		return pb.OperationType_OPERATION_CREATE
-	case domain.OPERATION_UPDATE:
-		return pb.OperationType_OPERATION_UPDATE
-	case domain.OPERATION_DELETE:
-		return pb.OperationType_OPERATION_DELETE
-	case domain.OPERATION_UNSPECIFIED:
-		return pb.OperationType_OPERATION_UNSPECIFIED
-	default:
-		return pb.OperationType_OPERATION_UNSPECIFIED
 	}
 }
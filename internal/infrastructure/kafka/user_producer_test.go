@@ -13,6 +13,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"testing"
 	"time"
 )
@@ -25,11 +26,12 @@ var (
 
 type UserProducerTestSuite struct {
 	suite.Suite
-	kafkaC   testcontainers.Container
-	producer *kafka.Producer
-	consumer *kafka.Consumer
-	ctx      context.Context
-	cancel   context.CancelFunc
+	kafkaC           testcontainers.Container
+	bootstrapServers string
+	producer         *kafka.Producer
+	consumer         *kafka.Consumer
+	ctx              context.Context
+	cancel           context.CancelFunc
 }
 
 func (suite *UserProducerTestSuite) SetupSuite() {
@@ -71,6 +73,7 @@ func (suite *UserProducerTestSuite) SetupSuite() {
 	suite.Require().NoError(err)
 
 	suite.kafkaC = kafkaC
+	suite.bootstrapServers = fmt.Sprintf("localhost:%d", mPort.Int())
 	suite.producer = producer
 	suite.consumer = consumer
 	suite.ctx, suite.cancel = context.WithTimeout(context.Background(), 30*time.Second)
@@ -90,6 +93,13 @@ func (suite *UserProducerTestSuite) TestUserProducer_SendMessage() {
 		BeforeChange:  &domain.User{},
 		AfterChange:   &domain.User{},
 		OperationType: domain.OPERATION_CREATE,
+		ModifiedBy:    "admin-1",
+		CorrelationId: "request-1",
+		TraceParent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		Sequence:      1,
+		ClusterTime:   7300000000000000001,
+		OccurredAt:    time.Now(),
+		ChangedFields: []string{"id"},
 	}
 
 	expectedUserEvent := &pb.UserEvent{
@@ -98,6 +108,11 @@ func (suite *UserProducerTestSuite) TestUserProducer_SendMessage() {
 		BeforeChange:  &pb.User{},
 		AfterChange:   &pb.User{},
 		OperationType: pb.OperationType_OPERATION_CREATE,
+		ModifiedBy:    "admin-1",
+		CorrelationId: "request-1",
+		Sequence:      1,
+		ClusterTime:   7300000000000000001,
+		ChangedFields: &fieldmaskpb.FieldMask{Paths: []string{"id"}},
 	}
 
 	// Create a userProducer
@@ -118,6 +133,228 @@ func (suite *UserProducerTestSuite) TestUserProducer_SendMessage() {
 	suite.Equal(expectedUserEvent.Id, userEventReceived.Id)
 	suite.Equal(expectedUserEvent.UserId, userEventReceived.UserId)
 	suite.Equal(expectedUserEvent.OperationType, userEventReceived.OperationType)
+	suite.Equal(expectedUserEvent.ModifiedBy, userEventReceived.ModifiedBy)
+	suite.Equal(expectedUserEvent.CorrelationId, userEventReceived.CorrelationId)
+	suite.Equal(expectedUserEvent.Sequence, userEventReceived.Sequence)
+	suite.Equal(expectedUserEvent.ClusterTime, userEventReceived.ClusterTime)
+	suite.True(userEvent.OccurredAt.Equal(userEventReceived.OccurredAt.AsTime()))
+	suite.Equal(expectedUserEvent.ChangedFields.Paths, userEventReceived.ChangedFields.Paths)
+
+	// assert the headers describe the event
+	headers := messageHeaders(message)
+	suite.Equal("user.created", headers[kafkaClient.EventTypeHeader])
+	suite.Equal(kafkaClient.UserEventSchemaVersion, headers[kafkaClient.SchemaVersionHeader])
+	suite.Equal("application/x-protobuf", headers[kafkaClient.ContentTypeHeader])
+	suite.Equal("request-1", headers[kafkaClient.CorrelationIdHeader])
+	suite.Equal(userEvent.TraceParent, headers[kafkaClient.TraceParentHeader])
+	suite.NotContains(headers, "ce_specversion")
+}
+
+func (suite *UserProducerTestSuite) TestUserProducer_SendMessage_CloudEvents() {
+	occurredAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	userEvent := &domain.UserEvent{
+		Id:            "3",
+		UserId:        "user_456",
+		AfterChange:   &domain.User{},
+		OperationType: domain.OPERATION_UPDATE,
+		CorrelationId: "request-3",
+		Sequence:      4,
+		OccurredAt:    occurredAt,
+	}
+
+	userProducer := kafkaClient.NewUserProducerWithOptions(suite.producer, testTopic, kafkaClient.UserProducerOptions{
+		CloudEventsSource: "/go-ddd-crud/users",
+	})
+	err := userProducer.SendMessage(userEvent)
+	suite.Require().NoError(err)
+
+	message, err := suite.consumer.ReadMessage(10 * time.Second)
+	suite.Require().NoError(err)
+
+	// In binary mode the value is still the protobuf event
+	userEventReceived := &pb.UserEvent{}
+	suite.Require().NoError(proto.Unmarshal(message.Value, userEventReceived))
+	suite.Equal("3", userEventReceived.Id)
+
+	headers := messageHeaders(message)
+	suite.Equal("1.0", headers["ce_specversion"])
+	suite.Equal("3", headers["ce_id"])
+	suite.Equal("/go-ddd-crud/users", headers["ce_source"])
+	suite.Equal("com.flapenna.go-ddd-crud.user.updated", headers["ce_type"])
+	suite.Equal("user_456", headers["ce_subject"])
+	suite.Equal("2024-05-01T10:00:00Z", headers["ce_time"])
+	suite.Equal("4", headers["ce_sequence"])
+	suite.Equal("request-3", headers["ce_correlationid"])
+	suite.Equal("application/x-protobuf", headers[kafkaClient.ContentTypeHeader])
+}
+
+func (suite *UserProducerTestSuite) TestUserProducer_SendMessage_Routes() {
+	restrictedTopic := "go-ddd-crud_user-event-restricted"
+	routes, err := kafkaClient.ParseUserEventRoutes(restrictedTopic+";"+testTopic+":email=mask,last_name=drop", nil)
+	suite.Require().NoError(err)
+
+	restrictedConsumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": suite.bootstrapServers,
+		"group.id":          "test-restricted-group",
+		"auto.offset.reset": "earliest",
+	})
+	suite.Require().NoError(err)
+	defer restrictedConsumer.Close()
+	suite.Require().NoError(restrictedConsumer.Subscribe(restrictedTopic, nil))
+
+	user := &domain.User{ID: "user_789", FirstName: "Federico", LastName: "Lapenna", Email: "federico@example.com"}
+	userProducer := kafkaClient.NewRoutedUserProducer(suite.producer, routes, kafkaClient.UserProducerOptions{})
+	err = userProducer.SendMessage(&domain.UserEvent{Id: "4", UserId: user.ID, AfterChange: user,
+		OperationType: domain.OPERATION_CREATE, ChangedFields: []string{"id", "first_name", "last_name", "email"}})
+	suite.Require().NoError(err)
+
+	// The public topic receives the redacted user
+	message, err := suite.consumer.ReadMessage(10 * time.Second)
+	suite.Require().NoError(err)
+	public := &pb.UserEvent{}
+	suite.Require().NoError(proto.Unmarshal(message.Value, public))
+	suite.Equal("4", public.Id)
+	suite.Equal("Federico", public.AfterChange.FirstName)
+	suite.Equal("f***@example.com", public.AfterChange.Email)
+	suite.Empty(public.AfterChange.LastName)
+	suite.Equal([]string{"id", "first_name", "email"}, public.ChangedFields.Paths)
+
+	// The restricted topic receives the full user
+	message, err = restrictedConsumer.ReadMessage(10 * time.Second)
+	suite.Require().NoError(err)
+	restricted := &pb.UserEvent{}
+	suite.Require().NoError(proto.Unmarshal(message.Value, restricted))
+	suite.Equal("4", restricted.Id)
+	suite.Equal("federico@example.com", restricted.AfterChange.Email)
+	suite.Equal("Lapenna", restricted.AfterChange.LastName)
+}
+
+func (suite *UserProducerTestSuite) TestUserSnapshotProducer_SendMessage() {
+	snapshotTopic := "go-ddd-crud_user-snapshot"
+	snapshotConsumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": suite.bootstrapServers,
+		"group.id":          "test-snapshot-group",
+		"auto.offset.reset": "earliest",
+	})
+	suite.Require().NoError(err)
+	defer snapshotConsumer.Close()
+	suite.Require().NoError(snapshotConsumer.Subscribe(snapshotTopic, nil))
+
+	user := &domain.User{ID: "user_snapshot", FirstName: "Federico", Email: "federico@example.com", Version: 2}
+	snapshotProducer := kafkaClient.NewUserSnapshotProducer(suite.producer, snapshotTopic)
+	suite.Require().NoError(snapshotProducer.SendMessage(&domain.UserEvent{Id: "5", UserId: user.ID, AfterChange: user,
+		OperationType: domain.OPERATION_UPDATE, Sequence: 2}))
+	suite.Require().NoError(snapshotProducer.SendMessage(&domain.UserEvent{Id: "6", UserId: user.ID, BeforeChange: user,
+		OperationType: domain.OPERATION_DELETE, Sequence: 3}))
+
+	// The latest state of the user, keyed by its id
+	message, err := snapshotConsumer.ReadMessage(10 * time.Second)
+	suite.Require().NoError(err)
+	suite.Equal(user.ID, string(message.Key))
+	snapshot := &pb.User{}
+	suite.Require().NoError(proto.Unmarshal(message.Value, snapshot))
+	suite.Equal("federico@example.com", snapshot.Email)
+	suite.Equal(int64(2), snapshot.Version)
+	suite.Equal("2", messageHeaders(message)[kafkaClient.UserVersionHeader])
+
+	// The deletion is a tombstone
+	message, err = snapshotConsumer.ReadMessage(10 * time.Second)
+	suite.Require().NoError(err)
+	suite.Equal(user.ID, string(message.Key))
+	suite.Nil(message.Value)
+	suite.Equal("3", messageHeaders(message)[kafkaClient.UserVersionHeader])
+}
+
+func (suite *UserProducerTestSuite) TestUserTopicReader_ReadPublishedUsers() {
+	eventTopic := "go-ddd-crud_user-event-reconciled"
+	snapshotTopic := "go-ddd-crud_user-snapshot-reconciled"
+	routes := []kafkaClient.UserEventRoute{
+		{Topic: eventTopic, Encoder: kafkaClient.NewJSONEncoder()},
+		{Topic: snapshotTopic, Snapshot: true},
+	}
+	userProducer := kafkaClient.NewRoutedUserProducer(suite.producer, routes, kafkaClient.UserProducerOptions{})
+	kept := &domain.User{ID: "user_kept", FirstName: "Federico", Version: 2}
+	deleted := &domain.User{ID: "user_deleted", FirstName: "Mario", Version: 1}
+	events := []*domain.UserEvent{
+		{Id: "7", UserId: kept.ID, AfterChange: &domain.User{ID: kept.ID, Version: 1}, OperationType: domain.OPERATION_CREATE, Sequence: 1},
+		{Id: "8", UserId: deleted.ID, AfterChange: deleted, OperationType: domain.OPERATION_CREATE, Sequence: 1},
+		{Id: "9", UserId: kept.ID, AfterChange: kept, OperationType: domain.OPERATION_UPDATE, Sequence: 2},
+		{Id: "10", UserId: deleted.ID, BeforeChange: deleted, OperationType: domain.OPERATION_DELETE, Sequence: 2},
+	}
+	for _, event := range events {
+		suite.Require().NoError(userProducer.SendMessage(event))
+	}
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": suite.bootstrapServers,
+		"group.id":          "test-reconciler-group",
+	})
+	suite.Require().NoError(err)
+	defer consumer.Close()
+	reader := kafkaClient.NewUserTopicReader(consumer, 10*time.Second)
+
+	// Both topics end with the latest state of each user
+	for _, topic := range []string{eventTopic, snapshotTopic} {
+		published, err := reader.ReadPublishedUsers(suite.ctx, topic)
+		suite.Require().NoError(err)
+		suite.Equal(map[string]*domain.PublishedUser{
+			kept.ID:    {UserId: kept.ID, Version: 2},
+			deleted.ID: {UserId: deleted.ID, Version: 2, Deleted: true},
+		}, published, topic)
+	}
+}
+
+func messageHeaders(message *kafka.Message) map[string]string {
+	headers := make(map[string]string, len(message.Headers))
+	for _, h := range message.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return headers
+}
+
+func (suite *UserProducerTestSuite) TestUserProducer_SendMessage_DeliveryFailure() {
+	// A producer that can never reach a broker
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":   "localhost:1",
+		"enable.idempotence":  true,
+		"delivery.timeout.ms": 1000,
+	})
+	suite.Require().NoError(err)
+	defer producer.Close()
+
+	userProducer := kafkaClient.NewUserProducer(producer, testTopic)
+
+	// The delivery failure is returned instead of only being logged
+	err = userProducer.SendMessage(&domain.UserEvent{Id: "2", UserId: "user_123", OperationType: domain.OPERATION_CREATE})
+	suite.Error(err)
+}
+
+func (suite *UserProducerTestSuite) TestTopicProvisioner_EnsureTopics() {
+	admin, err := kafka.NewAdminClientFromProducer(suite.producer)
+	suite.Require().NoError(err)
+	defer admin.Close()
+	provisioner := kafkaClient.NewTopicProvisioner(admin, 10*time.Second)
+
+	spec := kafkaClient.TopicSpec{
+		Name:              "go-ddd-crud_user-event-provisioned",
+		Partitions:        2,
+		ReplicationFactor: 1,
+		CleanupPolicy:     kafkaClient.CleanupPolicyDelete,
+	}
+
+	// The missing topic is created, then found matching its spec
+	suite.Require().NoError(provisioner.EnsureTopics(suite.ctx, []kafkaClient.TopicSpec{spec}))
+	suite.Require().NoError(provisioner.EnsureTopics(suite.ctx, []kafkaClient.TopicSpec{spec}))
+
+	mismatched := spec
+	mismatched.Partitions = 3
+	err = provisioner.EnsureTopics(suite.ctx, []kafkaClient.TopicSpec{mismatched})
+	suite.ErrorIs(err, kafkaClient.ErrTopicMismatch)
+
+	mismatched = spec
+	mismatched.CleanupPolicy = kafkaClient.CleanupPolicyCompact
+	err = provisioner.EnsureTopics(suite.ctx, []kafkaClient.TopicSpec{mismatched})
+	suite.ErrorIs(err, kafkaClient.ErrTopicMismatch)
 }
 
 func TestUserProducerTestSuite(t *testing.T) {
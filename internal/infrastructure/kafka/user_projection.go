@@ -0,0 +1,144 @@
+package kafka
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"google.golang.org/protobuf/proto"
+	"strings"
+)
+
+// FieldAction is applied by a projection to a user field of the published events
+type FieldAction string
+
+const (
+	FieldActionKeep FieldAction = "keep"
+	// FieldActionHash replaces the value with its hex HMAC-SHA256, so consumers can still join on it
+	FieldActionHash FieldAction = "hash"
+	// FieldActionMask keeps the first character, and the domain of an email
+	FieldActionMask FieldAction = "mask"
+	// FieldActionDrop clears the field and removes it from the changed fields
+	FieldActionDrop FieldAction = "drop"
+)
+
+const maskSuffix = "***"
+
+var ErrMissingHashKey = errors.New("hashing user fields requires a hash key")
+
+// projectableFields tells whether the user field holds a string, which can be hashed or masked
+var projectableFields = map[string]bool{
+	domain.UserFieldFirstName: true,
+	domain.UserFieldLastName:  true,
+	domain.UserFieldEmail:     true,
+	domain.UserFieldCountry:   true,
+	domain.UserFieldNickname:  true,
+	domain.UserFieldCreatedAt: false,
+	domain.UserFieldUpdatedAt: false,
+}
+
+// UserEventProjection redacts the users of the events sent to a topic
+type UserEventProjection struct {
+	fields  map[string]FieldAction
+	hashKey []byte
+}
+
+// NewUserEventProjection creates a projection applying an action to each of the given user
+// fields, by protobuf path. The fields left out are kept.
+func NewUserEventProjection(fields map[string]FieldAction, hashKey []byte) (*UserEventProjection, error) {
+	for field, action := range fields {
+		isString, ok := projectableFields[field]
+		if !ok {
+			return nil, fmt.Errorf("user field %q cannot be projected", field)
+		}
+		switch action {
+		case FieldActionKeep, FieldActionDrop:
+		case FieldActionHash, FieldActionMask:
+			if !isString {
+				return nil, fmt.Errorf("user field %q cannot be %sed", field, action)
+			}
+			if action == FieldActionHash && len(hashKey) == 0 {
+				return nil, ErrMissingHashKey
+			}
+		default:
+			return nil, fmt.Errorf("unknown action %q for user field %q", action, field)
+		}
+	}
+	return &UserEventProjection{fields: fields, hashKey: hashKey}, nil
+}
+
+// Project returns a redacted copy of the event
+func (p *UserEventProjection) Project(event *pb.UserEvent) *pb.UserEvent {
+	projected := proto.Clone(event).(*pb.UserEvent)
+	p.projectUser(projected.BeforeChange)
+	p.projectUser(projected.AfterChange)
+
+	if projected.ChangedFields != nil {
+		paths := make([]string, 0, len(projected.ChangedFields.Paths))
+		for _, path := range projected.ChangedFields.Paths {
+			if p.fields[path] != FieldActionDrop {
+				paths = append(paths, path)
+			}
+		}
+		projected.ChangedFields.Paths = paths
+	}
+	return projected
+}
+
+func (p *UserEventProjection) projectUser(user *pb.User) {
+	if user == nil {
+		return
+	}
+	for field, action := range p.fields {
+		switch field {
+		case domain.UserFieldFirstName:
+			user.FirstName = p.projectString(user.FirstName, action)
+		case domain.UserFieldLastName:
+			user.LastName = p.projectString(user.LastName, action)
+		case domain.UserFieldEmail:
+			user.Email = p.projectString(user.Email, action)
+		case domain.UserFieldCountry:
+			user.Country = p.projectString(user.Country, action)
+		case domain.UserFieldNickname:
+			user.Nickname = p.projectString(user.Nickname, action)
+		case domain.UserFieldCreatedAt:
+			if action == FieldActionDrop {
+				user.CreatedAt = nil
+			}
+		case domain.UserFieldUpdatedAt:
+			if action == FieldActionDrop {
+				user.UpdatedAt = nil
+			}
+		}
+	}
+}
+
+func (p *UserEventProjection) projectString(value string, action FieldAction) string {
+	if value == "" {
+		return value
+	}
+	switch action {
+	case FieldActionHash:
+		mac := hmac.New(sha256.New, p.hashKey)
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))
+	case FieldActionMask:
+		return maskString(value)
+	case FieldActionDrop:
+		return ""
+	default:
+		return value
+	}
+}
+
+// maskString keeps the first character of the value, and the domain of an email
+func maskString(value string) string {
+	first := []rune(value)[0]
+	if at := strings.LastIndex(value, "@"); at > 0 {
+		return string(first) + maskSuffix + value[at:]
+	}
+	return string(first) + maskSuffix
+}
@@ -0,0 +1,70 @@
+//go:build unit
+
+package kafka_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	kafkaClient "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUserEventProjection_Project(t *testing.T) {
+	hashKey := []byte("secret")
+	mac := hmac.New(sha256.New, hashKey)
+	mac.Write([]byte("federico@example.com"))
+	hashedEmail := hex.EncodeToString(mac.Sum(nil))
+
+	projection, err := kafkaClient.NewUserEventProjection(map[string]kafkaClient.FieldAction{
+		"email":      kafkaClient.FieldActionHash,
+		"first_name": kafkaClient.FieldActionMask,
+		"last_name":  kafkaClient.FieldActionDrop,
+		"created_at": kafkaClient.FieldActionDrop,
+		"country":    kafkaClient.FieldActionKeep,
+	}, hashKey)
+	require.NoError(t, err)
+
+	event := &pb.UserEvent{
+		Id:     "event-1",
+		UserId: "user-123",
+		BeforeChange: &pb.User{Id: "user-123", FirstName: "Federico", LastName: "Lapenna", Email: "federico@example.org",
+			Country: "IT", CreatedAt: timestamppb.Now()},
+		AfterChange: &pb.User{Id: "user-123", FirstName: "Federico", LastName: "Rossi", Email: "federico@example.com",
+			Country: "IT", CreatedAt: timestamppb.Now()},
+		OperationType: pb.OperationType_OPERATION_UPDATE,
+		ChangedFields: &fieldmaskpb.FieldMask{Paths: []string{"last_name", "email", "updated_at"}},
+	}
+
+	projected := projection.Project(event)
+
+	assert.Equal(t, "F***", projected.AfterChange.FirstName)
+	assert.Empty(t, projected.AfterChange.LastName)
+	assert.Empty(t, projected.BeforeChange.LastName)
+	assert.Equal(t, hashedEmail, projected.AfterChange.Email)
+	assert.NotEqual(t, projected.AfterChange.Email, projected.BeforeChange.Email)
+	assert.Equal(t, "IT", projected.AfterChange.Country)
+	assert.Nil(t, projected.AfterChange.CreatedAt)
+	assert.Equal(t, []string{"email", "updated_at"}, projected.ChangedFields.Paths)
+
+	// the event itself is left untouched
+	assert.Equal(t, "federico@example.com", event.AfterChange.Email)
+	assert.Equal(t, "Rossi", event.AfterChange.LastName)
+	assert.Equal(t, []string{"last_name", "email", "updated_at"}, event.ChangedFields.Paths)
+}
+
+func TestUserEventProjection_MaskEmail(t *testing.T) {
+	projection, err := kafkaClient.NewUserEventProjection(map[string]kafkaClient.FieldAction{
+		"email": kafkaClient.FieldActionMask,
+	}, nil)
+	require.NoError(t, err)
+
+	projected := projection.Project(&pb.UserEvent{AfterChange: &pb.User{Email: "federico@example.com"}})
+	assert.Equal(t, "f***@example.com", projected.AfterChange.Email)
+}
@@ -0,0 +1,93 @@
+package kafka
+
+import (
+	"fmt"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"strings"
+)
+
+// operationNames are the operations a route can be restricted to
+var operationNames = map[string]domain.OperationType{
+	"create": domain.OPERATION_CREATE,
+	"update": domain.OPERATION_UPDATE,
+	"delete": domain.OPERATION_DELETE,
+}
+
+// UserEventRoute sends the user events to Topic, redacted by Projection when set
+type UserEventRoute struct {
+	Topic string
+	// Operations restricts the route to the events of these operations, every event is sent when empty
+	Operations []domain.OperationType
+	Projection *UserEventProjection
+	// Encoder overrides the encoder of the producer for this topic
+	Encoder UserEventEncoder
+	// Snapshot sends the protobuf user after the change instead of the event, keyed by user id
+	// for a compacted topic. Deletions are sent as tombstones.
+	Snapshot bool
+}
+
+// Accepts tells whether the event is sent to the route
+func (r UserEventRoute) Accepts(event *domain.UserEvent) bool {
+	if len(r.Operations) == 0 {
+		return true
+	}
+	for _, operation := range r.Operations {
+		if operation == event.OperationType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseUserEventRoutes parses routes separated by semicolons. Each is made of a topic, optionally
+// followed by @ and the | separated operations it is restricted to, then optionally by a colon
+// and comma separated field=action projections, e.g.
+// "users-restricted;users-public:email=hash,first_name=mask;users-deleted@delete"
+func ParseUserEventRoutes(spec string, hashKey []byte) ([]UserEventRoute, error) {
+	routes := make([]UserEventRoute, 0)
+	topics := make(map[string]bool)
+	for _, routeSpec := range strings.Split(spec, ";") {
+		routeSpec = strings.TrimSpace(routeSpec)
+		if routeSpec == "" {
+			continue
+		}
+		target, projectionSpec, _ := strings.Cut(routeSpec, ":")
+		topic, operationsSpec, hasOperations := strings.Cut(target, "@")
+		route := UserEventRoute{Topic: strings.TrimSpace(topic)}
+		if route.Topic == "" {
+			return nil, fmt.Errorf("missing topic in route %q", routeSpec)
+		}
+		if topics[route.Topic] {
+			return nil, fmt.Errorf("duplicate route to topic %s", route.Topic)
+		}
+		topics[route.Topic] = true
+
+		if hasOperations {
+			for _, name := range strings.Split(operationsSpec, "|") {
+				operation, ok := operationNames[strings.TrimSpace(name)]
+				if !ok {
+					return nil, fmt.Errorf("unknown operation %q for topic %s", name, route.Topic)
+				}
+				route.Operations = append(route.Operations, operation)
+			}
+		}
+
+		if projectionSpec = strings.TrimSpace(projectionSpec); projectionSpec != "" {
+			fields := make(map[string]FieldAction)
+			for _, fieldSpec := range strings.Split(projectionSpec, ",") {
+				field, action, ok := strings.Cut(strings.TrimSpace(fieldSpec), "=")
+				if !ok {
+					return nil, fmt.Errorf("invalid projection %q of topic %s, expected field=action", fieldSpec, route.Topic)
+				}
+				fields[strings.TrimSpace(field)] = FieldAction(strings.TrimSpace(action))
+			}
+			projection, err := NewUserEventProjection(fields, hashKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid projection of topic %s: %w", route.Topic, err)
+			}
+			route.Projection = projection
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
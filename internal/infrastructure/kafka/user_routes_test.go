@@ -0,0 +1,103 @@
+//go:build unit
+
+package kafka_test
+
+import (
+	"testing"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	kafkaClient "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserEventRoutes(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           string
+		hashKey        []byte
+		expectedTopics []string
+		expectedErr    string
+	}{
+		{
+			name:           "no routes",
+			spec:           "",
+			expectedTopics: []string{},
+		},
+		{
+			name:           "restricted and public topics",
+			spec:           "users-restricted; users-public:email=hash, first_name=mask,last_name=drop",
+			hashKey:        []byte("secret"),
+			expectedTopics: []string{"users-restricted", "users-public"},
+		},
+		{
+			name:           "routes per operation",
+			spec:           "users-created@create;users-changed@update|delete:email=mask",
+			expectedTopics: []string{"users-created", "users-changed"},
+		},
+		{
+			name:        "unknown operation",
+			spec:        "users@upsert",
+			expectedErr: `unknown operation "upsert" for topic users`,
+		},
+		{
+			name:        "hash without key",
+			spec:        "users-public:email=hash",
+			expectedErr: kafkaClient.ErrMissingHashKey.Error(),
+		},
+		{
+			name:        "unknown field",
+			spec:        "users-public:hashed_password=drop",
+			expectedErr: `user field "hashed_password" cannot be projected`,
+		},
+		{
+			name:        "timestamp cannot be masked",
+			spec:        "users-public:created_at=mask",
+			expectedErr: `user field "created_at" cannot be masked`,
+		},
+		{
+			name:        "unknown action",
+			spec:        "users-public:email=encrypt",
+			expectedErr: `unknown action "encrypt" for user field "email"`,
+		},
+		{
+			name:        "missing action",
+			spec:        "users-public:email",
+			expectedErr: "expected field=action",
+		},
+		{
+			name:        "duplicate topic",
+			spec:        "users;users:email=drop",
+			expectedErr: "duplicate route to topic users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := kafkaClient.ParseUserEventRoutes(tt.spec, tt.hashKey)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			topics := make([]string, 0, len(routes))
+			for _, route := range routes {
+				topics = append(topics, route.Topic)
+			}
+			assert.Equal(t, tt.expectedTopics, topics)
+		})
+	}
+}
+
+func TestUserEventRoute_Accepts(t *testing.T) {
+	routes, err := kafkaClient.ParseUserEventRoutes("users;users-changed@update|delete", nil)
+	require.NoError(t, err)
+
+	created := &domain.UserEvent{OperationType: domain.OPERATION_CREATE}
+	deleted := &domain.UserEvent{OperationType: domain.OPERATION_DELETE}
+
+	assert.True(t, routes[0].Accepts(created))
+	assert.True(t, routes[0].Accepts(deleted))
+	assert.False(t, routes[1].Accepts(created))
+	assert.True(t, routes[1].Accepts(deleted))
+}
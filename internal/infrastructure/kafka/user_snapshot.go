@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"google.golang.org/protobuf/proto"
+	"strconv"
+)
+
+// UserVersionHeader carries the version of the user of a snapshot, to discard stale snapshots
+const UserVersionHeader = "user_version"
+
+// NewUserSnapshotProducer creates a producer sending the latest state of the users to the
+// compacted topic. Only the user id, the after change and the sequence of the events are sent.
+func NewUserSnapshotProducer(broker *kafka.Producer, topic string) domain.UserProducer {
+	return NewRoutedUserProducer(broker, []UserEventRoute{{Topic: topic, Snapshot: true}}, UserProducerOptions{})
+}
+
+// userSnapshotValue is the protobuf user after the change, or a nil tombstone once deleted
+func userSnapshotValue(event *pb.UserEvent) ([]byte, error) {
+	if event.AfterChange == nil {
+		return nil, nil
+	}
+	return proto.Marshal(event.AfterChange)
+}
+
+func userSnapshotHeaders(event *domain.UserEvent) []kafka.Header {
+	return []kafka.Header{
+		{Key: ContentTypeHeader, Value: []byte(protobufContentType)},
+		{Key: UserVersionHeader, Value: []byte(strconv.FormatInt(event.Sequence, 10))},
+	}
+}
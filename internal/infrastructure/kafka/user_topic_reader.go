@@ -0,0 +1,130 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/flapenna/go-ddd-crud/pkg/userevents"
+	"strconv"
+	"time"
+)
+
+const readPollTimeout = 100 * time.Millisecond
+
+// UserTopicReader reads the latest published state of the users from a user event topic or
+// from the compacted snapshot topic, telling them apart by the user_version header
+type UserTopicReader struct {
+	consumer *kafka.Consumer
+	timeout  time.Duration
+}
+
+// NewUserTopicReader creates a reader assigning itself the partitions of the topics, so the
+// consumer does not need to join a group
+func NewUserTopicReader(consumer *kafka.Consumer, timeout time.Duration) *UserTopicReader {
+	return &UserTopicReader{
+		consumer: consumer,
+		timeout:  timeout,
+	}
+}
+
+// ReadPublishedUsers consumes the topic from its beginning up to its end as of the call, and
+// returns the latest published state of each user by id
+func (r *UserTopicReader) ReadPublishedUsers(ctx context.Context, topic string) (map[string]*domain.PublishedUser, error) {
+	metadata, err := r.consumer.GetMetadata(&topic, false, int(r.timeout.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata of topic %s: %w", topic, err)
+	}
+	topicMetadata, ok := metadata.Topics[topic]
+	if !ok || topicMetadata.Error.Code() != kafka.ErrNoError {
+		return nil, fmt.Errorf("topic %s not found", topic)
+	}
+
+	// The last offset to read of each partition
+	ends := make(map[int32]int64)
+	partitions := make([]kafka.TopicPartition, 0, len(topicMetadata.Partitions))
+	for _, partition := range topicMetadata.Partitions {
+		low, high, err := r.consumer.QueryWatermarkOffsets(topic, partition.ID, int(r.timeout.Milliseconds()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query offsets of topic %s partition %d: %w", topic, partition.ID, err)
+		}
+		if high <= low {
+			continue
+		}
+		ends[partition.ID] = high - 1
+		partitions = append(partitions, kafka.TopicPartition{Topic: &topic, Partition: partition.ID, Offset: kafka.OffsetBeginning})
+	}
+
+	published := make(map[string]*domain.PublishedUser)
+	if len(partitions) == 0 {
+		return published, nil
+	}
+	if err := r.consumer.Assign(partitions); err != nil {
+		return nil, fmt.Errorf("failed to assign topic %s: %w", topic, err)
+	}
+	defer r.consumer.Unassign()
+
+	for len(ends) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		msg, err := r.consumer.ReadMessage(readPollTimeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if errors.As(err, &kafkaErr) && kafkaErr.IsTimeout() {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read topic %s: %w", topic, err)
+		}
+
+		partition := msg.TopicPartition.Partition
+		if end, ok := ends[partition]; ok && int64(msg.TopicPartition.Offset) >= end {
+			delete(ends, partition)
+		}
+		publishedUser, err := publishedUserFromMessage(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode message %s: %w", msg.TopicPartition, err)
+		}
+		// Messages of a user share its partition, the last one of the highest version wins
+		if previous, ok := published[publishedUser.UserId]; !ok || publishedUser.Version >= previous.Version {
+			published[publishedUser.UserId] = publishedUser
+		}
+	}
+	return published, nil
+}
+
+func publishedUserFromMessage(msg *kafka.Message) (*domain.PublishedUser, error) {
+	var contentType string
+	var snapshotVersion *int64
+	for _, header := range msg.Headers {
+		switch header.Key {
+		case ContentTypeHeader:
+			contentType = string(header.Value)
+		case UserVersionHeader:
+			version, err := strconv.ParseInt(string(header.Value), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s header: %w", UserVersionHeader, err)
+			}
+			snapshotVersion = &version
+		}
+	}
+
+	if snapshotVersion != nil {
+		return &domain.PublishedUser{UserId: string(msg.Key), Version: *snapshotVersion, Deleted: msg.Value == nil}, nil
+	}
+	event, err := userevents.Decode(contentType, msg.Value)
+	if err != nil {
+		return nil, err
+	}
+	userId := event.UserId
+	if userId == "" {
+		userId = string(msg.Key)
+	}
+	return &domain.PublishedUser{
+		UserId:  userId,
+		Version: event.Sequence,
+		Deleted: event.OperationType == pb.OperationType_OPERATION_DELETE,
+	}, nil
+}
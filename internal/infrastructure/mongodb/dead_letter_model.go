@@ -0,0 +1,26 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"time"
+)
+
+// DeadLetterEntity is a user event that could not be published, keyed by event id
+type DeadLetterEntity struct {
+	ID            string               `bson:"_id"`
+	UserId        string               `bson:"user_id"`
+	BeforeChange  *UserEntity          `bson:"before_change,omitempty"`
+	AfterChange   *UserEntity          `bson:"after_change,omitempty"`
+	OperationType domain.OperationType `bson:"operation_type"`
+	ModifiedBy    string               `bson:"modified_by"`
+	CorrelationId string               `bson:"correlation_id"`
+	TraceParent   string               `bson:"trace_parent"`
+	Sequence      int64                `bson:"sequence"`
+	ClusterTime   uint64               `bson:"cluster_time"`
+	OccurredAt    time.Time            `bson:"occurred_at"`
+	ChangedFields []string             `bson:"changed_fields,omitempty"`
+	Error         string               `bson:"error"`
+	Attempts      int32                `bson:"attempts"`
+	FirstFailedAt time.Time            `bson:"first_failed_at"`
+	LastFailedAt  time.Time            `bson:"last_failed_at"`
+}
@@ -0,0 +1,150 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type UserDeadLetterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUserDeadLetterRepository(collection *mongo.Collection) *UserDeadLetterRepository {
+	return &UserDeadLetterRepository{
+		collection: collection,
+	}
+}
+
+// RecordDeadLetter upserts the dead letter: the event and the first failure are only
+// written on insert, every call counts one attempt and keeps the last error
+func (r *UserDeadLetterRepository) RecordDeadLetter(ctx context.Context, event *domain.UserEvent, failure error, failedAt time.Time) error {
+	onInsert := bson.M{
+		"user_id":         event.UserId,
+		"operation_type":  event.OperationType,
+		"modified_by":     event.ModifiedBy,
+		"correlation_id":  event.CorrelationId,
+		"trace_parent":    event.TraceParent,
+		"sequence":        event.Sequence,
+		"cluster_time":    int64(event.ClusterTime),
+		"occurred_at":     event.OccurredAt,
+		"first_failed_at": failedAt,
+	}
+	if len(event.ChangedFields) > 0 {
+		onInsert["changed_fields"] = event.ChangedFields
+	}
+	if event.BeforeChange != nil {
+		onInsert["before_change"] = toEntity(event.BeforeChange)
+	}
+	if event.AfterChange != nil {
+		onInsert["after_change"] = toEntity(event.AfterChange)
+	}
+
+	update := bson.M{
+		"$setOnInsert": onInsert,
+		"$set":         bson.M{"error": failure.Error(), "last_failed_at": failedAt},
+		"$inc":         bson.M{"attempts": 1},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": event.Id}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *UserDeadLetterRepository) GetDeadLetter(ctx context.Context, id string) (*domain.DeadLetter, error) {
+	var entity *DeadLetterEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deadLetterToDomain(entity), nil
+}
+
+func (r *UserDeadLetterRepository) ListDeadLetters(ctx context.Context, request *domain.ListDeadLettersQueryRequest) (*domain.ListDeadLettersQueryResponse, error) {
+	if request.PageSize == 0 {
+		request.PageSize = 10
+	}
+
+	filter := bson.M{}
+
+	// Get the total count of documents matching the filter
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %v", err)
+	}
+
+	// Pagination options, oldest failures first
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "first_failed_at", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetSkip(int64(request.Page * request.PageSize))
+	findOptions.SetLimit(int64(request.PageSize))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	var entities []*DeadLetterEntity
+	if err = cursor.All(ctx, &entities); err != nil {
+		return nil, fmt.Errorf("failed to decode dead letters: %w", err)
+	}
+
+	results := make([]*domain.DeadLetter, len(entities))
+	for i, e := range entities {
+		results[i] = deadLetterToDomain(e)
+	}
+
+	return &domain.ListDeadLettersQueryResponse{
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalCount: uint32(totalCount),
+		Results:    results,
+	}, nil
+}
+
+func (r *UserDeadLetterRepository) DeleteDeadLetter(ctx context.Context, id string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+func deadLetterToDomain(e *DeadLetterEntity) *domain.DeadLetter {
+	return &domain.DeadLetter{
+		Id: e.ID,
+		Event: &domain.UserEvent{
+			Id:            e.ID,
+			UserId:        e.UserId,
+			BeforeChange:  userToDomain(e.BeforeChange),
+			AfterChange:   userToDomain(e.AfterChange),
+			OperationType: e.OperationType,
+			ModifiedBy:    e.ModifiedBy,
+			CorrelationId: e.CorrelationId,
+			TraceParent:   e.TraceParent,
+			Sequence:      e.Sequence,
+			ClusterTime:   e.ClusterTime,
+			OccurredAt:    e.OccurredAt,
+			ChangedFields: e.ChangedFields,
+		},
+		Error:         e.Error,
+		Attempts:      e.Attempts,
+		FirstFailedAt: e.FirstFailedAt,
+		LastFailedAt:  e.LastFailedAt,
+	}
+}
@@ -0,0 +1,131 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type UserDeadLetterRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.UserDeadLetterRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *UserDeadLetterRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_dead_letters")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewUserDeadLetterRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *UserDeadLetterRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *UserDeadLetterRepositoryTestSuite) SetupTest() {
+	// Clean up the collection before each test
+	suite.collection.Drop(suite.ctx)
+}
+
+func (suite *UserDeadLetterRepositoryTestSuite) TestUserDeadLetterRepository_RecordDeadLetter() {
+	firstFailure := time.Now().UTC().Round(time.Millisecond)
+	lastFailure := firstFailure.Add(time.Minute)
+	event := &domain.UserEvent{
+		Id:            uuid.NewString(),
+		UserId:        uuid.NewString(),
+		AfterChange:   &domain.User{FirstName: "Federico", CreatedAt: firstFailure, UpdatedAt: firstFailure},
+		OperationType: domain.OPERATION_CREATE,
+		ModifiedBy:    "admin-1",
+		CorrelationId: "request-1",
+		Sequence:      1,
+		ClusterTime:   7300000000000000001,
+		OccurredAt:    firstFailure,
+	}
+
+	err := suite.repo.RecordDeadLetter(suite.ctx, event, errors.New("first error"), firstFailure)
+	suite.Require().NoError(err)
+	err = suite.repo.RecordDeadLetter(suite.ctx, event, errors.New("last error"), lastFailure)
+	suite.Require().NoError(err)
+
+	deadLetter, err := suite.repo.GetDeadLetter(suite.ctx, event.Id)
+	suite.Require().NoError(err)
+	suite.Equal(event.Id, deadLetter.Id)
+	suite.Equal(event.UserId, deadLetter.Event.UserId)
+	suite.Equal("Federico", deadLetter.Event.AfterChange.FirstName)
+	suite.Nil(deadLetter.Event.BeforeChange)
+	suite.Equal(domain.OPERATION_CREATE, deadLetter.Event.OperationType)
+	suite.Equal("admin-1", deadLetter.Event.ModifiedBy)
+	suite.Equal("request-1", deadLetter.Event.CorrelationId)
+	suite.Equal(int64(1), deadLetter.Event.Sequence)
+	suite.Equal(uint64(7300000000000000001), deadLetter.Event.ClusterTime)
+	suite.True(firstFailure.Equal(deadLetter.Event.OccurredAt))
+	suite.Equal("last error", deadLetter.Error)
+	suite.Equal(int32(2), deadLetter.Attempts)
+	suite.True(firstFailure.Equal(deadLetter.FirstFailedAt))
+	suite.True(lastFailure.Equal(deadLetter.LastFailedAt))
+}
+
+func (suite *UserDeadLetterRepositoryTestSuite) TestUserDeadLetterRepository_ListAndDelete() {
+	now := time.Now().UTC().Round(time.Millisecond)
+	ids := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	for i, id := range ids {
+		event := &domain.UserEvent{Id: id, UserId: uuid.NewString(), OperationType: domain.OPERATION_DELETE}
+		err := suite.repo.RecordDeadLetter(suite.ctx, event, errors.New("producer error"), now.Add(time.Duration(i)*time.Second))
+		suite.Require().NoError(err)
+	}
+
+	res, err := suite.repo.ListDeadLetters(suite.ctx, &domain.ListDeadLettersQueryRequest{Page: 0, PageSize: 2})
+	suite.Require().NoError(err)
+	suite.Equal(uint32(3), res.TotalCount)
+	suite.Require().Len(res.Results, 2)
+	suite.Equal(ids[0], res.Results[0].Id)
+	suite.Equal(ids[1], res.Results[1].Id)
+
+	suite.Require().NoError(suite.repo.DeleteDeadLetter(suite.ctx, ids[0]))
+	suite.Equal(domain.ErrDeadLetterNotFound, suite.repo.DeleteDeadLetter(suite.ctx, ids[0]))
+
+	_, err = suite.repo.GetDeadLetter(suite.ctx, ids[0])
+	suite.Equal(domain.ErrDeadLetterNotFound, err)
+}
+
+func TestUserDeadLetterRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserDeadLetterRepositoryTestSuite))
+}
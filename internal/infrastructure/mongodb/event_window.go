@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+)
+
+// eventWindow emits the user events of a watcher while up to size of them wait for their
+// publishing outcome. The outcomes are reported on results, in the order of the events, and
+// the watchers without results do not wait for any outcome.
+// Once an event fails, no other event is emitted until the outcomes of the emitted events
+// have been received, for the watcher to emit the failed event again from a consistent position.
+type eventWindow struct {
+	events  chan *domain.UserEvent
+	results chan error
+	size    int
+	pending int
+	err     error
+}
+
+func newEventWindow(events chan *domain.UserEvent, results chan error, size int) *eventWindow {
+	if size <= 0 {
+		size = 1
+	}
+	return &eventWindow{events: events, results: results, size: size}
+}
+
+// emit sends the event, then waits for outcomes until the window has room for the next one.
+// The first failure is returned once every emitted event has its outcome.
+func (w *eventWindow) emit(ctx context.Context, event *domain.UserEvent) error {
+	if w.err != nil {
+		return w.drain(ctx)
+	}
+	// The outcomes are received while sending, the pipeline may wait for them to accept the event
+	for sent := false; !sent; {
+		select {
+		case w.events <- event:
+			sent = true
+		case err := <-w.results:
+			w.record(err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if w.results == nil {
+		return nil
+	}
+	w.pending++
+
+	for w.pending >= w.size && w.err == nil {
+		select {
+		case err := <-w.results:
+			w.record(err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if w.err != nil {
+		return w.drain(ctx)
+	}
+	return nil
+}
+
+// drain waits for the outcome of every emitted event, and returns the first failure
+func (w *eventWindow) drain(ctx context.Context) error {
+	for w.pending > 0 {
+		select {
+		case err := <-w.results:
+			w.record(err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	err := w.err
+	w.err = nil
+	return err
+}
+
+func (w *eventWindow) record(err error) {
+	w.pending--
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+}
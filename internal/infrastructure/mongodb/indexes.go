@@ -0,0 +1,21 @@
+package mongodb
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// indexCreationTimeout bounds the index creation of a repository constructor
+const indexCreationTimeout = 30 * time.Second
+
+// ensureIndexes creates the indexes the queries of a repository rely on, creating an existing
+// index again is a no-op. A failure is only logged: the queries still work, scanning the collection.
+func ensureIndexes(collection *mongo.Collection, indexes ...mongo.IndexModel) {
+	ctx, cancel := context.WithTimeout(context.Background(), indexCreationTimeout)
+	defer cancel()
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Errorf("failed to create the indexes of the %s collection: %v", collection.Name(), err)
+	}
+}
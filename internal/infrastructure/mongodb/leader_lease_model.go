@@ -0,0 +1,14 @@
+package mongodb
+
+import (
+	"time"
+)
+
+// LeaderLeaseEntity is the lease of a leader election, keyed by lease name. It is expired rather
+// than deleted when released, to keep increasing its fencing token.
+type LeaderLeaseEntity struct {
+	Name         string    `bson:"_id"`
+	HolderId     string    `bson:"holder_id"`
+	FencingToken int64     `bson:"fencing_token"`
+	ExpiresAt    time.Time `bson:"expires_at"`
+}
@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"context"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type LeaderLeaseRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLeaderLeaseRepository(collection *mongo.Collection) *LeaderLeaseRepository {
+	return &LeaderLeaseRepository{
+		collection: collection,
+	}
+}
+
+// AcquireLeaderLease acquires or renews the lease with a single upsert, comparing the expiry with
+// the clock of the server so that the instances do not depend on their own clocks agreeing. When
+// the lease is held by another instance the filter does not match and the upsert fails on the
+// duplicate name.
+func (r *LeaderLeaseRepository) AcquireLeaderLease(ctx context.Context, name, holderId string, duration time.Duration) (*domain.LeaderLease, error) {
+	heldByHolder := bson.M{"$eq": bson.A{"$holder_id", bson.M{"$literal": holderId}}}
+	expired := bson.M{"$lte": bson.A{"$expires_at", "$$NOW"}}
+	renewal := bson.M{"$and": bson.A{heldByHolder, bson.M{"$not": bson.A{expired}}}}
+	filter := bson.M{"_id": name, "$expr": bson.M{"$or": bson.A{heldByHolder, expired}}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"holder_id": bson.M{"$literal": holderId},
+		"fencing_token": bson.M{"$cond": bson.A{renewal, "$fencing_token",
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$fencing_token", int64(0)}}, int64(1)}}}},
+		"expires_at": bson.M{"$add": bson.A{"$$NOW", duration.Milliseconds()}},
+	}}}
+
+	var entity *LeaderLeaseEntity
+	err := r.collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&entity)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, domain.ErrLeaseHeld
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LeaderLease{
+		Name:         entity.Name,
+		HolderId:     entity.HolderId,
+		FencingToken: entity.FencingToken,
+		ExpiresAt:    entity.ExpiresAt,
+	}, nil
+}
+
+func (r *LeaderLeaseRepository) ReleaseLeaderLease(ctx context.Context, lease *domain.LeaderLease) error {
+	filter := bson.M{"_id": lease.Name, "holder_id": lease.HolderId, "fencing_token": lease.FencingToken}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.A{bson.M{"$set": bson.M{"expires_at": "$$NOW"}}})
+	return err
+}
@@ -0,0 +1,143 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type LeaderLeaseRepositoryTestSuite struct {
+	suite.Suite
+	mongoC      testcontainers.Container
+	client      *mongo.Client
+	collection  *mongo.Collection
+	checkpoints *mongo.Collection
+	repo        *mongodb.LeaderLeaseRepository
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func (suite *LeaderLeaseRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_leader_leases")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.checkpoints = client.Database("testdb").Collection("test_resume_tokens")
+	suite.repo = mongodb.NewLeaderLeaseRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 10*time.Second)
+}
+
+func (suite *LeaderLeaseRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *LeaderLeaseRepositoryTestSuite) SetupTest() {
+	// Clean up the collections before each test
+	suite.collection.Drop(suite.ctx)
+	suite.checkpoints.Drop(suite.ctx)
+}
+
+func (suite *LeaderLeaseRepositoryTestSuite) TestLeaderLeaseRepository_AcquireLeaderLease() {
+	lease, err := suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-1", time.Second)
+	suite.Require().NoError(err)
+	suite.Equal("instance-1", lease.HolderId)
+	suite.Equal(int64(1), lease.FencingToken)
+	suite.WithinDuration(time.Now().Add(time.Second), lease.ExpiresAt, 500*time.Millisecond)
+
+	// The lease is held until it expires
+	_, err = suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-2", time.Second)
+	suite.ErrorIs(err, domain.ErrLeaseHeld)
+
+	// Renewing keeps the fencing token
+	renewed, err := suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-1", time.Second)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), renewed.FencingToken)
+	suite.False(renewed.ExpiresAt.Before(lease.ExpiresAt))
+
+	// Another lease name is elected on its own
+	other, err := suite.repo.AcquireLeaderLease(suite.ctx, "webhooks", "instance-2", time.Second)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), other.FencingToken)
+
+	// Once expired, the lease is taken over with a new fencing token
+	time.Sleep(1200 * time.Millisecond)
+	takenOver, err := suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-2", time.Second)
+	suite.Require().NoError(err)
+	suite.Equal("instance-2", takenOver.HolderId)
+	suite.Equal(int64(2), takenOver.FencingToken)
+	_, err = suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-1", time.Second)
+	suite.ErrorIs(err, domain.ErrLeaseHeld)
+}
+
+func (suite *LeaderLeaseRepositoryTestSuite) TestLeaderLeaseRepository_ReleaseLeaderLease() {
+	lease, err := suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-1", time.Minute)
+	suite.Require().NoError(err)
+
+	// Releasing a former term does not release the lease
+	suite.Require().NoError(suite.repo.ReleaseLeaderLease(suite.ctx, &domain.LeaderLease{
+		Name: "user-watcher", HolderId: "instance-1", FencingToken: 0}))
+	_, err = suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-2", time.Minute)
+	suite.ErrorIs(err, domain.ErrLeaseHeld)
+
+	// A released lease is acquired right away, with a new fencing token
+	suite.Require().NoError(suite.repo.ReleaseLeaderLease(suite.ctx, lease))
+	takenOver, err := suite.repo.AcquireLeaderLease(suite.ctx, "user-watcher", "instance-2", time.Minute)
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), takenOver.FencingToken)
+}
+
+func (suite *LeaderLeaseRepositoryTestSuite) TestResumeTokenRepository_FencedSaveResumeToken() {
+	checkpoints := mongodb.NewResumeTokenRepository(suite.checkpoints)
+	token := func(data string) bson.Raw {
+		raw, err := bson.Marshal(bson.M{"_data": data})
+		suite.Require().NoError(err)
+		return raw
+	}
+
+	// A checkpoint saved without leader election is overwritten by a leader
+	suite.Require().NoError(checkpoints.SaveResumeToken(suite.ctx, "users", token("1")))
+	suite.Require().NoError(checkpoints.SaveResumeToken(domain.ContextWithFencingToken(suite.ctx, 2), "users", token("2")))
+
+	// The former leader can no longer save its checkpoints
+	err := checkpoints.SaveResumeToken(domain.ContextWithFencingToken(suite.ctx, 1), "users", token("stale"))
+	suite.ErrorIs(err, domain.ErrStaleFencingToken)
+	suite.Require().NoError(checkpoints.SaveResumeToken(domain.ContextWithFencingToken(suite.ctx, 3), "users", token("3")))
+
+	saved, err := checkpoints.LoadResumeToken(suite.ctx, "users")
+	suite.Require().NoError(err)
+	suite.Equal(token("3"), saved)
+}
+
+func TestLeaderLeaseRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LeaderLeaseRepositoryTestSuite))
+}
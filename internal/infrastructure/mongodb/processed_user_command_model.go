@@ -0,0 +1,10 @@
+package mongodb
+
+import "time"
+
+// ProcessedUserCommandEntity is the result of a user command, keyed by command id
+type ProcessedUserCommandEntity struct {
+	ID          string    `bson:"_id"`
+	Result      []byte    `bson:"result"`
+	ProcessedAt time.Time `bson:"processed_at"`
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type ProcessedUserCommandRepository struct {
+	collection *mongo.Collection
+}
+
+// NewProcessedUserCommandRepository creates a repository whose commands are keyed by the unique
+// _id index, and expire retention after being processed. They are kept forever when retention is 0.
+func NewProcessedUserCommandRepository(collection *mongo.Collection, retention time.Duration) *ProcessedUserCommandRepository {
+	if retention > 0 {
+		ensureIndexes(collection, mongo.IndexModel{
+			Keys:    bson.D{{Key: "processed_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds())),
+		})
+	}
+	return &ProcessedUserCommandRepository{
+		collection: collection,
+	}
+}
+
+func (r *ProcessedUserCommandRepository) RecordProcessedUserCommand(ctx context.Context, command *domain.ProcessedUserCommand) error {
+	_, err := r.collection.InsertOne(ctx, &ProcessedUserCommandEntity{
+		ID:          command.Id,
+		Result:      command.Result,
+		ProcessedAt: command.ProcessedAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrUserCommandAlreadyProcessed
+	}
+	return err
+}
+
+func (r *ProcessedUserCommandRepository) GetProcessedUserCommand(ctx context.Context, id string) (*domain.ProcessedUserCommand, error) {
+	var entity ProcessedUserCommandEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrUserCommandNotProcessed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ProcessedUserCommand{
+		Id:          entity.ID,
+		Result:      entity.Result,
+		ProcessedAt: entity.ProcessedAt,
+	}, nil
+}
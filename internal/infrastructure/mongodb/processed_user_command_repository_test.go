@@ -0,0 +1,90 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type ProcessedUserCommandRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.ProcessedUserCommandRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *ProcessedUserCommandRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_processed_user_commands")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewProcessedUserCommandRepository(collection, 24*time.Hour)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *ProcessedUserCommandRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *ProcessedUserCommandRepositoryTestSuite) SetupTest() {
+	// Clean up the documents before each test, keeping the indexes
+	suite.collection.DeleteMany(suite.ctx, map[string]any{})
+}
+
+func (suite *ProcessedUserCommandRepositoryTestSuite) TestProcessedUserCommandRepository_RecordProcessedUserCommand() {
+	processedAt := time.Now().UTC().Round(time.Millisecond)
+	command := &domain.ProcessedUserCommand{Id: "command-1", Result: []byte("result-1"), ProcessedAt: processedAt}
+	suite.Require().NoError(suite.repo.RecordProcessedUserCommand(suite.ctx, command))
+
+	// The same command cannot be recorded twice
+	err := suite.repo.RecordProcessedUserCommand(suite.ctx, &domain.ProcessedUserCommand{Id: "command-1", Result: []byte("result-2"),
+		ProcessedAt: processedAt.Add(time.Second)})
+	suite.Equal(domain.ErrUserCommandAlreadyProcessed, err)
+
+	saved, err := suite.repo.GetProcessedUserCommand(suite.ctx, "command-1")
+	suite.Require().NoError(err)
+	suite.Equal("command-1", saved.Id)
+	suite.Equal([]byte("result-1"), saved.Result)
+	suite.True(processedAt.Equal(saved.ProcessedAt))
+}
+
+func (suite *ProcessedUserCommandRepositoryTestSuite) TestProcessedUserCommandRepository_GetProcessedUserCommandNotFound() {
+	_, err := suite.repo.GetProcessedUserCommand(suite.ctx, "command-1")
+	suite.Equal(domain.ErrUserCommandNotProcessed, err)
+}
+
+func TestProcessedUserCommandRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessedUserCommandRepositoryTestSuite))
+}
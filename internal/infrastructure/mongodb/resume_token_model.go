@@ -0,0 +1,15 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"time"
+)
+
+// ResumeTokenEntity is the last position of a change stream whose events have all been published
+type ResumeTokenEntity struct {
+	StreamName  string    `bson:"_id"`
+	ResumeToken bson.Raw  `bson:"resume_token"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+	// FencingToken is the token of the leader that saved the checkpoint, when leader elected
+	FencingToken int64 `bson:"fencing_token,omitempty"`
+}
@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// ResumeTokenRepository is a ResumeTokenStore keeping one checkpoint per change stream
+type ResumeTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewResumeTokenRepository(collection *mongo.Collection) *ResumeTokenRepository {
+	return &ResumeTokenRepository{
+		collection: collection,
+	}
+}
+
+// LoadResumeToken returns nil when the stream has no checkpoint yet
+func (r *ResumeTokenRepository) LoadResumeToken(ctx context.Context, streamName string) (bson.Raw, error) {
+	var entity *ResumeTokenEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": streamName}).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entity.ResumeToken, nil
+}
+
+// SaveResumeToken is fenced when ctx carries the fencing token of a leader: the checkpoint of
+// a newer leader is never overwritten, the save failing with ErrStaleFencingToken instead
+func (r *ResumeTokenRepository) SaveResumeToken(ctx context.Context, streamName string, token bson.Raw) error {
+	entity := &ResumeTokenEntity{
+		StreamName:  streamName,
+		ResumeToken: token,
+		UpdatedAt:   time.Now().UTC().Round(time.Millisecond),
+	}
+	filter := bson.M{"_id": streamName}
+	if fencingToken, ok := domain.FencingTokenFromContext(ctx); ok {
+		entity.FencingToken = fencingToken
+		filter["$or"] = bson.A{
+			bson.M{"fencing_token": bson.M{"$exists": false}},
+			bson.M{"fencing_token": bson.M{"$lte": fencingToken}},
+		}
+	}
+	_, err := r.collection.ReplaceOne(ctx, filter, entity, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrStaleFencingToken
+	}
+	return err
+}
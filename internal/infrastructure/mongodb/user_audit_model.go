@@ -0,0 +1,17 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"time"
+)
+
+type UserAuditEntryEntity struct {
+	ID            string               `bson:"_id"`
+	UserId        string               `bson:"user_id"`
+	BeforeChange  *UserEntity          `bson:"before_change,omitempty"`
+	AfterChange   *UserEntity          `bson:"after_change,omitempty"`
+	OperationType domain.OperationType `bson:"operation_type"`
+	ModifiedBy    string               `bson:"modified_by"`
+	CorrelationId string               `bson:"correlation_id"`
+	RecordedAt    time.Time            `bson:"recorded_at"`
+}
@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserAuditRepository stores the audit entries in an append-only collection:
+// entries are only ever inserted, never updated or deleted.
+type UserAuditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserAuditRepository creates the repository and the indexes of the audit trail queries, by
+// user, by actor and by time range, all sorted by recording time
+func NewUserAuditRepository(collection *mongo.Collection) *UserAuditRepository {
+	ensureIndexes(collection,
+		mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "recorded_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "modified_by", Value: 1}, {Key: "recorded_at", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "recorded_at", Value: 1}}},
+	)
+	return &UserAuditRepository{
+		collection: collection,
+	}
+}
+
+// AppendAuditEntry stores an entry, appending the same entry twice, as for an event emitted
+// again by the watcher, is a no-op
+func (r *UserAuditRepository) AppendAuditEntry(ctx context.Context, entry *domain.UserAuditEntry) error {
+	_, err := r.collection.InsertOne(ctx, toAuditEntryEntity(entry))
+	if mongo.IsDuplicateKeyError(err) {
+		log.Debugf("audit entry %s already recorded", entry.Id)
+		return nil
+	}
+	return err
+}
+
+func (r *UserAuditRepository) ListAuditEntries(ctx context.Context, request *domain.ListUserAuditEntriesQueryRequest) (*domain.ListUserAuditEntriesQueryResponse, error) {
+	if request.PageSize == 0 {
+		request.PageSize = 10
+	}
+
+	filter := bson.M{}
+
+	// Add filters based on the request
+	if request.UserId != nil {
+		filter["user_id"] = request.UserId
+	}
+	if request.ModifiedBy != nil {
+		filter["modified_by"] = request.ModifiedBy
+	}
+	recordedAt := bson.M{}
+	if request.From != nil {
+		recordedAt["$gte"] = request.From
+	}
+	if request.To != nil {
+		recordedAt["$lte"] = request.To
+	}
+	if len(recordedAt) > 0 {
+		filter["recorded_at"] = recordedAt
+	}
+
+	// Get the total count of documents matching the filter
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %v", err)
+	}
+
+	// Pagination options, oldest entries first
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "recorded_at", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetSkip(int64(request.Page * request.PageSize))
+	findOptions.SetLimit(int64(request.PageSize))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	var entries []*UserAuditEntryEntity
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+
+	results := make([]*domain.UserAuditEntry, len(entries))
+	for i, e := range entries {
+		results[i] = auditEntryToDomain(e)
+	}
+
+	return &domain.ListUserAuditEntriesQueryResponse{
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalCount: uint32(totalCount),
+		Results:    results,
+	}, nil
+}
+
+func auditEntryToDomain(e *UserAuditEntryEntity) *domain.UserAuditEntry {
+	return &domain.UserAuditEntry{
+		Id:            e.ID,
+		UserId:        e.UserId,
+		BeforeChange:  userToDomain(e.BeforeChange),
+		AfterChange:   userToDomain(e.AfterChange),
+		OperationType: e.OperationType,
+		ModifiedBy:    e.ModifiedBy,
+		CorrelationId: e.CorrelationId,
+		RecordedAt:    e.RecordedAt,
+	}
+}
+
+func toAuditEntryEntity(entry *domain.UserAuditEntry) *UserAuditEntryEntity {
+	entity := &UserAuditEntryEntity{
+		ID:            entry.Id,
+		UserId:        entry.UserId,
+		OperationType: entry.OperationType,
+		ModifiedBy:    entry.ModifiedBy,
+		CorrelationId: entry.CorrelationId,
+		RecordedAt:    entry.RecordedAt,
+	}
+	if entry.BeforeChange != nil {
+		entity.BeforeChange = toEntity(entry.BeforeChange)
+	}
+	if entry.AfterChange != nil {
+		entity.AfterChange = toEntity(entry.AfterChange)
+	}
+	return entity
+}
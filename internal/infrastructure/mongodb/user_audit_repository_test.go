@@ -0,0 +1,170 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type UserAuditRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.UserAuditRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *UserAuditRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_audit")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewUserAuditRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *UserAuditRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *UserAuditRepositoryTestSuite) SetupTest() {
+	// Clean up the collection before each test
+	suite.collection.Drop(suite.ctx)
+}
+
+func (suite *UserAuditRepositoryTestSuite) TestUserAuditRepository_AppendAuditEntry() {
+	now := time.Now().UTC().Round(time.Millisecond)
+	entry := &domain.UserAuditEntry{
+		Id:     uuid.NewString(),
+		UserId: uuid.NewString(),
+		AfterChange: &domain.User{
+			FirstName: "Federico",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		OperationType: domain.OPERATION_CREATE,
+		ModifiedBy:    "admin-1",
+		CorrelationId: "request-1",
+		RecordedAt:    now,
+	}
+
+	err := suite.repo.AppendAuditEntry(suite.ctx, entry)
+	suite.Require().NoError(err)
+
+	// entries are append-only, appending the same entry again is a no-op
+	err = suite.repo.AppendAuditEntry(suite.ctx, entry)
+	suite.Require().NoError(err)
+
+	res, err := suite.repo.ListAuditEntries(suite.ctx, &domain.ListUserAuditEntriesQueryRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Len(res.Results, 1)
+	suite.Equal(entry.Id, res.Results[0].Id)
+	suite.Equal(entry.UserId, res.Results[0].UserId)
+	suite.Nil(res.Results[0].BeforeChange)
+	suite.Equal(entry.AfterChange.FirstName, res.Results[0].AfterChange.FirstName)
+	suite.Equal(entry.OperationType, res.Results[0].OperationType)
+	suite.Equal(entry.ModifiedBy, res.Results[0].ModifiedBy)
+	suite.Equal(entry.CorrelationId, res.Results[0].CorrelationId)
+	suite.WithinDuration(entry.RecordedAt, res.Results[0].RecordedAt, time.Millisecond)
+}
+
+func (suite *UserAuditRepositoryTestSuite) TestUserAuditRepository_ListAuditEntries() {
+	userId := uuid.NewString()
+	otherUserId := uuid.NewString()
+	admin := "admin-1"
+	// round due to bson spec https://bsonspec.org/spec.html
+	now := time.Now().UTC().Round(time.Millisecond)
+	from := now.Add(-90 * time.Minute)
+	seed := []*domain.UserAuditEntry{
+		{Id: "event-1", UserId: userId, OperationType: domain.OPERATION_CREATE, ModifiedBy: userId, RecordedAt: now.Add(-2 * time.Hour)},
+		{Id: "event-2", UserId: userId, OperationType: domain.OPERATION_UPDATE, ModifiedBy: admin, RecordedAt: now.Add(-time.Hour)},
+		{Id: "event-3", UserId: otherUserId, OperationType: domain.OPERATION_CREATE, ModifiedBy: admin, RecordedAt: now.Add(-30 * time.Minute)},
+		{Id: "event-4", UserId: userId, OperationType: domain.OPERATION_DELETE, ModifiedBy: admin, RecordedAt: now},
+	}
+	tests := []struct {
+		name        string
+		req         *domain.ListUserAuditEntriesQueryRequest
+		wantedIds   []string
+		wantedTotal uint32
+	}{
+		{
+			name:        "list every entry ordered by time",
+			req:         &domain.ListUserAuditEntriesQueryRequest{},
+			wantedIds:   []string{"event-1", "event-2", "event-3", "event-4"},
+			wantedTotal: 4,
+		},
+		{
+			name:        "filter by user",
+			req:         &domain.ListUserAuditEntriesQueryRequest{UserId: &userId},
+			wantedIds:   []string{"event-1", "event-2", "event-4"},
+			wantedTotal: 3,
+		},
+		{
+			name:        "filter by actor and time range",
+			req:         &domain.ListUserAuditEntriesQueryRequest{ModifiedBy: &admin, From: &from, To: &now},
+			wantedIds:   []string{"event-2", "event-3", "event-4"},
+			wantedTotal: 3,
+		},
+		{
+			name:        "paginate",
+			req:         &domain.ListUserAuditEntriesQueryRequest{Page: 1, PageSize: 3},
+			wantedIds:   []string{"event-4"},
+			wantedTotal: 4,
+		},
+	}
+
+	for _, e := range seed {
+		err := suite.repo.AppendAuditEntry(suite.ctx, e)
+		suite.Require().NoError(err)
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			res, err := suite.repo.ListAuditEntries(suite.ctx, tt.req)
+			suite.Require().NoError(err)
+			suite.Equal(tt.wantedTotal, res.TotalCount)
+
+			ids := make([]string, len(res.Results))
+			for i, e := range res.Results {
+				ids[i] = e.Id
+			}
+			suite.Equal(tt.wantedIds, ids)
+		})
+	}
+}
+
+func TestUserAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserAuditRepositoryTestSuite))
+}
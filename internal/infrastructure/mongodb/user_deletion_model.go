@@ -0,0 +1,13 @@
+package mongodb
+
+import "time"
+
+// UserDeletionEntity attributes the deletion of a user, keyed by the user id. The delete change
+// event only carries the document as it was before, so the watcher reads the actor from here.
+type UserDeletionEntity struct {
+	ID            string    `bson:"_id"`
+	DeletedBy     string    `bson:"deleted_by"`
+	CorrelationId string    `bson:"correlation_id"`
+	TraceParent   string    `bson:"trace_parent"`
+	DeletedAt     time.Time `bson:"deleted_at"`
+}
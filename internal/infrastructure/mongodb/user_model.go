@@ -12,4 +12,8 @@ type UserEntity struct {
 	Nickname       string    `bson:"nickname"`
 	CreatedAt      time.Time `bson:"created_at,omitempty"`
 	UpdatedAt      time.Time `bson:"updated_at,omitempty"`
+	LastModifiedBy string    `bson:"last_modified_by"`
+	CorrelationId  string    `bson:"correlation_id"`
+	TraceParent    string    `bson:"trace_parent"`
+	Version        int64     `bson:"version,omitempty"`
 }
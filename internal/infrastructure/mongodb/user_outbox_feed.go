@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// UsersOutboxFeed emits the user events as they are inserted into the outbox, from a change
+// stream of the outbox. Unlike the relay, it neither waits for acknowledgements nor deletes the
+// rows, so every instance can follow it, and the events keep the ids they are published with.
+//
+// The feed is not checkpointed: a failed stream is reopened from the last event it emitted,
+// with an exponential backoff and without giving up, or from now once that event is no longer
+// in the oplog.
+type UsersOutboxFeed struct {
+	outbox      *mongo.Collection
+	retryPolicy RetryPolicy
+}
+
+func NewOutboxFeed(outbox *mongo.Collection, retryPolicy RetryPolicy) *UsersOutboxFeed {
+	return &UsersOutboxFeed{outbox: outbox, retryPolicy: retryPolicy}
+}
+
+func (f *UsersOutboxFeed) WatchUsers(ctx context.Context) <-chan *domain.UserEvent {
+	userEvents := make(chan *domain.UserEvent)
+	go func() {
+		defer close(userEvents)
+
+		var resumeToken bson.Raw
+		attempts := 0
+		for {
+			err := f.watch(ctx, userEvents, &resumeToken, func() { attempts = 0 })
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, errStreamInvalidated) {
+				continue
+			}
+			if isChangeStreamHistoryLost(err) {
+				log.Warnf("Outbox feed history lost, following the outbox from now: %v", err)
+				resumeToken = nil
+				continue
+			}
+
+			attempts++
+			backoff := f.retryPolicy.backoff(attempts)
+			log.Warnf("Error following the outbox, resuming in %s (attempt %d): %v", backoff, attempts, err)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return userEvents
+}
+
+// watch emits the inserted rows until the stream fails. resumeToken is the position of the last
+// emitted event, and healthy is called with every event.
+func (f *UsersOutboxFeed) watch(ctx context.Context, userEvents chan<- *domain.UserEvent, resumeToken *bson.Raw, healthy func()) error {
+	opts := options.ChangeStream()
+	if *resumeToken != nil {
+		opts.SetStartAfter(*resumeToken)
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	changeStream, err := f.outbox.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("failed to open change stream: %w", err)
+	}
+	defer changeStream.Close(ctx)
+
+	for changeStream.Next(ctx) {
+		var change struct {
+			OperationType string           `bson:"operationType"`
+			FullDocument  UserOutboxEntity `bson:"fullDocument"`
+		}
+		err := changeStream.Decode(&change)
+		switch {
+		case err != nil:
+			log.Warnf("Failed to decode outbox event: %v", err)
+		case change.OperationType == "invalidate":
+			// The outbox was dropped or renamed, the stream is reopened after it
+			*resumeToken = changeStream.ResumeToken()
+			return errStreamInvalidated
+		default:
+			select {
+			case userEvents <- outboxEntityToEvent(&change.FullDocument):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		*resumeToken = changeStream.ResumeToken()
+		healthy()
+	}
+	return fmt.Errorf("change stream error: %w", changeStream.Err())
+}
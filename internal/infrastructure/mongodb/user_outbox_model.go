@@ -0,0 +1,31 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// UserOutboxEntity is a user event waiting to be published. Position orders the rows of a
+// user in the commit order of their writes.
+type UserOutboxEntity struct {
+	ID            primitive.ObjectID   `bson:"_id"`
+	EventId       string               `bson:"event_id"`
+	UserId        string               `bson:"user_id"`
+	Position      int64                `bson:"position"`
+	BeforeChange  *UserEntity          `bson:"before_change,omitempty"`
+	AfterChange   *UserEntity          `bson:"after_change,omitempty"`
+	OperationType domain.OperationType `bson:"operation_type"`
+	ModifiedBy    string               `bson:"modified_by"`
+	CorrelationId string               `bson:"correlation_id"`
+	TraceParent   string               `bson:"trace_parent"`
+	Sequence      int64                `bson:"sequence"`
+	CreatedAt     time.Time            `bson:"created_at"`
+}
+
+// UserOutboxSequenceEntity is the outbox position of the last write of a user. It is kept once
+// the user is deleted, for a user created again with the same id to follow its former events.
+type UserOutboxSequenceEntity struct {
+	ID       string `bson:"_id"`
+	Position int64  `bson:"position"`
+}
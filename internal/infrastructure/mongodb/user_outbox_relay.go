@@ -0,0 +1,199 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"sync"
+	"time"
+)
+
+const outboxBatchSize = 100
+
+// UsersOutboxRelay polls the outbox collection and emits its rows as user events, the events of
+// a user in the order of their positions, and up to MaxInFlight ahead of their acknowledgements,
+// one by default.
+// A row is deleted only once its event has been acknowledged as published, otherwise it
+// is emitted again: delivery is at-least-once. Once the channel returned by WatchUsers is
+// closed, WatchUsers can be called again.
+type UsersOutboxRelay struct {
+	outbox       *mongo.Collection
+	pollInterval time.Duration
+	maxInFlight  int
+	// window is the window of the current WatchUsers call
+	window *eventWindow
+
+	mu     sync.RWMutex
+	status domain.WatcherStatus
+	// results receives the outcomes of the events of the current WatchUsers call
+	results chan error
+}
+
+// NewOutboxRelay creates the relay, the index of the event ids, by which the published rows are
+// deleted, and the index of the user positions, by which the rows are fetched
+func NewOutboxRelay(outbox *mongo.Collection, pollInterval time.Duration) *UsersOutboxRelay {
+	ensureIndexes(outbox,
+		mongo.IndexModel{Keys: bson.D{{Key: "event_id", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "position", Value: 1}}},
+	)
+	return &UsersOutboxRelay{
+		outbox:       outbox,
+		pollInterval: pollInterval,
+		maxInFlight:  1,
+		status:       domain.WatcherStatus{State: domain.WatcherStateStarting},
+	}
+}
+
+// WithMaxInFlight lets up to maxInFlight events wait for their acknowledgement, for a pipeline
+// to process them in parallel
+func (r *UsersOutboxRelay) WithMaxInFlight(maxInFlight int) *UsersOutboxRelay {
+	r.maxInFlight = maxInFlight
+	return r
+}
+
+func (r *UsersOutboxRelay) WatchUsers(ctx context.Context) <-chan *domain.UserEvent {
+	// The rows not acknowledged by a previous call are still in the outbox, and emitted again
+	userEvents := make(chan *domain.UserEvent)
+	results := make(chan error)
+	r.mu.Lock()
+	r.results = results
+	r.status.State = domain.WatcherStateStarting
+	r.mu.Unlock()
+	r.window = newEventWindow(userEvents, results, r.maxInFlight)
+
+	go func() {
+		defer close(userEvents)
+
+		log.Info("Started relaying user events from the outbox.")
+
+		ticker := time.NewTicker(r.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			err := r.relayPendingEvents(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Errorf("Error relaying outbox events: %v", err)
+			}
+			r.recordResult(err)
+
+			select {
+			case <-ctx.Done():
+				log.Info("Context canceled, stopping outbox relay.")
+				r.mu.Lock()
+				r.status.State = domain.WatcherStateStopped
+				r.mu.Unlock()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return userEvents
+}
+
+// relayPendingEvents emits the pending rows until the outbox is drained or an event fails
+func (r *UsersOutboxRelay) relayPendingEvents(ctx context.Context) error {
+	for {
+		entities, err := r.fetchPendingEvents(ctx)
+		if err != nil {
+			return err
+		}
+		if len(entities) == 0 {
+			return nil
+		}
+
+		for _, entity := range entities {
+			// The window waits for the publishing outcomes once full, to keep the order
+			if err := r.window.emit(ctx, outboxEntityToEvent(entity)); err != nil {
+				return fmt.Errorf("events not published, retrying later: %w", err)
+			}
+		}
+		// The rows of the batch have to be deleted before fetching the next one
+		if err := r.window.drain(ctx); err != nil {
+			return fmt.Errorf("events not published, retrying later: %w", err)
+		}
+	}
+}
+
+func (r *UsersOutboxRelay) WatcherStatus() domain.WatcherStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// recordResult tracks the consecutive failures, the rows are retried at the next poll
+func (r *UsersOutboxRelay) recordResult(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		r.status = domain.WatcherStatus{State: domain.WatcherStateRunning}
+		return
+	}
+	r.status.State = domain.WatcherStateRetrying
+	r.status.Attempts++
+	r.status.LastError = err.Error()
+}
+
+func (r *UsersOutboxRelay) fetchPendingEvents(ctx context.Context) ([]*UserOutboxEntity, error) {
+	// The ObjectIDs are generated by the writers, and do not follow the commit order
+	opts := options.Find().SetSort(bson.D{{Key: "user_id", Value: 1}, {Key: "position", Value: 1}}).
+		SetLimit(outboxBatchSize)
+	cursor, err := r.outbox.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	var entities []*UserOutboxEntity
+	if err = cursor.All(ctx, &entities); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox events: %w", err)
+	}
+	return entities, nil
+}
+
+// AckUserEvent removes the published event from the outbox
+func (r *UsersOutboxRelay) AckUserEvent(ctx context.Context, event *domain.UserEvent) error {
+	_, err := r.outbox.DeleteOne(ctx, bson.M{"event_id": event.Id})
+	r.report(ctx, err)
+	return err
+}
+
+// NackUserEvent keeps the event in the outbox to be emitted again
+func (r *UsersOutboxRelay) NackUserEvent(ctx context.Context, event *domain.UserEvent, err error) {
+	r.report(ctx, err)
+}
+
+func (r *UsersOutboxRelay) report(ctx context.Context, err error) {
+	r.mu.RLock()
+	results := r.results
+	r.mu.RUnlock()
+	select {
+	case results <- err:
+	case <-ctx.Done():
+	}
+}
+
+func outboxEntityToEvent(e *UserOutboxEntity) *domain.UserEvent {
+	event := &domain.UserEvent{
+		Id:            e.EventId,
+		UserId:        e.UserId,
+		BeforeChange:  userToDomain(e.BeforeChange),
+		AfterChange:   userToDomain(e.AfterChange),
+		OperationType: e.OperationType,
+		ModifiedBy:    e.ModifiedBy,
+		CorrelationId: e.CorrelationId,
+		TraceParent:   e.TraceParent,
+		Sequence:      e.Sequence,
+		OccurredAt:    e.CreatedAt,
+	}
+	event.ChangedFields = domain.ChangedUserFields(event.BeforeChange, event.AfterChange)
+	return event
+}
@@ -0,0 +1,250 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type UserOutboxRelayTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	outbox     *mongo.Collection
+	sequences  *mongo.Collection
+	repo       *mongodb.UserRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *UserOutboxRelayTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	// collections must exist before being written in a transaction
+	mongoDb := client.Database("testdb")
+	suite.Require().NoError(mongoDb.CreateCollection(ctx, "test"))
+	suite.Require().NoError(mongoDb.CreateCollection(ctx, "test_outbox"))
+	suite.Require().NoError(mongoDb.CreateCollection(ctx, "test_outbox_sequences"))
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = mongoDb.Collection("test")
+	suite.outbox = mongoDb.Collection("test_outbox")
+	suite.sequences = mongoDb.Collection("test_outbox_sequences")
+	suite.repo = mongodb.NewOutboxUserRepository(suite.collection, suite.outbox, suite.sequences)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 60*time.Second)
+}
+
+func (suite *UserOutboxRelayTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *UserOutboxRelayTestSuite) SetupTest() {
+	// Clean up the collections before each test, without dropping them
+	_, err := suite.collection.DeleteMany(suite.ctx, bson.M{})
+	suite.Require().NoError(err)
+	_, err = suite.outbox.DeleteMany(suite.ctx, bson.M{})
+	suite.Require().NoError(err)
+	_, err = suite.sequences.DeleteMany(suite.ctx, bson.M{})
+	suite.Require().NoError(err)
+}
+
+func (suite *UserOutboxRelayTestSuite) TestOutboxUserRepository_WritesEventsInTransaction() {
+	ctx := domain.ContextWithActor(suite.ctx, &domain.Actor{ID: "admin-1"})
+	ctx = domain.ContextWithCorrelationId(ctx, "request-1")
+	now := time.Now().UTC().Round(time.Millisecond)
+	user := &domain.User{
+		ID:             uuid.NewString(),
+		FirstName:      "Federico",
+		LastName:       "La Penna",
+		Email:          "flapenna@email.com",
+		HashedPassword: "password",
+		Country:        "IT",
+		Nickname:       "Pennino",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Version:        1,
+	}
+
+	suite.Require().NoError(suite.repo.CreateUser(ctx, user))
+	user.FirstName = "Fede"
+	suite.Require().NoError(suite.repo.UpdateUser(ctx, user))
+	suite.Require().NoError(suite.repo.DeleteUserById(ctx, user.ID))
+
+	// a failed write must not leave an event behind
+	err := suite.repo.DeleteUserById(ctx, user.ID)
+	suite.Equal(domain.ErrUserNotFound, err)
+
+	cursor, err := suite.outbox.Find(suite.ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	suite.Require().NoError(err)
+	var events []*mongodb.UserOutboxEntity
+	suite.Require().NoError(cursor.All(suite.ctx, &events))
+	suite.Require().Len(events, 3)
+
+	suite.Equal(domain.OPERATION_CREATE, events[0].OperationType)
+	suite.Nil(events[0].BeforeChange)
+	suite.Equal("Federico", events[0].AfterChange.FirstName)
+	// the hashed password is never written to the outbox
+	suite.Empty(events[0].AfterChange.HashedPassword)
+
+	suite.Equal(domain.OPERATION_UPDATE, events[1].OperationType)
+	suite.Equal("Federico", events[1].BeforeChange.FirstName)
+	suite.Equal("Fede", events[1].AfterChange.FirstName)
+	suite.Equal(int64(2), events[1].AfterChange.Version)
+
+	suite.Equal(domain.OPERATION_DELETE, events[2].OperationType)
+	suite.Equal("Fede", events[2].BeforeChange.FirstName)
+	suite.Nil(events[2].AfterChange)
+
+	for i, e := range events {
+		// The sequence is the version resulting from the change, and the position numbers the writes
+		suite.Equal(int64(i+1), e.Sequence)
+		suite.Equal(int64(i+1), e.Position)
+		suite.Equal(user.ID, e.UserId)
+		suite.Equal("admin-1", e.ModifiedBy)
+		suite.Equal("request-1", e.CorrelationId)
+	}
+}
+
+func (suite *UserOutboxRelayTestSuite) TestOutboxRelay_AtLeastOnceDelivery() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+
+	user := &domain.User{ID: uuid.NewString(), FirstName: "Federico", Version: 1}
+	suite.Require().NoError(suite.repo.CreateUser(ctx, user))
+	suite.Require().NoError(suite.repo.DeleteUserById(ctx, user.ID))
+
+	relay := mongodb.NewOutboxRelay(suite.outbox, 100*time.Millisecond)
+	events := relay.WatchUsers(ctx)
+
+	next := func() *domain.UserEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			suite.FailNow("Timed out waiting for outbox event")
+			return nil
+		}
+	}
+
+	// the first event fails to be published and is emitted again
+	created := next()
+	suite.Equal(domain.OPERATION_CREATE, created.OperationType)
+	relay.NackUserEvent(ctx, created, errors.New("producer error"))
+
+	redelivered := next()
+	suite.Equal(created.Id, redelivered.Id)
+	suite.Require().NoError(relay.AckUserEvent(ctx, redelivered))
+
+	deleted := next()
+	suite.Equal(domain.OPERATION_DELETE, deleted.OperationType)
+	suite.Require().NoError(relay.AckUserEvent(ctx, deleted))
+
+	// acknowledged events are removed from the outbox
+	count, err := suite.outbox.CountDocuments(suite.ctx, bson.M{})
+	suite.Require().NoError(err)
+	suite.Equal(int64(0), count)
+}
+
+func (suite *UserOutboxRelayTestSuite) TestOutboxRelay_OrdersInterleavedTransactionsByPosition() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+
+	user := &domain.User{ID: uuid.NewString(), FirstName: "Federico", Version: 1}
+	suite.Require().NoError(suite.repo.CreateUser(ctx, user))
+
+	// Two writers update the same user concurrently, their transactions conflict and are retried
+	const updates = 5
+	var wg sync.WaitGroup
+	for writer := 0; writer < 2; writer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < updates; i++ {
+				suite.NoError(suite.repo.UpdateUser(ctx, &domain.User{ID: user.ID, FirstName: "Federico"}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A writer with a clock ahead generates greater ObjectIDs: the row of the second write
+	// is inserted again with an ObjectID after every other row
+	var second mongodb.UserOutboxEntity
+	suite.Require().NoError(suite.outbox.FindOneAndDelete(suite.ctx, bson.M{"position": 2}).Decode(&second))
+	second.ID = primitive.NewObjectIDFromTimestamp(time.Now().Add(time.Hour))
+	_, err := suite.outbox.InsertOne(suite.ctx, second)
+	suite.Require().NoError(err)
+
+	relay := mongodb.NewOutboxRelay(suite.outbox, 100*time.Millisecond)
+	events := relay.WatchUsers(ctx)
+	for version := int64(1); version <= 2*updates+1; version++ {
+		select {
+		case event := <-events:
+			// The writes of the user are emitted in commit order, the versions they resulted in
+			suite.Equal(version, event.AfterChange.Version)
+			suite.Require().NoError(relay.AckUserEvent(ctx, event))
+		case <-time.After(5 * time.Second):
+			suite.FailNow("Timed out waiting for outbox event")
+		}
+	}
+}
+
+func (suite *UserOutboxRelayTestSuite) TestOutboxFeed_FollowsInserts() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+
+	feed := mongodb.NewOutboxFeed(suite.outbox, mongodb.DefaultRetryPolicy)
+	events := feed.WatchUsers(ctx)
+	time.Sleep(time.Second)
+
+	user := &domain.User{ID: uuid.NewString(), FirstName: "Federico", Version: 1}
+	suite.Require().NoError(suite.repo.CreateUser(ctx, user))
+
+	select {
+	case event := <-events:
+		suite.Equal(user.ID, event.UserId)
+		suite.Equal(domain.OPERATION_CREATE, event.OperationType)
+		// The feed emits the event with the id the relay publishes it with, and leaves the row
+		var row mongodb.UserOutboxEntity
+		suite.Require().NoError(suite.outbox.FindOne(ctx, bson.M{}).Decode(&row))
+		suite.Equal(row.EventId, event.Id)
+	case <-time.After(5 * time.Second):
+		suite.FailNow("Timed out waiting for outbox event")
+	}
+}
+
+func TestUserOutboxRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(UserOutboxRelayTestSuite))
+}
@@ -0,0 +1,25 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"time"
+)
+
+// UserReplayEntity is the progress of a user replay, keyed by replay id
+type UserReplayEntity struct {
+	ID            string                 `bson:"_id"`
+	Country       *string                `bson:"country,omitempty"`
+	FirstName     *string                `bson:"first_name,omitempty"`
+	LastName      *string                `bson:"last_name,omitempty"`
+	Nickname      *string                `bson:"nickname,omitempty"`
+	Email         *string                `bson:"email,omitempty"`
+	LastUserId    string                 `bson:"last_user_id"`
+	RatePerSecond uint32                 `bson:"rate_per_second"`
+	State         domain.UserReplayState `bson:"state"`
+	Owner         string                 `bson:"owner,omitempty"`
+	ReplayedCount int64                  `bson:"replayed_count"`
+	Error         string                 `bson:"error,omitempty"`
+	StartedBy     string                 `bson:"started_by"`
+	StartedAt     time.Time              `bson:"started_at"`
+	UpdatedAt     time.Time              `bson:"updated_at"`
+}
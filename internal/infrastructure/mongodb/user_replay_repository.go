@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type UserReplayRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserReplayRepository creates the repository and the index the replays are claimed with
+func NewUserReplayRepository(collection *mongo.Collection) *UserReplayRepository {
+	ensureIndexes(collection, mongo.IndexModel{Keys: bson.D{{Key: "state", Value: 1}, {Key: "started_at", Value: 1}}})
+	return &UserReplayRepository{
+		collection: collection,
+	}
+}
+
+func (r *UserReplayRepository) GetUserReplay(ctx context.Context, id string) (*domain.UserReplay, error) {
+	var entity *UserReplayEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrUserReplayNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return userReplayToDomain(entity), nil
+}
+
+func (r *UserReplayRepository) SaveUserReplay(ctx context.Context, replay *domain.UserReplay) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": replay.Id}, toUserReplayEntity(replay), options.Replace().SetUpsert(true))
+	return err
+}
+
+// ClaimUserReplay switches the oldest claimable replay to running in a single update, for a
+// replay to be claimed by one owner only
+func (r *UserReplayRepository) ClaimUserReplay(ctx context.Context, owner string, staleBefore time.Time) (*domain.UserReplay, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"state": domain.UserReplayStatePending},
+		bson.M{"state": domain.UserReplayStateRunning, "updated_at": bson.M{"$lt": staleBefore}},
+	}}
+	update := bson.M{"$set": bson.M{
+		"state":      domain.UserReplayStateRunning,
+		"owner":      owner,
+		"updated_at": time.Now().UTC().Round(time.Millisecond),
+	}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "started_at", Value: 1}}).SetReturnDocument(options.After)
+
+	var entity *UserReplayEntity
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrUserReplayNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return userReplayToDomain(entity), nil
+}
+
+func (r *UserReplayRepository) CheckpointUserReplay(ctx context.Context, replay *domain.UserReplay, owner string) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": replay.Id, "owner": owner}, toUserReplayEntity(replay))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserReplayTakenOver
+	}
+	return nil
+}
+
+func toUserReplayEntity(replay *domain.UserReplay) *UserReplayEntity {
+	return &UserReplayEntity{
+		ID:            replay.Id,
+		Country:       replay.Query.Country,
+		FirstName:     replay.Query.FirstName,
+		LastName:      replay.Query.LastName,
+		Nickname:      replay.Query.Nickname,
+		Email:         replay.Query.Email,
+		LastUserId:    replay.Query.AfterId,
+		RatePerSecond: replay.RatePerSecond,
+		State:         replay.State,
+		Owner:         replay.Owner,
+		ReplayedCount: replay.ReplayedCount,
+		Error:         replay.Error,
+		StartedBy:     replay.StartedBy,
+		StartedAt:     replay.StartedAt,
+		UpdatedAt:     replay.UpdatedAt,
+	}
+}
+
+func userReplayToDomain(e *UserReplayEntity) *domain.UserReplay {
+	return &domain.UserReplay{
+		Id: e.ID,
+		Query: domain.ScanUsersQuery{
+			AfterId:   e.LastUserId,
+			Country:   e.Country,
+			FirstName: e.FirstName,
+			LastName:  e.LastName,
+			Nickname:  e.Nickname,
+			Email:     e.Email,
+		},
+		RatePerSecond: e.RatePerSecond,
+		State:         e.State,
+		Owner:         e.Owner,
+		ReplayedCount: e.ReplayedCount,
+		Error:         e.Error,
+		StartedBy:     e.StartedBy,
+		StartedAt:     e.StartedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+}
@@ -0,0 +1,165 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type UserReplayRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.UserReplayRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *UserReplayRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_user_replays")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewUserReplayRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *UserReplayRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *UserReplayRepositoryTestSuite) SetupTest() {
+	// Clean up the collection before each test
+	suite.collection.Drop(suite.ctx)
+}
+
+func (suite *UserReplayRepositoryTestSuite) TestUserReplayRepository_SaveUserReplay() {
+	startedAt := time.Now().UTC().Round(time.Millisecond)
+	country := "UK"
+	replay := &domain.UserReplay{
+		Id:            "replay-1",
+		Query:         domain.ScanUsersQuery{Country: &country},
+		RatePerSecond: 50,
+		State:         domain.UserReplayStateRunning,
+		StartedBy:     "admin-1",
+		StartedAt:     startedAt,
+		UpdatedAt:     startedAt,
+	}
+	suite.Require().NoError(suite.repo.SaveUserReplay(suite.ctx, replay))
+
+	// A checkpoint replaces the saved replay
+	replay.Query.AfterId = "user-100"
+	replay.ReplayedCount = 100
+	replay.State = domain.UserReplayStateFailed
+	replay.Error = "producer error"
+	replay.UpdatedAt = startedAt.Add(time.Minute)
+	suite.Require().NoError(suite.repo.SaveUserReplay(suite.ctx, replay))
+
+	saved, err := suite.repo.GetUserReplay(suite.ctx, "replay-1")
+	suite.Require().NoError(err)
+	suite.Equal("replay-1", saved.Id)
+	suite.Equal("user-100", saved.Query.AfterId)
+	suite.Equal(&country, saved.Query.Country)
+	suite.Nil(saved.Query.Email)
+	suite.Equal(uint32(50), saved.RatePerSecond)
+	suite.Equal(domain.UserReplayStateFailed, saved.State)
+	suite.Equal(int64(100), saved.ReplayedCount)
+	suite.Equal("producer error", saved.Error)
+	suite.Equal("admin-1", saved.StartedBy)
+	suite.True(startedAt.Equal(saved.StartedAt))
+	suite.True(replay.UpdatedAt.Equal(saved.UpdatedAt))
+
+	count, err := suite.collection.CountDocuments(suite.ctx, map[string]any{})
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *UserReplayRepositoryTestSuite) TestUserReplayRepository_ClaimUserReplay() {
+	now := time.Now().UTC().Round(time.Millisecond)
+	for i, replay := range []*domain.UserReplay{
+		{Id: "replay-running", State: domain.UserReplayStateRunning, Owner: "instance-0", UpdatedAt: now},
+		{Id: "replay-pending", State: domain.UserReplayStatePending},
+		{Id: "replay-completed", State: domain.UserReplayStateCompleted},
+	} {
+		replay.StartedAt = now.Add(time.Duration(i) * time.Second)
+		suite.Require().NoError(suite.repo.SaveUserReplay(suite.ctx, replay))
+	}
+
+	// Two instances claim concurrently, only one of them gets the pending replay
+	staleBefore := now.Add(-time.Minute)
+	claims := make(chan *domain.UserReplay, 2)
+	errs := make(chan error, 2)
+	for _, owner := range []string{"instance-1", "instance-2"} {
+		go func() {
+			replay, err := suite.repo.ClaimUserReplay(suite.ctx, owner, staleBefore)
+			claims <- replay
+			errs <- err
+		}()
+	}
+	var claimed *domain.UserReplay
+	notFound := 0
+	for i := 0; i < 2; i++ {
+		replay, err := <-claims, <-errs
+		if errors.Is(err, domain.ErrUserReplayNotFound) {
+			notFound++
+			continue
+		}
+		suite.Require().NoError(err)
+		claimed = replay
+	}
+	suite.Equal(1, notFound)
+	suite.Require().NotNil(claimed)
+	suite.Equal("replay-pending", claimed.Id)
+	suite.Equal(domain.UserReplayStateRunning, claimed.State)
+
+	// The former owner can no longer checkpoint a replay another instance took over
+	suite.Require().NoError(suite.repo.CheckpointUserReplay(suite.ctx, claimed, claimed.Owner))
+	suite.Equal(domain.ErrUserReplayTakenOver, suite.repo.CheckpointUserReplay(suite.ctx, claimed, "instance-0"))
+
+	// A running replay not checkpointed since staleBefore is claimed again, its owner being gone
+	replay, err := suite.repo.ClaimUserReplay(suite.ctx, "instance-3", now.Add(time.Second))
+	suite.Require().NoError(err)
+	suite.Equal("replay-running", replay.Id)
+	suite.Equal("instance-3", replay.Owner)
+	suite.Equal(domain.ErrUserReplayTakenOver, suite.repo.CheckpointUserReplay(suite.ctx, replay, "instance-0"))
+}
+
+func (suite *UserReplayRepositoryTestSuite) TestUserReplayRepository_GetUserReplayNotFound() {
+	_, err := suite.repo.GetUserReplay(suite.ctx, "replay-1")
+	suite.Equal(domain.ErrUserReplayNotFound, err)
+}
+
+func TestUserReplayRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserReplayRepositoryTestSuite))
+}
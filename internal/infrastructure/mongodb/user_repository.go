@@ -5,14 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 type UserRepository struct {
 	collection *mongo.Collection
+	outbox     *mongo.Collection
+	sequences  *mongo.Collection
+	deletions  *mongo.Collection
 }
 
 func NewUserRepository(collection *mongo.Collection) *UserRepository {
@@ -21,41 +27,122 @@ func NewUserRepository(collection *mongo.Collection) *UserRepository {
 	}
 }
 
+// NewOutboxUserRepository returns a UserRepository that records every write as a user
+// event in the outbox collection, in the same transaction as the write itself. The events
+// of a user are numbered with a counter per user kept in sequences.
+func NewOutboxUserRepository(collection *mongo.Collection, outbox *mongo.Collection, sequences *mongo.Collection) *UserRepository {
+	return &UserRepository{
+		collection: collection,
+		outbox:     outbox,
+		sequences:  sequences,
+	}
+}
+
+// WithDeletions records the actor deleting a user in collection, in the same transaction as the
+// delete, for the watchers to attribute the delete events
+func (r *UserRepository) WithDeletions(collection *mongo.Collection) *UserRepository {
+	r.deletions = collection
+	return r
+}
+
 func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) error {
-	_, err := r.collection.InsertOne(ctx, toEntity(user))
+	return r.inTransaction(ctx, func(ctx context.Context) error {
+		entity := toEntity(user)
+		_, err := r.collection.InsertOne(ctx, entity)
+		if err != nil {
+			return err
+		}
+		return r.appendToOutbox(ctx, domain.OPERATION_CREATE, nil, entity)
+	})
+}
+
+func (r *UserRepository) GetUserById(ctx context.Context, id string) (*domain.User, error) {
+	var user *UserEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrUserNotFound
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return userToDomain(user), nil
 }
 
+// UpdateUser replaces the user fields and increments its version. When user.Version
+// is set, the update only applies if the stored document is still at that version.
 func (r *UserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
-	filter := bson.M{"_id": user.ID}
-	update := bson.M{"$set": toEntity(user)}
-	// Configure options to return the updated document
-	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-
-	var updatedUser *UserEntity
-	result := r.collection.FindOneAndUpdate(ctx, filter, update, opts)
-	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
-		return domain.ErrUserNotFound
-	}
-	if err := result.Decode(&updatedUser); err != nil {
-		return err
-	}
+	return r.inTransaction(ctx, func(ctx context.Context) error {
+		filter := bson.M{"_id": user.ID}
+		if user.Version > 0 {
+			filter["version"] = user.Version
+		}
 
-	user.CreatedAt = updatedUser.CreatedAt
+		// The outbox event needs the pre-image, read within the same transaction
+		var previousUser *UserEntity
+		if r.outbox != nil {
+			err := r.collection.FindOne(ctx, filter).Decode(&previousUser)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return err
+			}
+		}
 
-	return nil
+		entity := toEntity(user)
+		entity.Version = 0
+		update := bson.M{"$set": entity, "$inc": bson.M{"version": 1}}
+		// Configure options to return the updated document
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		var updatedUser *UserEntity
+		result := r.collection.FindOneAndUpdate(ctx, filter, update, opts)
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			if user.Version > 0 {
+				return r.versionMismatchError(ctx, user.ID)
+			}
+			return domain.ErrUserNotFound
+		}
+		if err := result.Decode(&updatedUser); err != nil {
+			return err
+		}
+
+		user.CreatedAt = updatedUser.CreatedAt
+		user.Version = updatedUser.Version
+
+		return r.appendToOutbox(ctx, domain.OPERATION_UPDATE, previousUser, updatedUser)
+	})
 }
 
-func (r *UserRepository) DeleteUserById(ctx context.Context, id string) error {
-	filter := bson.M{"_id": id}
-	result := r.collection.FindOneAndDelete(ctx, filter)
-	if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+// versionMismatchError tells apart a missing user from one at a different version
+func (r *UserRepository) versionMismatchError(ctx context.Context, id string) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
 		return domain.ErrUserNotFound
 	}
-	return result.Err()
+	return domain.ErrVersionConflict
+}
+
+func (r *UserRepository) DeleteUserById(ctx context.Context, id string) error {
+	deleteUser := func(ctx context.Context) error {
+		var deletedUser *UserEntity
+		result := r.collection.FindOneAndDelete(ctx, bson.M{"_id": id})
+		if errors.Is(result.Err(), mongo.ErrNoDocuments) {
+			return domain.ErrUserNotFound
+		}
+		if err := result.Decode(&deletedUser); err != nil {
+			return err
+		}
+
+		if err := r.recordDeletion(ctx, id); err != nil {
+			return err
+		}
+		return r.appendToOutbox(ctx, domain.OPERATION_DELETE, deletedUser, nil)
+	}
+	if r.deletions != nil {
+		return r.transaction(ctx, deleteUser)
+	}
+	return r.inTransaction(ctx, deleteUser)
 }
 
 func (r *UserRepository) ListUsers(ctx context.Context, request *domain.ListUsersQueryRequest) (*domain.ListUsersQueryResponse, error) {
@@ -63,24 +150,7 @@ func (r *UserRepository) ListUsers(ctx context.Context, request *domain.ListUser
 		request.PageSize = 10
 	}
 
-	filter := bson.M{}
-
-	// Add filters based on the request
-	if request.Country != nil {
-		filter["country"] = request.Country
-	}
-	if request.FirstName != nil {
-		filter["first_name"] = request.FirstName
-	}
-	if request.LastName != nil {
-		filter["last_name"] = request.LastName
-	}
-	if request.Nickname != nil {
-		filter["nickname"] = request.Nickname
-	}
-	if request.Email != nil {
-		filter["email"] = request.Email
-	}
+	filter := usersFilter(request.Country, request.FirstName, request.LastName, request.Nickname, request.Email)
 
 	// Get the total count of documents matching the filter
 	totalCount, err := r.collection.CountDocuments(ctx, filter)
@@ -116,6 +186,155 @@ func (r *UserRepository) ListUsers(ctx context.Context, request *domain.ListUser
 	}, nil
 }
 
+// ScanUsers reads the users in id order through a single cursor, without loading them all
+func (r *UserRepository) ScanUsers(ctx context.Context, query *domain.ScanUsersQuery, fn func(user *domain.User) error) error {
+	filter := usersFilter(query.Country, query.FirstName, query.LastName, query.Nickname, query.Email)
+	if query.AfterId != "" {
+		filter["_id"] = bson.M{"$gt": query.AfterId}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("failed to scan users: %w", err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	for cursor.Next(ctx) {
+		var user UserEntity
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+		if err := fn(userToDomain(&user)); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to scan users: %w", err)
+	}
+	return nil
+}
+
+// usersFilter matches the users equal to every filter provided
+func usersFilter(country, firstName, lastName, nickname, email *string) bson.M {
+	filter := bson.M{}
+	if country != nil {
+		filter["country"] = country
+	}
+	if firstName != nil {
+		filter["first_name"] = firstName
+	}
+	if lastName != nil {
+		filter["last_name"] = lastName
+	}
+	if nickname != nil {
+		filter["nickname"] = nickname
+	}
+	if email != nil {
+		filter["email"] = email
+	}
+	return filter
+}
+
+// inTransaction runs fn in a transaction when the outbox is enabled, so that the user
+// write and its outbox event are committed atomically
+func (r *UserRepository) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.outbox == nil {
+		return fn(ctx)
+	}
+	return r.transaction(ctx, fn)
+}
+
+func (r *UserRepository) transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := r.collection.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// recordDeletion saves the actor deleting the user, it is a no-op without a deletions collection.
+// The record of a former deletion of the same id is replaced.
+func (r *UserRepository) recordDeletion(ctx context.Context, id string) error {
+	if r.deletions == nil {
+		return nil
+	}
+
+	deletion := &UserDeletionEntity{
+		ID:            id,
+		DeletedBy:     domain.ActorIdFromContext(ctx),
+		CorrelationId: domain.CorrelationIdFromContext(ctx),
+		TraceParent:   domain.TraceParentFromContext(ctx),
+		DeletedAt:     time.Now().UTC().Round(time.Millisecond),
+	}
+	_, err := r.deletions.ReplaceOne(ctx, bson.M{"_id": id}, deletion, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record user deletion: %w", err)
+	}
+	return nil
+}
+
+// appendToOutbox records the user event of a write, it is a no-op when the outbox is disabled
+func (r *UserRepository) appendToOutbox(ctx context.Context, operationType domain.OperationType, beforeChange, afterChange *UserEntity) error {
+	if r.outbox == nil {
+		return nil
+	}
+
+	event := &UserOutboxEntity{
+		ID:            primitive.NewObjectID(),
+		EventId:       uuid.NewString(),
+		OperationType: operationType,
+		ModifiedBy:    domain.ActorIdFromContext(ctx),
+		CorrelationId: domain.CorrelationIdFromContext(ctx),
+		TraceParent:   domain.TraceParentFromContext(ctx),
+		Sequence:      eventSequence(beforeChange, afterChange),
+		CreatedAt:     time.Now().UTC().Round(time.Millisecond),
+	}
+	// Store the images without the hashed password
+	if beforeChange != nil {
+		event.UserId = beforeChange.ID
+		event.BeforeChange = toEntity(userToDomain(beforeChange))
+	}
+	if afterChange != nil {
+		event.UserId = afterChange.ID
+		event.AfterChange = toEntity(userToDomain(afterChange))
+	}
+
+	position, err := r.nextOutboxPosition(ctx, event.UserId)
+	if err != nil {
+		return err
+	}
+	event.Position = position
+
+	_, err = r.outbox.InsertOne(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to append user event to outbox: %w", err)
+	}
+	return nil
+}
+
+// nextOutboxPosition increments the outbox counter of the user in the transaction of the write.
+// The concurrent writes of a user conflict on the counter, so the positions follow the commit
+// order whatever the instance and the clock the writes come from.
+func (r *UserRepository) nextOutboxPosition(ctx context.Context, userId string) (int64, error) {
+	var sequence UserOutboxSequenceEntity
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.sequences.FindOneAndUpdate(ctx, bson.M{"_id": userId}, bson.M{"$inc": bson.M{"position": 1}}, opts).
+		Decode(&sequence)
+	if err != nil {
+		return 0, fmt.Errorf("failed to number user event in outbox: %w", err)
+	}
+	return sequence.Position, nil
+}
+
 func usersToDomain(ul []*UserEntity) []*domain.User {
 	users := make([]*domain.User, len(ul))
 	for i, u := range ul {
@@ -129,14 +348,18 @@ func userToDomain(u *UserEntity) *domain.User {
 		return nil
 	}
 	return &domain.User{
-		ID:        u.ID,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Email:     u.Email,
-		Country:   u.Country,
-		Nickname:  u.Nickname,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:             u.ID,
+		FirstName:      u.FirstName,
+		LastName:       u.LastName,
+		Email:          u.Email,
+		Country:        u.Country,
+		Nickname:       u.Nickname,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+		LastModifiedBy: u.LastModifiedBy,
+		CorrelationId:  u.CorrelationId,
+		TraceParent:    u.TraceParent,
+		Version:        u.Version,
 	}
 }
 
@@ -151,5 +374,9 @@ func toEntity(user *domain.User) *UserEntity {
 		Nickname:       user.Nickname,
 		CreatedAt:      user.CreatedAt,
 		UpdatedAt:      user.UpdatedAt,
+		LastModifiedBy: user.LastModifiedBy,
+		CorrelationId:  user.CorrelationId,
+		TraceParent:    user.TraceParent,
+		Version:        user.Version,
 	}
 }
@@ -4,6 +4,7 @@ package mongodb_test
 
 import (
 	"context"
+	"errors"
 	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
 	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
 	"github.com/google/uuid"
@@ -23,6 +24,7 @@ type UserRepositoryTestSuite struct {
 	mongoC     testcontainers.Container
 	client     *mongo.Client
 	collection *mongo.Collection
+	deletions  *mongo.Collection
 	repo       *mongodb.UserRepository
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -56,7 +58,8 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 	suite.mongoC = mongoC
 	suite.client = client
 	suite.collection = collection
-	suite.repo = mongodb.NewUserRepository(collection)
+	suite.deletions = mongoDb.Collection("test_deletions")
+	suite.repo = mongodb.NewUserRepository(collection).WithDeletions(suite.deletions)
 	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
 }
 
@@ -142,6 +145,7 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_CreateUser() {
 func (suite *UserRepositoryTestSuite) TestUserRepository_UpdateUser() {
 	createdAt := time.Now().UTC().Add(-time.Hour)
 	id := uuid.NewString()
+	versionedId := uuid.NewString()
 	tests := []struct {
 		name      string
 		seed      *domain.User
@@ -190,6 +194,48 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_UpdateUser() {
 			},
 			wantedErr: domain.ErrUserNotFound,
 		},
+		{
+			name: "update user at expected version",
+			seed: &domain.User{
+				ID:        versionedId,
+				FirstName: "Federico",
+				LastName:  "La Penna",
+				Email:     "flapenna@email.com",
+				Country:   "IT",
+				Nickname:  "Pennino",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+				Version:   1,
+			},
+			req: &domain.User{
+				ID:        versionedId,
+				FirstName: "Federico Updated",
+				LastName:  "La Penna",
+				Email:     "flapenna@email.com",
+				Country:   "IT",
+				Nickname:  "Pennino",
+				CreatedAt: createdAt,
+				UpdatedAt: time.Now().UTC(),
+				Version:   1,
+			},
+			wantedErr: nil,
+		},
+		{
+			name: "trying to update user at stale version",
+			seed: nil,
+			req: &domain.User{
+				ID:        versionedId,
+				FirstName: "Federico Stale",
+				LastName:  "La Penna",
+				Email:     "flapenna@email.com",
+				Country:   "IT",
+				Nickname:  "Pennino",
+				CreatedAt: createdAt,
+				UpdatedAt: time.Now().UTC(),
+				Version:   1,
+			},
+			wantedErr: domain.ErrVersionConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -199,6 +245,7 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_UpdateUser() {
 				err := suite.repo.CreateUser(suite.ctx, tt.seed)
 				suite.Require().NoError(err)
 			}
+			expectedVersion := tt.req.Version
 
 			err := suite.repo.UpdateUser(suite.ctx, tt.req)
 			if tt.wantedErr != nil {
@@ -220,6 +267,72 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_UpdateUser() {
 				suite.Equal(tt.req.Country, updated.Country)
 				suite.WithinDuration(tt.req.CreatedAt, updated.CreatedAt, time.Millisecond)
 				suite.WithinDuration(tt.req.UpdatedAt, updated.UpdatedAt, time.Millisecond)
+				if expectedVersion > 0 {
+					suite.Equal(expectedVersion+1, updated.Version)
+				}
+				suite.Equal(updated.Version, tt.req.Version)
+			}
+		})
+	}
+}
+
+func (suite *UserRepositoryTestSuite) TestUserRepository_GetUserById() {
+	id := uuid.NewString()
+	now := time.Now().UTC().Round(time.Millisecond)
+	tests := []struct {
+		name      string
+		seed      *domain.User
+		req       string
+		wantedErr error
+	}{
+		{
+			name: "get user by id",
+			seed: &domain.User{
+				ID:             id,
+				FirstName:      "Federico",
+				LastName:       "La Penna",
+				Email:          "flapenna@email.com",
+				HashedPassword: "password",
+				Country:        "IT",
+				Nickname:       "Pennino",
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			},
+			req:       id,
+			wantedErr: nil,
+		},
+		{
+			name:      "trying to get not existing user",
+			seed:      nil,
+			req:       uuid.NewString(),
+			wantedErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			if tt.seed != nil {
+				// seed user as pre-requisite
+				err := suite.repo.CreateUser(suite.ctx, tt.seed)
+				suite.Require().NoError(err)
+			}
+
+			user, err := suite.repo.GetUserById(suite.ctx, tt.req)
+			if tt.wantedErr != nil {
+				suite.Error(err)
+				suite.Equal(tt.wantedErr, err)
+			} else {
+				suite.Require().NoError(err)
+				suite.Equal(tt.seed.ID, user.ID)
+				suite.Equal(tt.seed.FirstName, user.FirstName)
+				suite.Equal(tt.seed.LastName, user.LastName)
+				suite.Equal(tt.seed.Email, user.Email)
+				suite.Equal(tt.seed.Country, user.Country)
+				suite.Equal(tt.seed.Nickname, user.Nickname)
+				// the hashed password must never leave the repository
+				suite.Empty(user.HashedPassword)
+				suite.WithinDuration(tt.seed.CreatedAt, user.CreatedAt, time.Millisecond)
+				suite.WithinDuration(tt.seed.UpdatedAt, user.UpdatedAt, time.Millisecond)
 			}
 		})
 	}
@@ -266,10 +379,14 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_DeleteUserByID() {
 				suite.Require().NoError(err)
 			}
 
-			err := suite.repo.DeleteUserById(suite.ctx, tt.req)
+			ctx := domain.ContextWithActor(suite.ctx, &domain.Actor{ID: "admin-1"})
+			err := suite.repo.DeleteUserById(ctx, tt.req)
+			var deletion mongodb.UserDeletionEntity
+			deletionErr := suite.deletions.FindOne(context.Background(), bson.M{"_id": tt.req}).Decode(&deletion)
 			if tt.wantedErr != nil {
 				suite.Error(err)
 				suite.Equal(tt.wantedErr, err)
+				suite.Equal(mongo.ErrNoDocuments, deletionErr)
 			} else {
 				suite.Require().NoError(err)
 
@@ -278,6 +395,10 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_DeleteUserByID() {
 				err = suite.collection.FindOne(context.Background(), bson.M{"_id": tt.req}).Decode(&updated)
 				suite.Require().Error(err)
 				suite.Equal(mongo.ErrNoDocuments, err)
+
+				// check the deleting actor has been recorded
+				suite.Require().NoError(deletionErr)
+				suite.Equal("admin-1", deletion.DeletedBy)
 			}
 		})
 	}
@@ -624,6 +745,36 @@ func (suite *UserRepositoryTestSuite) TestUserRepository_ListUsers() {
 	}
 }
 
+func (suite *UserRepositoryTestSuite) TestUserRepository_ScanUsers() {
+	for _, id := range []string{"user-3", "user-1", "user-2"} {
+		suite.Require().NoError(suite.repo.CreateUser(suite.ctx, &domain.User{ID: id, FirstName: "Federico", Version: 1}))
+	}
+
+	scan := func(query *domain.ScanUsersQuery) []string {
+		ids := make([]string, 0)
+		err := suite.repo.ScanUsers(suite.ctx, query, func(user *domain.User) error {
+			ids = append(ids, user.ID)
+			return nil
+		})
+		suite.Require().NoError(err)
+		return ids
+	}
+
+	// The users are read in id order, and the scan can resume after a given id
+	suite.Equal([]string{"user-1", "user-2", "user-3"}, scan(&domain.ScanUsersQuery{}))
+	suite.Equal([]string{"user-3"}, scan(&domain.ScanUsersQuery{AfterId: "user-2"}))
+
+	// The scan stops at the first error
+	stop := errors.New("stop")
+	calls := 0
+	err := suite.repo.ScanUsers(suite.ctx, &domain.ScanUsersQuery{}, func(user *domain.User) error {
+		calls++
+		return stop
+	})
+	suite.ErrorIs(err, stop)
+	suite.Equal(1, calls)
+}
+
 func TestUserRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
@@ -0,0 +1,14 @@
+package mongodb
+
+import "time"
+
+type UserVersionEntity struct {
+	ID            string      `bson:"_id"`
+	UserId        string      `bson:"user_id"`
+	Version       int64       `bson:"version"`
+	User          *UserEntity `bson:"user,omitempty"`
+	Deleted       bool        `bson:"deleted"`
+	ValidFrom     time.Time   `bson:"valid_from"`
+	ModifiedBy    string      `bson:"modified_by"`
+	CorrelationId string      `bson:"correlation_id"`
+}
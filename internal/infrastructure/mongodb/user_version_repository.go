@@ -0,0 +1,147 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type UserVersionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserVersionRepository creates the repository and the indexes of the point-in-time lookups
+// and of the version listings of a user
+func NewUserVersionRepository(collection *mongo.Collection) *UserVersionRepository {
+	ensureIndexes(collection,
+		mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "valid_from", Value: -1}, {Key: "version", Value: -1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "version", Value: -1}}},
+	)
+	return &UserVersionRepository{
+		collection: collection,
+	}
+}
+
+// AppendUserVersion stores a version, appending the same version twice is a no-op
+func (r *UserVersionRepository) AppendUserVersion(ctx context.Context, version *domain.UserVersion) error {
+	_, err := r.collection.InsertOne(ctx, toVersionEntity(version))
+	if mongo.IsDuplicateKeyError(err) {
+		log.Debugf("version %d of user %s already recorded", version.Version, version.UserId)
+		return nil
+	}
+	return err
+}
+
+func (r *UserVersionRepository) GetUserVersion(ctx context.Context, userId string, version int64) (*domain.UserVersion, error) {
+	var entity *UserVersionEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": versionId(userId, version)}).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrUserVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return versionToDomain(entity), nil
+}
+
+// GetUserVersionAt returns the latest version that was already valid at asOf
+func (r *UserVersionRepository) GetUserVersionAt(ctx context.Context, userId string, asOf time.Time) (*domain.UserVersion, error) {
+	filter := bson.M{"user_id": userId, "valid_from": bson.M{"$lte": asOf}}
+	opts := options.FindOne().SetSort(bson.D{{Key: "valid_from", Value: -1}, {Key: "version", Value: -1}})
+
+	var entity *UserVersionEntity
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrUserVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return versionToDomain(entity), nil
+}
+
+func (r *UserVersionRepository) ListUserVersions(ctx context.Context, request *domain.ListUserVersionsQueryRequest) (*domain.ListUserVersionsQueryResponse, error) {
+	if request.PageSize == 0 {
+		request.PageSize = 10
+	}
+
+	filter := bson.M{"user_id": request.UserId}
+
+	// Get the total count of documents matching the filter
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %v", err)
+	}
+
+	// Pagination options, latest versions first
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "version", Value: -1}})
+	findOptions.SetSkip(int64(request.Page * request.PageSize))
+	findOptions.SetLimit(int64(request.PageSize))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	var entities []*UserVersionEntity
+	if err = cursor.All(ctx, &entities); err != nil {
+		return nil, fmt.Errorf("failed to decode user versions: %w", err)
+	}
+
+	results := make([]*domain.UserVersion, len(entities))
+	for i, e := range entities {
+		results[i] = versionToDomain(e)
+	}
+
+	return &domain.ListUserVersionsQueryResponse{
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalCount: uint32(totalCount),
+		Results:    results,
+	}, nil
+}
+
+// versionId makes a version unique per user
+func versionId(userId string, version int64) string {
+	return fmt.Sprintf("%s:%d", userId, version)
+}
+
+func versionToDomain(e *UserVersionEntity) *domain.UserVersion {
+	return &domain.UserVersion{
+		UserId:        e.UserId,
+		Version:       e.Version,
+		User:          userToDomain(e.User),
+		Deleted:       e.Deleted,
+		ValidFrom:     e.ValidFrom,
+		ModifiedBy:    e.ModifiedBy,
+		CorrelationId: e.CorrelationId,
+	}
+}
+
+func toVersionEntity(version *domain.UserVersion) *UserVersionEntity {
+	entity := &UserVersionEntity{
+		ID:            versionId(version.UserId, version.Version),
+		UserId:        version.UserId,
+		Version:       version.Version,
+		Deleted:       version.Deleted,
+		ValidFrom:     version.ValidFrom,
+		ModifiedBy:    version.ModifiedBy,
+		CorrelationId: version.CorrelationId,
+	}
+	if version.User != nil {
+		entity.User = toEntity(version.User)
+	}
+	return entity
+}
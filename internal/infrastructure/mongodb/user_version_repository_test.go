@@ -0,0 +1,181 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type UserVersionRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.UserVersionRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *UserVersionRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_versions")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewUserVersionRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *UserVersionRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *UserVersionRepositoryTestSuite) SetupTest() {
+	// Clean up the collection before each test
+	suite.collection.Drop(suite.ctx)
+}
+
+// seedVersions records a user created two days ago, renamed yesterday and deleted an hour ago
+func (suite *UserVersionRepositoryTestSuite) seedVersions(userId string, now time.Time) {
+	created := now.Add(-48 * time.Hour)
+	renamed := now.Add(-24 * time.Hour)
+	versions := []*domain.UserVersion{
+		{UserId: userId, Version: 1, User: &domain.User{ID: userId, FirstName: "Federico", Version: 1, CreatedAt: created, UpdatedAt: created}, ValidFrom: created, ModifiedBy: userId},
+		{UserId: userId, Version: 2, User: &domain.User{ID: userId, FirstName: "Fede", Version: 2, CreatedAt: created, UpdatedAt: renamed}, ValidFrom: renamed, ModifiedBy: "admin-1"},
+		{UserId: userId, Version: 3, Deleted: true, ValidFrom: now.Add(-time.Hour), ModifiedBy: "admin-1"},
+	}
+	for _, v := range versions {
+		err := suite.repo.AppendUserVersion(suite.ctx, v)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *UserVersionRepositoryTestSuite) TestUserVersionRepository_AppendUserVersion() {
+	userId := uuid.NewString()
+	version := &domain.UserVersion{UserId: userId, Version: 1, User: &domain.User{ID: userId, FirstName: "Federico"}, ValidFrom: time.Now().UTC()}
+
+	err := suite.repo.AppendUserVersion(suite.ctx, version)
+	suite.Require().NoError(err)
+
+	// appending the same version again is a no-op
+	err = suite.repo.AppendUserVersion(suite.ctx, version)
+	suite.Require().NoError(err)
+
+	count, err := suite.collection.CountDocuments(suite.ctx, map[string]string{"user_id": userId})
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *UserVersionRepositoryTestSuite) TestUserVersionRepository_GetUserVersion() {
+	userId := uuid.NewString()
+	suite.seedVersions(userId, time.Now().UTC().Round(time.Millisecond))
+
+	version, err := suite.repo.GetUserVersion(suite.ctx, userId, 2)
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), version.Version)
+	suite.Equal("Fede", version.User.FirstName)
+	suite.Equal("admin-1", version.ModifiedBy)
+
+	_, err = suite.repo.GetUserVersion(suite.ctx, userId, 4)
+	suite.Equal(domain.ErrUserVersionNotFound, err)
+}
+
+func (suite *UserVersionRepositoryTestSuite) TestUserVersionRepository_GetUserVersionAt() {
+	userId := uuid.NewString()
+	// round due to bson spec https://bsonspec.org/spec.html
+	now := time.Now().UTC().Round(time.Millisecond)
+	suite.seedVersions(userId, now)
+
+	tests := []struct {
+		name          string
+		asOf          time.Time
+		wantedVersion int64
+		wantedErr     error
+	}{
+		{
+			name:      "before creation",
+			asOf:      now.Add(-72 * time.Hour),
+			wantedErr: domain.ErrUserVersionNotFound,
+		},
+		{
+			name:          "after creation",
+			asOf:          now.Add(-36 * time.Hour),
+			wantedVersion: 1,
+		},
+		{
+			name:          "exactly at rename",
+			asOf:          now.Add(-24 * time.Hour),
+			wantedVersion: 2,
+		},
+		{
+			name:          "after deletion",
+			asOf:          now,
+			wantedVersion: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			version, err := suite.repo.GetUserVersionAt(suite.ctx, userId, tt.asOf)
+			if tt.wantedErr != nil {
+				suite.Equal(tt.wantedErr, err)
+			} else {
+				suite.Require().NoError(err)
+				suite.Equal(tt.wantedVersion, version.Version)
+			}
+		})
+	}
+}
+
+func (suite *UserVersionRepositoryTestSuite) TestUserVersionRepository_ListUserVersions() {
+	userId := uuid.NewString()
+	suite.seedVersions(userId, time.Now().UTC().Round(time.Millisecond))
+	suite.seedVersions(uuid.NewString(), time.Now().UTC().Round(time.Millisecond))
+
+	res, err := suite.repo.ListUserVersions(suite.ctx, &domain.ListUserVersionsQueryRequest{UserId: userId, PageSize: 2})
+	suite.Require().NoError(err)
+	suite.Equal(uint32(3), res.TotalCount)
+	suite.Require().Len(res.Results, 2)
+	suite.Equal(int64(3), res.Results[0].Version)
+	suite.True(res.Results[0].Deleted)
+	suite.Nil(res.Results[0].User)
+	suite.Equal(int64(2), res.Results[1].Version)
+
+	res, err = suite.repo.ListUserVersions(suite.ctx, &domain.ListUserVersionsQueryRequest{UserId: userId, Page: 1, PageSize: 2})
+	suite.Require().NoError(err)
+	suite.Require().Len(res.Results, 1)
+	suite.Equal(int64(1), res.Results[0].Version)
+}
+
+func TestUserVersionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UserVersionRepositoryTestSuite))
+}
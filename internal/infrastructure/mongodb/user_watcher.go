@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/flapenna/go-ddd-crud/internal/domain/user"
 	"github.com/google/uuid"
@@ -9,54 +10,309 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"math/rand"
+	"sync"
+	"time"
 )
 
+// HistoryLostPolicy is applied when the change stream can no longer be resumed from its
+// checkpoint, because the oplog does not hold the events following it anymore
+type HistoryLostPolicy string
+
+const (
+	// HistoryLostFail stops the watcher
+	HistoryLostFail HistoryLostPolicy = "fail"
+	// HistoryLostResync emits the current state of every user, then watches from now
+	HistoryLostResync HistoryLostPolicy = "resync"
+)
+
+const (
+	changeStreamFatalErrorCode  = 280
+	changeStreamHistoryLostCode = 286
+)
+
+// RetryPolicy configures how the watcher reopens a failed change stream
+type RetryPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts is the number of consecutive failures before giving up, 0 retries forever
+	MaxAttempts int
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	MaxAttempts:    10,
+}
+
+// userEventNamespace is the namespace of the name-based UUIDs used as event ids
+var userEventNamespace = uuid.MustParse("5b0e7c1e-8f3a-4d6b-9c2e-1a7f4e9d3b60")
+
+var (
+	errEventNotPublished = errors.New("user event not published")
+	errStreamInvalidated = errors.New("change stream invalidated")
+)
+
+// ResumeTokenStore persists the position of a change stream, to resume it after a restart
+type ResumeTokenStore interface {
+	LoadResumeToken(ctx context.Context, streamName string) (bson.Raw, error)
+	SaveResumeToken(ctx context.Context, streamName string, token bson.Raw) error
+}
+
+// UsersChangeStreamWatcher emits the changes of the users collection. With a checkpoint
+// store, it emits up to MaxInFlight events ahead of their acknowledgements, one by default,
+// and saves the resume token of an event only once the event has been acknowledged as
+// published: after a restart or a failed publication, the stream is resumed from the last
+// published event. The events must be acknowledged in the order they were emitted. Once an
+// event is nacked, the later acknowledgements no longer move the checkpoint, until the stream
+// has been reopened from the token before the failed event.
+//
+// The watcher supervises itself: a failed stream is reopened from the last resume token,
+// with an exponential backoff, until RetryPolicy.MaxAttempts consecutive failures.
+//
+// Once the channel returned by WatchUsers is closed, WatchUsers can be called again, e.g. by an
+// instance elected leader again, to resume from the last checkpoint.
 type UsersChangeStreamWatcher struct {
-	collection *mongo.Collection
-	userEvents chan *domain.UserEvent
+	collection        *mongo.Collection
+	deletions         *mongo.Collection
+	checkpoints       ResumeTokenStore
+	streamName        string
+	historyLostPolicy HistoryLostPolicy
+	retryPolicy       RetryPolicy
+	maxInFlight       int
+	// window is the window of the current WatchUsers call
+	window *eventWindow
+	failed chan struct{}
+	resync bool
+
+	tokenMu     sync.Mutex
+	resumeToken bson.Raw
+	// pendingTokens are the resume tokens of the events waiting for their acknowledgement
+	pendingTokens []bson.Raw
+	// pinned is set once an event is nacked, to keep the checkpoint before it
+	pinned bool
+	// results receives the outcomes of the events of the current WatchUsers call
+	results chan error
+
+	mu     sync.RWMutex
+	status domain.WatcherStatus
 }
 
 func NewChangeStreamWatcher(collection *mongo.Collection) *UsersChangeStreamWatcher {
 	return &UsersChangeStreamWatcher{
-		collection: collection,
-		userEvents: make(chan *domain.UserEvent),
+		collection:        collection,
+		streamName:        collection.Name(),
+		historyLostPolicy: HistoryLostFail,
+		retryPolicy:       DefaultRetryPolicy,
+		maxInFlight:       1,
+		failed:            make(chan struct{}),
+		status:            domain.WatcherStatus{State: domain.WatcherStateStarting},
 	}
 }
 
+// NewCheckpointedChangeStreamWatcher creates a watcher resuming from the resume token saved in checkpoints
+func NewCheckpointedChangeStreamWatcher(collection *mongo.Collection, checkpoints ResumeTokenStore,
+	historyLostPolicy HistoryLostPolicy, retryPolicy RetryPolicy) *UsersChangeStreamWatcher {
+	return &UsersChangeStreamWatcher{
+		collection:        collection,
+		checkpoints:       checkpoints,
+		streamName:        collection.Name(),
+		historyLostPolicy: historyLostPolicy,
+		retryPolicy:       retryPolicy,
+		maxInFlight:       1,
+		failed:            make(chan struct{}),
+		status:            domain.WatcherStatus{State: domain.WatcherStateStarting},
+	}
+}
+
+// WithStreamName saves the checkpoints under name rather than the collection name, for another
+// watcher of the same collection to keep its own position
+func (w *UsersChangeStreamWatcher) WithStreamName(name string) *UsersChangeStreamWatcher {
+	w.streamName = name
+	return w
+}
+
+// WithMaxInFlight lets up to maxInFlight events wait for their acknowledgement, for a pipeline
+// to process them in parallel. It has no effect without a checkpoint store.
+func (w *UsersChangeStreamWatcher) WithMaxInFlight(maxInFlight int) *UsersChangeStreamWatcher {
+	w.maxInFlight = maxInFlight
+	return w
+}
+
+// WithDeletions attributes the delete events to the actors recorded in collection by
+// UserRepository.DeleteUserById. Without it the delete events are not attributed.
+func (w *UsersChangeStreamWatcher) WithDeletions(collection *mongo.Collection) *UsersChangeStreamWatcher {
+	w.deletions = collection
+	return w
+}
+
 func (w *UsersChangeStreamWatcher) WatchUsers(ctx context.Context) <-chan *domain.UserEvent {
-	go func() {
-		defer close(w.userEvents)
-		changeStream, err := w.openChangeStream(ctx)
-		if err != nil {
-			log.Errorf("Failed to open change stream: %v", err)
-			return
-		}
-		defer changeStream.Close(ctx)
+	// The events not acknowledged by a previous call are emitted again from the checkpoint
+	userEvents := make(chan *domain.UserEvent)
+	var results chan error
+	if w.checkpoints != nil {
+		results = make(chan error)
+	}
+	w.tokenMu.Lock()
+	w.results = results
+	w.pendingTokens = nil
+	w.pinned = false
+	w.tokenMu.Unlock()
+	w.window = newEventWindow(userEvents, results, w.maxInFlight)
+	w.setState(domain.WatcherStateStarting)
 
-		log.Info("Started watching for user changes.")
+	go func() {
+		defer close(userEvents)
 
+		tokenLoaded := false
 		for {
+			var err error
+			if !tokenLoaded {
+				err = w.loadResumeToken(ctx)
+				tokenLoaded = err == nil
+			}
+			if err == nil {
+				err = w.watch(ctx)
+			}
+
+			switch {
+			case ctx.Err() != nil:
+				log.Info("Context canceled, stopping watch.")
+				w.setState(domain.WatcherStateStopped)
+				return
+			case errors.Is(err, errStreamInvalidated):
+				log.Info("Change stream invalidated, reopening it.")
+				continue
+			case isChangeStreamHistoryLost(err) && w.historyLostPolicy == HistoryLostResync:
+				log.Warnf("Change stream history lost, resyncing all users: %v", err)
+				w.resync = true
+				continue
+			case isChangeStreamHistoryLost(err):
+				log.Errorf("Change stream history lost, stopping watch: %v", err)
+				w.fail(err)
+				return
+			}
+
+			attempts := w.recordFailure(err)
+			if w.retryPolicy.MaxAttempts > 0 && attempts > w.retryPolicy.MaxAttempts {
+				log.Errorf("Error watching user changes, giving up after %d attempts: %v", attempts, err)
+				w.fail(err)
+				return
+			}
+
+			backoff := w.backoff(attempts)
+			log.Warnf("Error watching user changes, resuming in %s (attempt %d): %v", backoff, attempts, err)
 			select {
+			case <-time.After(backoff):
 			case <-ctx.Done():
-				log.Info("Context canceled, stopping watch.")
+				w.setState(domain.WatcherStateStopped)
 				return
-			default:
-				err = w.processNextChange(ctx, changeStream)
-				if err != nil {
-					log.Errorf("Error processing next change: %v", err)
-					return
-				}
 			}
 		}
 	}()
 
-	return w.userEvents
+	return userEvents
+}
+
+// Failed is closed when the watcher gives up watching the changes
+func (w *UsersChangeStreamWatcher) Failed() <-chan struct{} {
+	return w.failed
+}
+
+func (w *UsersChangeStreamWatcher) WatcherStatus() domain.WatcherStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
+
+func (w *UsersChangeStreamWatcher) setState(state domain.WatcherState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.State = state
+}
+
+// recordHealthy resets the failures once a change has been handled
+func (w *UsersChangeStreamWatcher) recordHealthy() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = domain.WatcherStatus{State: domain.WatcherStateRunning}
+}
+
+func (w *UsersChangeStreamWatcher) recordFailure(err error) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.State = domain.WatcherStateRetrying
+	w.status.Attempts++
+	w.status.LastError = err.Error()
+	return w.status.Attempts
+}
+
+func (w *UsersChangeStreamWatcher) fail(err error) {
+	w.mu.Lock()
+	w.status.State = domain.WatcherStateFailed
+	w.status.LastError = err.Error()
+	w.mu.Unlock()
+	close(w.failed)
+}
+
+func (w *UsersChangeStreamWatcher) backoff(attempts int) time.Duration {
+	return w.retryPolicy.backoff(attempts)
+}
+
+// backoff doubles the delay at every attempt, with a jitter of up to half of the delay
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// watch emits the changes until the stream fails or has to be reopened
+func (w *UsersChangeStreamWatcher) watch(ctx context.Context) error {
+	changeStream, err := w.openChangeStream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open change stream: %w", err)
+	}
+	defer changeStream.Close(ctx)
+
+	if w.resync {
+		if err := w.resyncUsers(ctx, changeStream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Started watching for user changes.")
+	w.setState(domain.WatcherStateRunning)
+
+	for {
+		if err := w.processNextChange(ctx, changeStream); err != nil {
+			// The stream is reopened from the last checkpoint, once the emitted events are acknowledged
+			if drainErr := w.window.drain(ctx); drainErr != nil && !errors.Is(err, errEventNotPublished) {
+				log.Warnf("User event not published before reopening the change stream: %v", drainErr)
+			}
+			return err
+		}
+		w.recordHealthy()
+	}
 }
 
 func (w *UsersChangeStreamWatcher) openChangeStream(ctx context.Context) (*mongo.ChangeStream, error) {
-	return w.collection.Watch(ctx, mongo.Pipeline{},
-		options.ChangeStream().SetFullDocument(options.UpdateLookup).
-			SetFullDocumentBeforeChange(options.WhenAvailable))
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	// Every emitted event has its outcome, the nacked events are emitted again from the checkpoint
+	w.unpinCheckpoint()
+	// StartAfter, unlike ResumeAfter, also accepts the token of an invalidate event
+	if resumeToken := w.lastResumeToken(); resumeToken != nil && !w.resync {
+		opts.SetStartAfter(resumeToken)
+	}
+	return w.collection.Watch(ctx, mongo.Pipeline{}, opts)
 }
 
 func (w *UsersChangeStreamWatcher) processNextChange(ctx context.Context, changeStream *mongo.ChangeStream) error {
@@ -69,25 +325,220 @@ func (w *UsersChangeStreamWatcher) processNextChange(ctx context.Context, change
 
 		log.Debugf("Change doc: %v", changeDoc)
 
+		operationType := changeDoc.Lookup("operationType").StringValue()
+		switch operationType {
+		case "insert", "update", "delete":
+		case "invalidate":
+			// The collection was dropped or renamed, every previous event has been handled
+			if err := w.drainWindow(ctx); err != nil {
+				return err
+			}
+			if err := w.saveResumeToken(ctx, changeStream.ResumeToken()); err != nil {
+				return err
+			}
+			return errStreamInvalidated
+		default:
+			log.Debugf("Skipping %s event.", operationType)
+			return nil
+		}
+
 		afterChange, beforeChange := w.decodeChangeDocuments(changeDoc)
 		userID := w.determineUserID(beforeChange, afterChange)
-		operationType := changeDoc.Lookup("operationType").StringValue()
 
 		userEvent := &domain.UserEvent{
-			Id:            uuid.New().String(),
+			Id:            eventIdFromResumeToken(changeDoc.Lookup("_id")),
 			UserId:        userID,
 			BeforeChange:  userToDomain(beforeChange),
 			AfterChange:   userToDomain(afterChange),
 			OperationType: operationToEnum(operationType),
+			Sequence:      eventSequence(beforeChange, afterChange),
 		}
-
-		select {
-		case w.userEvents <- userEvent:
-		case <-ctx.Done():
-			return ctx.Err()
+		userEvent.ClusterTime, userEvent.OccurredAt = changeTime(changeDoc)
+		userEvent.ChangedFields = changedFields(changeDoc, userEvent)
+		if operationType == "delete" {
+			if err := w.attributeDeletion(ctx, changeDoc, userEvent); err != nil {
+				return err
+			}
+		} else if attribution := w.determineAttribution(beforeChange, afterChange); attribution != nil {
+			userEvent.ModifiedBy = attribution.LastModifiedBy
+			userEvent.CorrelationId = attribution.CorrelationId
+			userEvent.TraceParent = attribution.TraceParent
 		}
+
+		return w.emit(ctx, userEvent, changeStream.ResumeToken())
 	} else if err := changeStream.Err(); err != nil {
-		return fmt.Errorf("change stream error: %v", err)
+		return fmt.Errorf("change stream error: %w", err)
+	}
+	return nil
+}
+
+// resyncUsers emits the current state of every user as an update, then checkpoints the
+// position the stream was opened at, since every later change will be read from the stream
+func (w *UsersChangeStreamWatcher) resyncUsers(ctx context.Context, startToken bson.Raw) error {
+	cursor, err := w.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("failed to resync users: %w", err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	for cursor.Next(ctx) {
+		var usr UserEntity
+		if err := cursor.Decode(&usr); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+		userEvent := &domain.UserEvent{
+			Id:            uuid.NewSHA1(userEventNamespace, []byte(fmt.Sprintf("resync:%s:%d", usr.ID, usr.Version))).String(),
+			UserId:        usr.ID,
+			AfterChange:   userToDomain(&usr),
+			OperationType: domain.OPERATION_UPDATE,
+			ModifiedBy:    usr.LastModifiedBy,
+			CorrelationId: usr.CorrelationId,
+			TraceParent:   usr.TraceParent,
+			Sequence:      usr.Version,
+			OccurredAt:    usr.UpdatedAt,
+		}
+		if err := w.emit(ctx, userEvent, nil); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to resync users: %w", err)
+	}
+
+	if err := w.drainWindow(ctx); err != nil {
+		return err
+	}
+	if err := w.saveResumeToken(ctx, startToken); err != nil {
+		return err
+	}
+	w.resync = false
+	log.Info("Resynced all users.")
+	return nil
+}
+
+// emit sends the event through the window. token is checkpointed once the event is acknowledged.
+func (w *UsersChangeStreamWatcher) emit(ctx context.Context, userEvent *domain.UserEvent, token bson.Raw) error {
+	if w.checkpoints != nil {
+		w.tokenMu.Lock()
+		w.pendingTokens = append(w.pendingTokens, token)
+		w.tokenMu.Unlock()
+	}
+	if err := w.window.emit(ctx, userEvent); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errEventNotPublished, err)
+	}
+	return nil
+}
+
+// drainWindow waits for the emitted events to be acknowledged, before checkpointing past them
+func (w *UsersChangeStreamWatcher) drainWindow(ctx context.Context) error {
+	if err := w.window.drain(ctx); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errEventNotPublished, err)
+	}
+	return nil
+}
+
+// AckUserEvent checkpoints the resume token of the published event. The event is
+// published even if saving the checkpoint fails: it is only emitted again after a restart.
+// After a nack, the tokens of the following events are not checkpointed, for the nacked
+// event to be emitted again.
+func (w *UsersChangeStreamWatcher) AckUserEvent(ctx context.Context, event *domain.UserEvent) error {
+	if w.checkpoints == nil {
+		return nil
+	}
+	var err error
+	if token, pinned := w.popPendingToken(); !pinned {
+		err = w.saveResumeToken(ctx, token)
+	}
+	w.report(ctx, nil)
+	return err
+}
+
+// NackUserEvent makes the watcher resume from the last checkpoint, to emit the event again.
+// The checkpoint is pinned before the event until the stream is reopened.
+func (w *UsersChangeStreamWatcher) NackUserEvent(ctx context.Context, event *domain.UserEvent, err error) {
+	if w.checkpoints == nil {
+		return
+	}
+	w.tokenMu.Lock()
+	w.pinned = true
+	w.tokenMu.Unlock()
+	w.popPendingToken()
+	w.report(ctx, err)
+}
+
+// popPendingToken is the token of the oldest event waiting for its acknowledgement, and
+// whether the checkpoint is pinned by a previous nack
+func (w *UsersChangeStreamWatcher) popPendingToken() (bson.Raw, bool) {
+	w.tokenMu.Lock()
+	defer w.tokenMu.Unlock()
+	if len(w.pendingTokens) == 0 {
+		return nil, w.pinned
+	}
+	token := w.pendingTokens[0]
+	w.pendingTokens = w.pendingTokens[1:]
+	return token, w.pinned
+}
+
+// unpinCheckpoint lets the acknowledgements move the checkpoint again, once the stream is
+// reopened from it
+func (w *UsersChangeStreamWatcher) unpinCheckpoint() {
+	w.tokenMu.Lock()
+	defer w.tokenMu.Unlock()
+	w.pinned = false
+}
+
+func (w *UsersChangeStreamWatcher) report(ctx context.Context, err error) {
+	w.tokenMu.Lock()
+	results := w.results
+	w.tokenMu.Unlock()
+	select {
+	case results <- err:
+	case <-ctx.Done():
+	}
+}
+
+func (w *UsersChangeStreamWatcher) loadResumeToken(ctx context.Context) error {
+	if w.checkpoints == nil {
+		return nil
+	}
+	token, err := w.checkpoints.LoadResumeToken(ctx, w.streamName)
+	if err != nil {
+		return err
+	}
+	w.tokenMu.Lock()
+	w.resumeToken = token
+	w.tokenMu.Unlock()
+	return nil
+}
+
+func (w *UsersChangeStreamWatcher) lastResumeToken() bson.Raw {
+	w.tokenMu.Lock()
+	defer w.tokenMu.Unlock()
+	return w.resumeToken
+}
+
+func (w *UsersChangeStreamWatcher) saveResumeToken(ctx context.Context, token bson.Raw) error {
+	if token == nil {
+		return nil
+	}
+	w.tokenMu.Lock()
+	w.resumeToken = token
+	w.tokenMu.Unlock()
+	if w.checkpoints == nil {
+		return nil
+	}
+	if err := w.checkpoints.SaveResumeToken(ctx, w.streamName, token); err != nil {
+		return fmt.Errorf("failed to save change stream resume token: %w", err)
 	}
 	return nil
 }
@@ -134,6 +585,117 @@ func (w *UsersChangeStreamWatcher) determineUserID(beforeChange, afterChange *Us
 	return ""
 }
 
+// eventIdFromResumeToken derives the event id from the resume token of the change, so that
+// a change emitted again after a restart keeps its id
+func eventIdFromResumeToken(resumeToken bson.RawValue) string {
+	return uuid.NewSHA1(userEventNamespace, resumeToken.Value).String()
+}
+
+// eventSequence returns the version resulting from the change: the version of the post-image,
+// or the one following the pre-image for deletions. It is 0 when neither image is available.
+func eventSequence(beforeChange, afterChange *UserEntity) int64 {
+	if afterChange != nil {
+		return afterChange.Version
+	}
+	if beforeChange != nil {
+		return beforeChange.Version + 1
+	}
+	return 0
+}
+
+// changeTime returns the cluster time of the change and its wall clock time, available
+// since MongoDB 6.0, falling back to the seconds of the cluster time
+func changeTime(changeDoc bson.Raw) (uint64, time.Time) {
+	t, i, ok := changeDoc.Lookup("clusterTime").TimestampOK()
+	if !ok {
+		return 0, time.Time{}
+	}
+	clusterTime := uint64(t)<<32 | uint64(i)
+	if wallTime, ok := changeDoc.Lookup("wallTime").TimeOK(); ok {
+		return clusterTime, wallTime.UTC()
+	}
+	return clusterTime, time.Unix(int64(t), 0).UTC()
+}
+
+// determineAttribution returns the document carrying the actor of an insert or an update: the
+// post-image, or the pre-image when the document was deleted since
+func (w *UsersChangeStreamWatcher) determineAttribution(beforeChange, afterChange *UserEntity) *UserEntity {
+	if afterChange != nil {
+		return afterChange
+	}
+	return beforeChange
+}
+
+// attributeDeletion sets the actor recorded with the deletion of the user on the delete event.
+// The pre-image only holds the last actor who modified the user, so it is never used.
+func (w *UsersChangeStreamWatcher) attributeDeletion(ctx context.Context, changeDoc bson.Raw, userEvent *domain.UserEvent) error {
+	if w.deletions == nil {
+		return nil
+	}
+	id, ok := changeDoc.Lookup("documentKey", "_id").StringValueOK()
+	if !ok {
+		return nil
+	}
+
+	var deletion UserDeletionEntity
+	err := w.deletions.FindOne(ctx, bson.M{"_id": id}).Decode(&deletion)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		log.Warnf("No deletion recorded for user %s, the delete event is not attributed.", id)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the deletion of user %s: %w", id, err)
+	}
+	userEvent.ModifiedBy = deletion.DeletedBy
+	userEvent.CorrelationId = deletion.CorrelationId
+	userEvent.TraceParent = deletion.TraceParent
+	return nil
+}
+
+// changedFields diffs the pre and post images. Updates without a pre-image fall back
+// on the fields listed by the update description.
+func changedFields(changeDoc bson.Raw, event *domain.UserEvent) []string {
+	if event.OperationType != domain.OPERATION_UPDATE || event.BeforeChange != nil {
+		return domain.ChangedUserFields(event.BeforeChange, event.AfterChange)
+	}
+
+	changed := make([]string, 0)
+	if updatedFields, ok := changeDoc.Lookup("updateDescription", "updatedFields").DocumentOK(); ok {
+		if elements, err := updatedFields.Elements(); err == nil {
+			for _, element := range elements {
+				changed = appendPublishedField(changed, element.Key())
+			}
+		}
+	}
+	if removedFields, ok := changeDoc.Lookup("updateDescription", "removedFields").ArrayOK(); ok {
+		if values, err := removedFields.Values(); err == nil {
+			for _, value := range values {
+				if field, ok := value.StringValueOK(); ok {
+					changed = appendPublishedField(changed, field)
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// appendPublishedField keeps the fields of the user entity that are published, their bson
+// names matching the User protobuf message
+func appendPublishedField(fields []string, field string) []string {
+	switch field {
+	case domain.UserFieldFirstName, domain.UserFieldLastName, domain.UserFieldEmail, domain.UserFieldCountry,
+		domain.UserFieldNickname, domain.UserFieldCreatedAt, domain.UserFieldUpdatedAt:
+		return append(fields, field)
+	}
+	return fields
+}
+
+func isChangeStreamHistoryLost(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) &&
+		(serverErr.HasErrorCode(changeStreamHistoryLostCode) || serverErr.HasErrorCode(changeStreamFatalErrorCode))
+}
+
 func operationToEnum(operationType string) domain.OperationType {
 	switch operationType {
 	case "insert":
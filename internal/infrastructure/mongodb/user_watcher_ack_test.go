@@ -0,0 +1,65 @@
+//go:build unit
+
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// resumeTokenStoreStub records the saved resume tokens
+type resumeTokenStoreStub struct {
+	saved []bson.Raw
+}
+
+func (s *resumeTokenStoreStub) LoadResumeToken(context.Context, string) (bson.Raw, error) {
+	return nil, nil
+}
+
+func (s *resumeTokenStoreStub) SaveResumeToken(_ context.Context, _ string, token bson.Raw) error {
+	s.saved = append(s.saved, token)
+	return nil
+}
+
+func resumeToken(t *testing.T, data string) bson.Raw {
+	token, err := bson.Marshal(bson.M{"_data": data})
+	assert.NoError(t, err)
+	return token
+}
+
+func TestUsersChangeStreamWatcher_AckAfterNack(t *testing.T) {
+	ctx := context.TODO()
+	checkpoint := resumeToken(t, "0")
+	tokenN := resumeToken(t, "1")
+	tokenN1 := resumeToken(t, "2")
+	store := &resumeTokenStoreStub{}
+	watcher := &UsersChangeStreamWatcher{
+		checkpoints:   store,
+		streamName:    "users",
+		resumeToken:   checkpoint,
+		pendingTokens: []bson.Raw{tokenN, tokenN1},
+		results:       make(chan error, 3),
+	}
+
+	// Event N fails, event N+1 of another user is published
+	watcher.NackUserEvent(ctx, &domain.UserEvent{Id: "event-n", UserId: "user-1"}, errors.New("producer error"))
+	assert.NoError(t, watcher.AckUserEvent(ctx, &domain.UserEvent{Id: "event-n1", UserId: "user-2"}))
+
+	assert.Empty(t, store.saved)
+	assert.Equal(t, checkpoint, watcher.lastResumeToken())
+
+	// Once the stream is reopened from the checkpoint, event N is emitted again and moves it
+	watcher.unpinCheckpoint()
+	watcher.pendingTokens = []bson.Raw{tokenN}
+	assert.NoError(t, watcher.AckUserEvent(ctx, &domain.UserEvent{Id: "event-n", UserId: "user-1"}))
+
+	assert.Equal(t, []bson.Raw{tokenN}, store.saved)
+	assert.Equal(t, tokenN, watcher.lastResumeToken())
+	assert.Equal(t, errors.New("producer error"), <-watcher.results)
+	assert.NoError(t, <-watcher.results)
+}
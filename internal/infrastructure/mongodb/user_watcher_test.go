@@ -4,6 +4,7 @@ package mongodb_test
 
 import (
 	"context"
+	"errors"
 	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
 	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
 	"github.com/google/uuid"
@@ -25,6 +26,7 @@ type UserWatcherTestSuite struct {
 	mongoC     testcontainers.Container
 	client     *mongo.Client
 	collection *mongo.Collection
+	deletions  *mongo.Collection
 	watcher    *mongodb.UsersChangeStreamWatcher
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -63,7 +65,8 @@ func (suite *UserWatcherTestSuite) SetupSuite() {
 	suite.mongoC = mongoC
 	suite.client = client
 	suite.collection = collection
-	suite.watcher = mongodb.NewChangeStreamWatcher(collection)
+	suite.deletions = mongoDb.Collection("test_deletions")
+	suite.watcher = mongodb.NewChangeStreamWatcher(collection).WithDeletions(suite.deletions)
 	suite.ctx, suite.cancel = context.WithTimeout(ctx, 60*time.Second)
 }
 
@@ -92,6 +95,8 @@ func (suite *UserWatcherTestSuite) TestWatchUsers() {
 		HashedPassword: "password",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
+		LastModifiedBy: "admin-1",
+		CorrelationId:  "request-1",
 	}
 	_, err := suite.collection.InsertOne(suite.ctx, user)
 	suite.Require().NoError(err)
@@ -108,6 +113,10 @@ func (suite *UserWatcherTestSuite) TestWatchUsers() {
 		suite.Equal(user.Country, event.AfterChange.Country)
 		suite.Equal(user.Nickname, event.AfterChange.Nickname)
 		suite.Equal(domain.OPERATION_CREATE, event.OperationType)
+		suite.Equal("admin-1", event.ModifiedBy)
+		suite.Equal("request-1", event.CorrelationId)
+		suite.NotZero(event.ClusterTime)
+		suite.WithinDuration(time.Now(), event.OccurredAt, 15*time.Second)
 	case <-time.After(15 * time.Second):
 		suite.Fail("Timed out waiting for change event")
 	}
@@ -133,25 +142,136 @@ func (suite *UserWatcherTestSuite) TestWatchUsers() {
 		suite.Equal(user.Country, event.AfterChange.Country)
 		suite.Equal(user.Nickname, event.AfterChange.Nickname)
 		suite.Equal(domain.OPERATION_UPDATE, event.OperationType)
+		suite.Equal([]string{domain.UserFieldFirstName}, event.ChangedFields)
 	case <-time.After(15 * time.Second):
 		suite.Fail("Timed out waiting for change event")
 	}
 
-	// Delete the user
-	_, err = suite.collection.DeleteOne(suite.ctx, bson.M{"_id": user.ID})
+	// Delete the user through the repository, which records the deleting actor in the same transaction
+	ctx := domain.ContextWithActor(suite.ctx, &domain.Actor{ID: "admin-2"})
+	ctx = domain.ContextWithCorrelationId(ctx, "request-2")
+	err = mongodb.NewUserRepository(suite.collection).WithDeletions(suite.deletions).DeleteUserById(ctx, user.ID)
 	suite.Require().NoError(err)
 
-	// Wait for the event to be captured
+	// Wait for the event to be captured, attributed to the deleting actor rather than the last modifier
 	select {
 	case event := <-events:
 		suite.Require().NotNil(event)
 		suite.Nil(event.AfterChange)
 		suite.Equal(domain.OPERATION_DELETE, event.OperationType)
+		suite.Equal("admin-2", event.ModifiedBy)
+		suite.Equal("request-2", event.CorrelationId)
 	case <-time.After(15 * time.Second):
 		suite.Fail("Timed out waiting for change event")
 	}
 }
 
+func (suite *UserWatcherTestSuite) TestCheckpointedWatchUsers() {
+	mongoDb := suite.client.Database("testdb")
+	collOpts := options.CreateCollection().
+		SetChangeStreamPreAndPostImages(bson.M{"enabled": true})
+	err := mongoDb.CreateCollection(suite.ctx, "test_checkpointed", collOpts)
+	suite.Require().NoError(err)
+	collection := mongoDb.Collection("test_checkpointed")
+	checkpoints := mongodb.NewResumeTokenRepository(mongoDb.Collection("test_checkpoints"))
+
+	next := func(events <-chan *domain.UserEvent) *domain.UserEvent {
+		select {
+		case event := <-events:
+			suite.Require().NotNil(event)
+			return event
+		case <-time.After(15 * time.Second):
+			suite.FailNow("Timed out waiting for change event")
+			return nil
+		}
+	}
+	insertUser := func(firstName string) string {
+		id := uuid.NewString()
+		_, err := collection.InsertOne(suite.ctx, &mongodb.UserEntity{ID: id, FirstName: firstName})
+		suite.Require().NoError(err)
+		return id
+	}
+
+	// Without a checkpoint, the watcher starts from now
+	ctx, cancel := context.WithCancel(suite.ctx)
+	watcher := mongodb.NewCheckpointedChangeStreamWatcher(collection, checkpoints, mongodb.HistoryLostFail, mongodb.DefaultRetryPolicy)
+	events := watcher.WatchUsers(ctx)
+	time.Sleep(5 * time.Second)
+
+	firstId := insertUser("First")
+	event := next(events)
+	suite.Equal(firstId, event.UserId)
+	suite.Require().NoError(watcher.AckUserEvent(ctx, event))
+
+	token, err := checkpoints.LoadResumeToken(suite.ctx, "test_checkpointed")
+	suite.Require().NoError(err)
+	suite.NotNil(token)
+
+	// An event that failed to be published is emitted again
+	secondId := insertUser("Second")
+	event = next(events)
+	suite.Equal(secondId, event.UserId)
+	watcher.NackUserEvent(ctx, event, errors.New("producer error"))
+
+	// The event id is derived from the change, the redelivered event keeps it
+	redelivered := next(events)
+	suite.Equal(event.Id, redelivered.Id)
+	suite.Equal(event.ClusterTime, redelivered.ClusterTime)
+	event = redelivered
+	suite.Equal(secondId, event.UserId)
+	suite.Require().NoError(watcher.AckUserEvent(ctx, event))
+	cancel()
+	for range events {
+	}
+
+	// The changes made while the watcher is stopped are emitted once it watches again, as after a new election
+	thirdId := insertUser("Third")
+
+	ctx, cancel = context.WithCancel(suite.ctx)
+	defer cancel()
+	events = watcher.WatchUsers(ctx)
+
+	event = next(events)
+	suite.Equal(thirdId, event.UserId)
+	suite.Equal(domain.OPERATION_CREATE, event.OperationType)
+	suite.Require().NoError(watcher.AckUserEvent(ctx, event))
+
+	// A new watcher resumes from the same checkpoint, after a restart
+	fourthId := insertUser("Fourth")
+	restarted := mongodb.NewCheckpointedChangeStreamWatcher(collection, checkpoints, mongodb.HistoryLostFail, mongodb.DefaultRetryPolicy)
+	event = next(restarted.WatchUsers(ctx))
+	suite.Equal(fourthId, event.UserId)
+}
+
+func (suite *UserWatcherTestSuite) TestWatchUsers_GivesUpAfterMaxAttempts() {
+	// A client that can never reach a server
+	clientOpts := options.Client().ApplyURI("mongodb://localhost:1").
+		SetServerSelectionTimeout(100 * time.Millisecond)
+	client, err := mongo.Connect(suite.ctx, clientOpts)
+	suite.Require().NoError(err)
+	defer client.Disconnect(suite.ctx)
+
+	collection := client.Database("testdb").Collection("test")
+	retryPolicy := mongodb.RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, MaxAttempts: 2}
+	watcher := mongodb.NewCheckpointedChangeStreamWatcher(collection, nil, mongodb.HistoryLostFail, retryPolicy)
+	suite.Equal(domain.WatcherStateStarting, watcher.WatcherStatus().State)
+
+	events := watcher.WatchUsers(suite.ctx)
+
+	select {
+	case <-watcher.Failed():
+	case <-time.After(15 * time.Second):
+		suite.FailNow("Timed out waiting for the watcher to give up")
+	}
+	_, open := <-events
+	suite.False(open)
+
+	status := watcher.WatcherStatus()
+	suite.Equal(domain.WatcherStateFailed, status.State)
+	suite.Equal(3, status.Attempts)
+	suite.NotEmpty(status.LastError)
+}
+
 func TestUserWatcherTestSuite(t *testing.T) {
 	suite.Run(t, new(UserWatcherTestSuite))
 }
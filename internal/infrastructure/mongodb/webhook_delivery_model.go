@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"time"
+)
+
+// WebhookDeliveryEntity is the outcome of the delivery of a user event to a subscription
+type WebhookDeliveryEntity struct {
+	ID             string               `bson:"_id"`
+	SubscriptionId string               `bson:"subscription_id"`
+	EventId        string               `bson:"event_id"`
+	OperationType  domain.OperationType `bson:"operation_type"`
+	Succeeded      bool                 `bson:"succeeded"`
+	Attempts       int32                `bson:"attempts"`
+	StatusCode     int32                `bson:"status_code"`
+	Error          string               `bson:"error,omitempty"`
+	StartedAt      time.Time            `bson:"started_at"`
+	FinishedAt     time.Time            `bson:"finished_at"`
+}
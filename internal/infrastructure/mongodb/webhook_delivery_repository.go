@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookDeliveryRepository stores the delivery log in an append-only collection
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository creates the repository and the index of the delivery log of a subscription
+func NewWebhookDeliveryRepository(collection *mongo.Collection) *WebhookDeliveryRepository {
+	ensureIndexes(collection, mongo.IndexModel{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "started_at", Value: -1}}})
+	return &WebhookDeliveryRepository{
+		collection: collection,
+	}
+}
+
+func (r *WebhookDeliveryRepository) AppendWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	_, err := r.collection.InsertOne(ctx, &WebhookDeliveryEntity{
+		ID:             delivery.Id,
+		SubscriptionId: delivery.SubscriptionId,
+		EventId:        delivery.EventId,
+		OperationType:  delivery.OperationType,
+		Succeeded:      delivery.Succeeded,
+		Attempts:       delivery.Attempts,
+		StatusCode:     delivery.StatusCode,
+		Error:          delivery.Error,
+		StartedAt:      delivery.StartedAt,
+		FinishedAt:     delivery.FinishedAt,
+	})
+	return err
+}
+
+func (r *WebhookDeliveryRepository) ListWebhookDeliveries(ctx context.Context, request *domain.ListWebhookDeliveriesQueryRequest) (*domain.ListWebhookDeliveriesQueryResponse, error) {
+	if request.PageSize == 0 {
+		request.PageSize = 10
+	}
+
+	filter := bson.M{"subscription_id": request.SubscriptionId}
+	if request.Succeeded != nil {
+		filter["succeeded"] = *request.Succeeded
+	}
+
+	// Get the total count of documents matching the filter
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %v", err)
+	}
+
+	// Pagination options, latest deliveries first
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "started_at", Value: -1}, {Key: "_id", Value: 1}})
+	findOptions.SetSkip(int64(request.Page * request.PageSize))
+	findOptions.SetLimit(int64(request.PageSize))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	var entities []*WebhookDeliveryEntity
+	if err = cursor.All(ctx, &entities); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+
+	results := make([]*domain.WebhookDelivery, len(entities))
+	for i, e := range entities {
+		results[i] = &domain.WebhookDelivery{
+			Id:             e.ID,
+			SubscriptionId: e.SubscriptionId,
+			EventId:        e.EventId,
+			OperationType:  e.OperationType,
+			Succeeded:      e.Succeeded,
+			Attempts:       e.Attempts,
+			StatusCode:     e.StatusCode,
+			Error:          e.Error,
+			StartedAt:      e.StartedAt,
+			FinishedAt:     e.FinishedAt,
+		}
+	}
+
+	return &domain.ListWebhookDeliveriesQueryResponse{
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalCount: uint32(totalCount),
+		Results:    results,
+	}, nil
+}
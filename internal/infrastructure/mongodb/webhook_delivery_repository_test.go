@@ -0,0 +1,112 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type WebhookDeliveryRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.WebhookDeliveryRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *WebhookDeliveryRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_webhook_deliveries")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewWebhookDeliveryRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *WebhookDeliveryRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *WebhookDeliveryRepositoryTestSuite) SetupTest() {
+	// Clean up the collection before each test
+	suite.collection.Drop(suite.ctx)
+}
+
+func (suite *WebhookDeliveryRepositoryTestSuite) TestWebhookDeliveryRepository_ListWebhookDeliveries() {
+	now := time.Now().UTC().Round(time.Millisecond)
+	subscriptionId := uuid.NewString()
+	deliveries := []*domain.WebhookDelivery{
+		{Id: uuid.NewString(), SubscriptionId: subscriptionId, EventId: "event-1", OperationType: domain.OPERATION_CREATE,
+			Succeeded: true, Attempts: 1, StatusCode: 204, StartedAt: now, FinishedAt: now},
+		{Id: uuid.NewString(), SubscriptionId: subscriptionId, EventId: "event-2", OperationType: domain.OPERATION_UPDATE,
+			Attempts: 5, StatusCode: 503, Error: "unexpected status 503", StartedAt: now.Add(time.Second), FinishedAt: now.Add(time.Minute)},
+		{Id: uuid.NewString(), SubscriptionId: subscriptionId, EventId: "event-3", OperationType: domain.OPERATION_DELETE,
+			Succeeded: true, Attempts: 2, StatusCode: 200, StartedAt: now.Add(2 * time.Second), FinishedAt: now.Add(2 * time.Minute)},
+		{Id: uuid.NewString(), SubscriptionId: uuid.NewString(), EventId: "event-1", Succeeded: true, Attempts: 1,
+			StartedAt: now, FinishedAt: now},
+	}
+	for _, delivery := range deliveries {
+		suite.Require().NoError(suite.repo.AppendWebhookDelivery(suite.ctx, delivery))
+	}
+
+	// Latest deliveries first
+	res, err := suite.repo.ListWebhookDeliveries(suite.ctx, &domain.ListWebhookDeliveriesQueryRequest{
+		SubscriptionId: subscriptionId, Page: 0, PageSize: 2,
+	})
+	suite.Require().NoError(err)
+	suite.Equal(uint32(3), res.TotalCount)
+	suite.Require().Len(res.Results, 2)
+	suite.Equal("event-3", res.Results[0].EventId)
+	suite.Equal("event-2", res.Results[1].EventId)
+	suite.Equal(domain.OPERATION_UPDATE, res.Results[1].OperationType)
+	suite.False(res.Results[1].Succeeded)
+	suite.Equal(int32(5), res.Results[1].Attempts)
+	suite.Equal(int32(503), res.Results[1].StatusCode)
+	suite.Equal("unexpected status 503", res.Results[1].Error)
+	suite.True(now.Add(time.Minute).Equal(res.Results[1].FinishedAt))
+
+	succeeded := false
+	res, err = suite.repo.ListWebhookDeliveries(suite.ctx, &domain.ListWebhookDeliveriesQueryRequest{
+		SubscriptionId: subscriptionId, Succeeded: &succeeded,
+	})
+	suite.Require().NoError(err)
+	suite.Equal(uint32(1), res.TotalCount)
+	suite.Require().Len(res.Results, 1)
+	suite.Equal(deliveries[1].Id, res.Results[0].Id)
+}
+
+func TestWebhookDeliveryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookDeliveryRepositoryTestSuite))
+}
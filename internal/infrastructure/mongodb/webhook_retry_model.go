@@ -0,0 +1,35 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"time"
+)
+
+// WebhookRetryEntity is a delivery waiting for its next attempt, keyed by delivery id
+type WebhookRetryEntity struct {
+	ID             string                  `bson:"_id"`
+	SubscriptionId string                  `bson:"subscription_id"`
+	Position       int64                   `bson:"position"`
+	NextAttemptAt  time.Time               `bson:"next_attempt_at"`
+	Attempts       int32                   `bson:"attempts"`
+	StatusCode     int32                   `bson:"status_code"`
+	Error          string                  `bson:"error,omitempty"`
+	StartedAt      time.Time               `bson:"started_at"`
+	Event          WebhookRetryEventEntity `bson:"event"`
+}
+
+// WebhookRetryEventEntity is the user event of a queued delivery
+type WebhookRetryEventEntity struct {
+	ID            string               `bson:"id"`
+	UserId        string               `bson:"user_id"`
+	BeforeChange  *UserEntity          `bson:"before_change,omitempty"`
+	AfterChange   *UserEntity          `bson:"after_change,omitempty"`
+	OperationType domain.OperationType `bson:"operation_type"`
+	ModifiedBy    string               `bson:"modified_by"`
+	CorrelationId string               `bson:"correlation_id"`
+	TraceParent   string               `bson:"trace_parent"`
+	Sequence      int64                `bson:"sequence"`
+	ClusterTime   int64                `bson:"cluster_time"`
+	OccurredAt    time.Time            `bson:"occurred_at"`
+	ChangedFields []string             `bson:"changed_fields,omitempty"`
+}
@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRetryRepository stores the queues of the webhook subscriptions in a single collection
+type WebhookRetryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookRetryRepository creates the repository and the index of the queue of a subscription
+func NewWebhookRetryRepository(collection *mongo.Collection) *WebhookRetryRepository {
+	ensureIndexes(collection, mongo.IndexModel{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "position", Value: 1}}})
+	return &WebhookRetryRepository{
+		collection: collection,
+	}
+}
+
+func (r *WebhookRetryRepository) EnqueueWebhookRetry(ctx context.Context, retry *domain.WebhookRetry) error {
+	_, err := r.collection.InsertOne(ctx, toWebhookRetryEntity(retry))
+	return err
+}
+
+func (r *WebhookRetryRepository) ListQueuedWebhookSubscriptions(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "subscription_id", bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *WebhookRetryRepository) GetFirstWebhookRetry(ctx context.Context, subscriptionId string) (*domain.WebhookRetry, error) {
+	var entity *WebhookRetryEntity
+	opts := options.FindOne().SetSort(bson.D{{Key: "position", Value: 1}})
+	err := r.collection.FindOne(ctx, bson.M{"subscription_id": subscriptionId}, opts).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrWebhookRetryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhookRetryToDomain(entity), nil
+}
+
+// UpdateWebhookRetry saves the attempts and the next attempt time of the retry
+func (r *WebhookRetryRepository) UpdateWebhookRetry(ctx context.Context, retry *domain.WebhookRetry) error {
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": retry.Delivery.Id}, bson.M{"$set": bson.M{
+		"next_attempt_at": retry.NextAttemptAt,
+		"attempts":        retry.Delivery.Attempts,
+		"status_code":     retry.Delivery.StatusCode,
+		"error":           retry.Delivery.Error,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrWebhookRetryNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRetryRepository) DeleteWebhookRetry(ctx context.Context, id string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *WebhookRetryRepository) DeleteWebhookRetries(ctx context.Context, subscriptionId string) (int64, error) {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"subscription_id": subscriptionId})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+func toWebhookRetryEntity(retry *domain.WebhookRetry) *WebhookRetryEntity {
+	event := retry.Event
+	entity := &WebhookRetryEntity{
+		ID:             retry.Delivery.Id,
+		SubscriptionId: retry.Delivery.SubscriptionId,
+		Position:       retry.Position,
+		NextAttemptAt:  retry.NextAttemptAt,
+		Attempts:       retry.Delivery.Attempts,
+		StatusCode:     retry.Delivery.StatusCode,
+		Error:          retry.Delivery.Error,
+		StartedAt:      retry.Delivery.StartedAt,
+		Event: WebhookRetryEventEntity{
+			ID:            event.Id,
+			UserId:        event.UserId,
+			OperationType: event.OperationType,
+			ModifiedBy:    event.ModifiedBy,
+			CorrelationId: event.CorrelationId,
+			TraceParent:   event.TraceParent,
+			Sequence:      event.Sequence,
+			ClusterTime:   int64(event.ClusterTime),
+			OccurredAt:    event.OccurredAt,
+			ChangedFields: event.ChangedFields,
+		},
+	}
+	if event.BeforeChange != nil {
+		entity.Event.BeforeChange = toEntity(event.BeforeChange)
+	}
+	if event.AfterChange != nil {
+		entity.Event.AfterChange = toEntity(event.AfterChange)
+	}
+	return entity
+}
+
+func webhookRetryToDomain(e *WebhookRetryEntity) *domain.WebhookRetry {
+	return &domain.WebhookRetry{
+		Delivery: &domain.WebhookDelivery{
+			Id:             e.ID,
+			SubscriptionId: e.SubscriptionId,
+			EventId:        e.Event.ID,
+			OperationType:  e.Event.OperationType,
+			Attempts:       e.Attempts,
+			StatusCode:     e.StatusCode,
+			Error:          e.Error,
+			StartedAt:      e.StartedAt,
+		},
+		Event: &domain.UserEvent{
+			Id:            e.Event.ID,
+			UserId:        e.Event.UserId,
+			BeforeChange:  userToDomain(e.Event.BeforeChange),
+			AfterChange:   userToDomain(e.Event.AfterChange),
+			OperationType: e.Event.OperationType,
+			ModifiedBy:    e.Event.ModifiedBy,
+			CorrelationId: e.Event.CorrelationId,
+			TraceParent:   e.Event.TraceParent,
+			Sequence:      e.Event.Sequence,
+			ClusterTime:   uint64(e.Event.ClusterTime),
+			OccurredAt:    e.Event.OccurredAt,
+			ChangedFields: e.Event.ChangedFields,
+		},
+		Position:      e.Position,
+		NextAttemptAt: e.NextAttemptAt,
+	}
+}
@@ -0,0 +1,129 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type WebhookRetryRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.WebhookRetryRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *WebhookRetryRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_webhook_retries")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewWebhookRetryRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *WebhookRetryRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *WebhookRetryRepositoryTestSuite) SetupTest() {
+	// Clean up the documents before each test, keeping the indexes
+	suite.collection.DeleteMany(suite.ctx, map[string]any{})
+}
+
+func (suite *WebhookRetryRepositoryTestSuite) enqueue(id, subscriptionId string, position int64) *domain.WebhookRetry {
+	retry := &domain.WebhookRetry{
+		Delivery: &domain.WebhookDelivery{Id: id, SubscriptionId: subscriptionId, EventId: "event-" + id,
+			OperationType: domain.OPERATION_UPDATE, Attempts: 1, StatusCode: 503, Error: "unexpected status 503",
+			StartedAt: time.Now().UTC().Round(time.Millisecond)},
+		Event: &domain.UserEvent{Id: "event-" + id, UserId: "user-123", OperationType: domain.OPERATION_UPDATE,
+			AfterChange: &domain.User{ID: "user-123", Email: "john@example.com"}, ClusterTime: 42},
+		Position:      position,
+		NextAttemptAt: time.Now().UTC().Round(time.Millisecond),
+	}
+	suite.Require().NoError(suite.repo.EnqueueWebhookRetry(suite.ctx, retry))
+	return retry
+}
+
+func (suite *WebhookRetryRepositoryTestSuite) TestWebhookRetryRepository_Queue() {
+	second := suite.enqueue("delivery-2", "webhook-1", 2)
+	first := suite.enqueue("delivery-1", "webhook-1", 1)
+	suite.enqueue("delivery-3", "webhook-2", 3)
+
+	queued, err := suite.repo.ListQueuedWebhookSubscriptions(suite.ctx)
+	suite.Require().NoError(err)
+	suite.ElementsMatch([]string{"webhook-1", "webhook-2"}, queued)
+
+	// The queue of a subscription is ordered by position
+	head, err := suite.repo.GetFirstWebhookRetry(suite.ctx, "webhook-1")
+	suite.Require().NoError(err)
+	suite.Equal(first.Delivery, head.Delivery)
+	suite.Equal(first.Event.AfterChange.Email, head.Event.AfterChange.Email)
+	suite.Equal(first.Event.ClusterTime, head.Event.ClusterTime)
+	suite.Nil(head.Event.BeforeChange)
+
+	head.Delivery.Attempts = 2
+	head.NextAttemptAt = head.NextAttemptAt.Add(time.Minute)
+	suite.Require().NoError(suite.repo.UpdateWebhookRetry(suite.ctx, head))
+	updated, err := suite.repo.GetFirstWebhookRetry(suite.ctx, "webhook-1")
+	suite.Require().NoError(err)
+	suite.Equal(int32(2), updated.Delivery.Attempts)
+	suite.True(head.NextAttemptAt.Equal(updated.NextAttemptAt))
+
+	suite.Require().NoError(suite.repo.DeleteWebhookRetry(suite.ctx, "delivery-1"))
+	head, err = suite.repo.GetFirstWebhookRetry(suite.ctx, "webhook-1")
+	suite.Require().NoError(err)
+	suite.Equal(second.Delivery.Id, head.Delivery.Id)
+}
+
+func (suite *WebhookRetryRepositoryTestSuite) TestWebhookRetryRepository_DeleteWebhookRetries() {
+	suite.enqueue("delivery-1", "webhook-1", 1)
+	suite.enqueue("delivery-2", "webhook-1", 2)
+	suite.enqueue("delivery-3", "webhook-2", 3)
+
+	deleted, err := suite.repo.DeleteWebhookRetries(suite.ctx, "webhook-1")
+	suite.Require().NoError(err)
+	suite.Equal(int64(2), deleted)
+
+	_, err = suite.repo.GetFirstWebhookRetry(suite.ctx, "webhook-1")
+	suite.Equal(domain.ErrWebhookRetryNotFound, err)
+	queued, err := suite.repo.ListQueuedWebhookSubscriptions(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"webhook-2"}, queued)
+}
+
+func TestWebhookRetryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRetryRepositoryTestSuite))
+}
@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"time"
+)
+
+// WebhookSubscriptionEntity is an endpoint receiving the user events, keyed by subscription id
+type WebhookSubscriptionEntity struct {
+	ID                  string                 `bson:"_id"`
+	Url                 string                 `bson:"url"`
+	Secret              string                 `bson:"secret"`
+	OperationTypes      []domain.OperationType `bson:"operation_types"`
+	Enabled             bool                   `bson:"enabled"`
+	ConsecutiveFailures int32                  `bson:"consecutive_failures"`
+	DisabledReason      string                 `bson:"disabled_reason,omitempty"`
+	CreatedBy           string                 `bson:"created_by"`
+	CreatedAt           time.Time              `bson:"created_at"`
+	UpdatedAt           time.Time              `bson:"updated_at"`
+}
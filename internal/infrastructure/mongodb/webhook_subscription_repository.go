@@ -0,0 +1,190 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookSubscriptionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewWebhookSubscriptionRepository(collection *mongo.Collection) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{
+		collection: collection,
+	}
+}
+
+func (r *WebhookSubscriptionRepository) CreateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	_, err := r.collection.InsertOne(ctx, toWebhookSubscriptionEntity(subscription))
+	return err
+}
+
+func (r *WebhookSubscriptionRepository) GetWebhookSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	var entity *WebhookSubscriptionEntity
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrWebhookSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhookSubscriptionToDomain(entity), nil
+}
+
+func (r *WebhookSubscriptionRepository) ListWebhookSubscriptions(ctx context.Context, request *domain.ListWebhookSubscriptionsQueryRequest) (*domain.ListWebhookSubscriptionsQueryResponse, error) {
+	if request.PageSize == 0 {
+		request.PageSize = 10
+	}
+
+	filter := bson.M{}
+
+	// Get the total count of documents matching the filter
+	totalCount, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %v", err)
+	}
+
+	// Pagination options, oldest subscriptions first
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	findOptions.SetSkip(int64(request.Page * request.PageSize))
+	findOptions.SetLimit(int64(request.PageSize))
+
+	results, err := r.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ListWebhookSubscriptionsQueryResponse{
+		Page:       request.Page,
+		PageSize:   request.PageSize,
+		TotalCount: uint32(totalCount),
+		Results:    results,
+	}, nil
+}
+
+func (r *WebhookSubscriptionRepository) ListEnabledWebhookSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return r.find(ctx, bson.M{"enabled": true}, options.Find())
+}
+
+// UpdateWebhookSubscription updates the fields managed by the admins. The failures counted
+// concurrently by the deliveries are only reset when a disabled subscription is enabled again.
+func (r *WebhookSubscriptionRepository) UpdateWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	enabledAgain := bson.M{"$and": bson.A{subscription.Enabled, bson.M{"$not": bson.A{"$enabled"}}}}
+	update := bson.A{bson.M{"$set": bson.M{
+		// The strings are literals, a pipeline would read a leading $ as a field path
+		"url":                  bson.M{"$literal": subscription.Url},
+		"secret":               bson.M{"$literal": subscription.Secret},
+		"operation_types":      subscription.OperationTypes,
+		"consecutive_failures": bson.M{"$cond": bson.A{enabledAgain, 0, "$consecutive_failures"}},
+		"disabled_reason":      bson.M{"$cond": bson.A{enabledAgain, "$$REMOVE", "$disabled_reason"}},
+		"enabled":              subscription.Enabled,
+		"updated_at":           subscription.UpdatedAt,
+	}}}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": subscription.Id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+// RecordWebhookOutcome updates the failures with a single pipeline update, so that the
+// concurrent deliveries of the subscription disable it exactly once
+func (r *WebhookSubscriptionRepository) RecordWebhookOutcome(ctx context.Context, id string, failure error, disableAfter int32) (*domain.WebhookSubscription, error) {
+	var update interface{} = bson.M{"$set": bson.M{"consecutive_failures": 0}}
+	if failure != nil {
+		failures := bson.M{"$add": bson.A{"$consecutive_failures", 1}}
+		disable := bson.M{"$and": bson.A{"$enabled", bson.M{"$gte": bson.A{failures, disableAfter}}}}
+		reason := fmt.Sprintf("%d consecutive deliveries failed, last error: %v", disableAfter, failure)
+		update = bson.A{bson.M{"$set": bson.M{
+			"consecutive_failures": failures,
+			"enabled":              bson.M{"$cond": bson.A{disable, false, "$enabled"}},
+			"disabled_reason":      bson.M{"$cond": bson.A{disable, bson.M{"$literal": reason}, "$disabled_reason"}},
+		}}}
+	}
+
+	var entity *WebhookSubscriptionEntity
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&entity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrWebhookSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhookSubscriptionToDomain(entity), nil
+}
+
+func (r *WebhookSubscriptionRepository) find(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]*domain.WebhookSubscription, error) {
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Warnf("failed to close cursor: %v", err)
+		}
+	}()
+
+	var entities []*WebhookSubscriptionEntity
+	if err = cursor.All(ctx, &entities); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook subscriptions: %w", err)
+	}
+
+	results := make([]*domain.WebhookSubscription, len(entities))
+	for i, e := range entities {
+		results[i] = webhookSubscriptionToDomain(e)
+	}
+	return results, nil
+}
+
+func toWebhookSubscriptionEntity(s *domain.WebhookSubscription) *WebhookSubscriptionEntity {
+	return &WebhookSubscriptionEntity{
+		ID:                  s.Id,
+		Url:                 s.Url,
+		Secret:              s.Secret,
+		OperationTypes:      s.OperationTypes,
+		Enabled:             s.Enabled,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledReason:      s.DisabledReason,
+		CreatedBy:           s.CreatedBy,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+}
+
+func webhookSubscriptionToDomain(e *WebhookSubscriptionEntity) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		Id:                  e.ID,
+		Url:                 e.Url,
+		Secret:              e.Secret,
+		OperationTypes:      e.OperationTypes,
+		Enabled:             e.Enabled,
+		ConsecutiveFailures: e.ConsecutiveFailures,
+		DisabledReason:      e.DisabledReason,
+		CreatedBy:           e.CreatedBy,
+		CreatedAt:           e.CreatedAt,
+		UpdatedAt:           e.UpdatedAt,
+	}
+}
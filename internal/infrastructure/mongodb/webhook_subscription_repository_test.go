@@ -0,0 +1,159 @@
+//go:build integration
+
+package mongodb_test
+
+import (
+	"context"
+	"errors"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/mongodb"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tc "github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"os"
+	"testing"
+	"time"
+)
+
+type WebhookSubscriptionRepositoryTestSuite struct {
+	suite.Suite
+	mongoC     testcontainers.Container
+	client     *mongo.Client
+	collection *mongo.Collection
+	repo       *mongodb.WebhookSubscriptionRepository
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	ctx := context.Background()
+	mongoC, err := tc.RunContainer(ctx,
+		testcontainers.WithImage("mongo:7"),
+		tc.WithReplicaSet(),
+	)
+	suite.Require().NoError(err)
+
+	connStr, err := mongoC.ConnectionString(ctx)
+	suite.Require().NoError(err)
+
+	clientOpts := options.Client().ApplyURI(connStr).SetDirect(true)
+	client, err := mongo.Connect(ctx, clientOpts)
+	suite.Require().NoError(err)
+
+	collection := client.Database("testdb").Collection("test_webhook_subscriptions")
+
+	suite.mongoC = mongoC
+	suite.client = client
+	suite.collection = collection
+	suite.repo = mongodb.NewWebhookSubscriptionRepository(collection)
+	suite.ctx, suite.cancel = context.WithTimeout(ctx, 5*time.Second)
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) TearDownSuite() {
+	suite.client.Disconnect(suite.ctx)
+	suite.mongoC.Terminate(suite.ctx)
+	suite.cancel()
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) SetupTest() {
+	// Clean up the collection before each test
+	suite.collection.Drop(suite.ctx)
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) createSubscription(createdAt time.Time) *domain.WebhookSubscription {
+	subscription := &domain.WebhookSubscription{
+		Id:             uuid.NewString(),
+		Url:            "https://partner.example.com/hooks",
+		Secret:         "secret",
+		OperationTypes: []domain.OperationType{domain.OPERATION_CREATE, domain.OPERATION_DELETE},
+		Enabled:        true,
+		CreatedBy:      "admin-1",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	suite.Require().NoError(suite.repo.CreateWebhookSubscription(suite.ctx, subscription))
+	return subscription
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) TestWebhookSubscriptionRepository_CRUD() {
+	now := time.Now().UTC().Round(time.Millisecond)
+	first := suite.createSubscription(now)
+	second := suite.createSubscription(now.Add(time.Second))
+
+	found, err := suite.repo.GetWebhookSubscription(suite.ctx, first.Id)
+	suite.Require().NoError(err)
+	suite.Equal(first.Url, found.Url)
+	suite.Equal("secret", found.Secret)
+	suite.Equal(first.OperationTypes, found.OperationTypes)
+	suite.True(found.Enabled)
+	suite.Equal("admin-1", found.CreatedBy)
+	suite.True(now.Equal(found.CreatedAt))
+
+	second.Url = "$https://partner.example.com/v2/hooks"
+	second.Enabled = false
+	second.UpdatedAt = now.Add(time.Minute)
+	suite.Require().NoError(suite.repo.UpdateWebhookSubscription(suite.ctx, second))
+
+	res, err := suite.repo.ListWebhookSubscriptions(suite.ctx, &domain.ListWebhookSubscriptionsQueryRequest{Page: 0, PageSize: 10})
+	suite.Require().NoError(err)
+	suite.Equal(uint32(2), res.TotalCount)
+	suite.Require().Len(res.Results, 2)
+	suite.Equal(first.Id, res.Results[0].Id)
+	suite.Equal("$https://partner.example.com/v2/hooks", res.Results[1].Url)
+	suite.False(res.Results[1].Enabled)
+
+	enabled, err := suite.repo.ListEnabledWebhookSubscriptions(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Len(enabled, 1)
+	suite.Equal(first.Id, enabled[0].Id)
+
+	suite.Require().NoError(suite.repo.DeleteWebhookSubscription(suite.ctx, first.Id))
+	suite.Equal(domain.ErrWebhookSubscriptionNotFound, suite.repo.DeleteWebhookSubscription(suite.ctx, first.Id))
+	_, err = suite.repo.GetWebhookSubscription(suite.ctx, first.Id)
+	suite.Equal(domain.ErrWebhookSubscriptionNotFound, err)
+	suite.Equal(domain.ErrWebhookSubscriptionNotFound, suite.repo.UpdateWebhookSubscription(suite.ctx, first))
+}
+
+func (suite *WebhookSubscriptionRepositoryTestSuite) TestWebhookSubscriptionRepository_RecordWebhookOutcome() {
+	subscription := suite.createSubscription(time.Now().UTC().Round(time.Millisecond))
+	failure := errors.New("unexpected status 503")
+
+	updated, err := suite.repo.RecordWebhookOutcome(suite.ctx, subscription.Id, failure, 2)
+	suite.Require().NoError(err)
+	suite.Equal(int32(1), updated.ConsecutiveFailures)
+	suite.True(updated.Enabled)
+
+	// A delivery resets the failures
+	updated, err = suite.repo.RecordWebhookOutcome(suite.ctx, subscription.Id, nil, 2)
+	suite.Require().NoError(err)
+	suite.Equal(int32(0), updated.ConsecutiveFailures)
+
+	for i := 0; i < 2; i++ {
+		updated, err = suite.repo.RecordWebhookOutcome(suite.ctx, subscription.Id, failure, 2)
+		suite.Require().NoError(err)
+	}
+	suite.Equal(int32(2), updated.ConsecutiveFailures)
+	suite.False(updated.Enabled)
+	suite.Equal("2 consecutive deliveries failed, last error: unexpected status 503", updated.DisabledReason)
+
+	// Enabling the subscription again resets its failures
+	updated.Enabled = true
+	suite.Require().NoError(suite.repo.UpdateWebhookSubscription(suite.ctx, updated))
+	found, err := suite.repo.GetWebhookSubscription(suite.ctx, subscription.Id)
+	suite.Require().NoError(err)
+	suite.True(found.Enabled)
+	suite.Equal(int32(0), found.ConsecutiveFailures)
+	suite.Empty(found.DisabledReason)
+
+	_, err = suite.repo.RecordWebhookOutcome(suite.ctx, uuid.NewString(), nil, 2)
+	suite.Equal(domain.ErrWebhookSubscriptionNotFound, err)
+}
+
+func TestWebhookSubscriptionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookSubscriptionRepositoryTestSuite))
+}
@@ -0,0 +1,159 @@
+package spill
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	segmentExt = ".seg"
+	cursorFile = "cursor"
+	// recordHeaderSize is the length and the CRC-32 of the payload, both big-endian uint32
+	recordHeaderSize = 8
+)
+
+var errCorruptRecord = errors.New("corrupt spill record")
+
+// segment is a file of records appended one after the other. Only the last segment of a
+// queue is written to, and the records of the first one are read from offset on.
+type segment struct {
+	id   uint64
+	path string
+	size int64
+	// records is the number of records not drained yet
+	records int
+}
+
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", id, segmentExt))
+}
+
+// listSegments returns the segments of dir, oldest first
+func listSegments(dir string) ([]*segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []*segment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, &segment{id: id, path: filepath.Join(dir, name)})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].id < segments[j].id })
+	return segments, nil
+}
+
+// scanSegment counts the records following offset. A record cut short by a crash, or
+// corrupted, ends the segment: the file is truncated before it. An I/O error leaves the file as is.
+func scanSegment(s *segment, offset int64) error {
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	s.size = info.Size()
+	if offset > s.size {
+		offset = s.size
+	}
+	for offset < s.size {
+		_, next, err := readRecord(f, offset, s.size)
+		if err != nil {
+			if !errors.Is(err, errCorruptRecord) {
+				return err
+			}
+			if err := f.Truncate(offset); err != nil {
+				return err
+			}
+			s.size = offset
+			return fmt.Errorf("segment %s truncated at %d: %w", s.path, offset, err)
+		}
+		s.records++
+		offset = next
+	}
+	return nil
+}
+
+// encodeRecord frames payload with its length and checksum
+func encodeRecord(payload []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[recordHeaderSize:], payload)
+	return record
+}
+
+// readRecord reads the record at offset of a segment of size bytes, and returns its payload
+// and the offset of the next record. A record that is truncated or does not match its checksum
+// fails with errCorruptRecord, while a failed read returns its error as is, to be retried.
+func readRecord(f io.ReaderAt, offset, size int64) ([]byte, int64, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := f.ReadAt(header, offset); err != nil {
+		return nil, 0, readError(err)
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if offset+recordHeaderSize+int64(length) > size {
+		return nil, 0, fmt.Errorf("%w: record exceeds the segment", errCorruptRecord)
+	}
+	payload := make([]byte, length)
+	if _, err := f.ReadAt(payload, offset+recordHeaderSize); err != nil {
+		return nil, 0, readError(err)
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, 0, fmt.Errorf("%w: checksum mismatch", errCorruptRecord)
+	}
+	return payload, offset + recordHeaderSize + int64(length), nil
+}
+
+// readError tells a record cut short, which ends before the file does, from a failed read
+func readError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %v", errCorruptRecord, err)
+	}
+	return err
+}
+
+// loadCursor returns the position of the next record to drain, the beginning of the queue when not saved yet
+func loadCursor(dir string) (uint64, int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, cursorFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	var id uint64
+	var offset int64
+	if _, err := fmt.Sscanf(string(data), "%d %d", &id, &offset); err != nil {
+		return 0, 0, fmt.Errorf("invalid spill cursor: %w", err)
+	}
+	return id, offset, nil
+}
+
+// saveCursor replaces the cursor atomically, for a crash to leave either the previous or the new one
+func saveCursor(dir string, id uint64, offset int64) error {
+	tmp := filepath.Join(dir, cursorFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%d %d\n", id, offset)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, cursorFile))
+}
@@ -0,0 +1,561 @@
+package spill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what happens to an event to spill when the queue is full
+type OverflowPolicy string
+
+const (
+	// OverflowFail fails the publication of the event, which is then dead-lettered
+	OverflowFail OverflowPolicy = "fail"
+	// OverflowBlock waits for drained events to make room, holding the watcher back meanwhile
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropOldest deletes the oldest segment, and the events it holds, to make room
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+)
+
+const (
+	defaultMaxBytes      = 1 << 30
+	defaultSegmentBytes  = 16 << 20
+	defaultRetryInterval = time.Second
+)
+
+var (
+	ErrSpillFull   = errors.New("spill queue is full")
+	ErrSpillClosed = errors.New("spill queue is closed")
+)
+
+// Options configures where and how much a UserSpillProducer spills
+type Options struct {
+	// Dir holds the segment files, and the position of the next event to drain
+	Dir string
+	// MaxBytes is the size of the segment files above which OverflowPolicy applies, 1GiB by default
+	MaxBytes int64
+	// SegmentBytes is the size above which a new segment file is started, 16MiB by default
+	SegmentBytes int64
+	// OverflowPolicy is OverflowFail by default
+	OverflowPolicy OverflowPolicy
+	// RetryInterval is the delay before sending a spilled event again after a failure, 1s by default
+	RetryInterval time.Duration
+	// Spillable tells the failures caused by the broker being unavailable, every failure when nil
+	Spillable func(err error) bool
+	// DeadLetters stores the spilled events failing for another reason, which are retried when nil
+	DeadLetters domain.UserDeadLetterRepository
+}
+
+// Stats is the state of the spill queue, as reported to the metrics
+type Stats struct {
+	// Records is the number of events waiting to be drained
+	Records int
+	// Bytes is the size of the segment files
+	Bytes    int64
+	Segments int
+	// Dropped is the number of events lost to OverflowDropOldest or to corrupt segments since the start
+	Dropped int64
+}
+
+// position is the place of a record in the queue, next being the offset of the following record
+type position struct {
+	segmentId uint64
+	offset    int64
+	next      int64
+}
+
+// spilledEvent is the payload of a record, the event with the term of the leader that spilled it
+type spilledEvent struct {
+	Term  int64             `json:"term,omitempty"`
+	Event *domain.UserEvent `json:"event"`
+}
+
+// UserSpillProducer sends the user events through producer and, while the broker is unavailable,
+// appends them to a write-ahead queue of segment files instead. Once an event has been spilled, the
+// following ones are spilled too until the queue is drained, so that they are published in order.
+// Run drains the queue, oldest event first, as soon as the broker accepts the events again.
+//
+// A spilled event counts as published: the watcher moves past it, and the queue is drained again
+// after a restart. Events are delivered at least once, the last drained one may be sent twice.
+//
+// The events are stamped with the fencing token of the last Run. Run only sends the events of its
+// own term: those of an older one have been followed by the events of other leaders meanwhile,
+// and are dead-lettered instead of being published out of order.
+type UserSpillProducer struct {
+	producer domain.UserProducer
+	opts     Options
+	wake     chan struct{}
+	term     atomic.Int64
+
+	mu       sync.Mutex
+	room     *sync.Cond
+	segments []*segment
+	writer   *os.File
+	reader   *os.File
+	offset   int64
+	nextId   uint64
+	records  int
+	bytes    int64
+	dropped  int64
+	closed   bool
+}
+
+// NewUserSpillProducer opens the queue of opts.Dir, with the events spilled before a restart
+func NewUserSpillProducer(producer domain.UserProducer, opts Options) (*UserSpillProducer, error) {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = defaultMaxBytes
+	}
+	if opts.SegmentBytes <= 0 {
+		opts.SegmentBytes = defaultSegmentBytes
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultRetryInterval
+	}
+	if opts.Spillable == nil {
+		opts.Spillable = func(err error) bool { return true }
+	}
+	switch opts.OverflowPolicy {
+	case "":
+		opts.OverflowPolicy = OverflowFail
+	case OverflowFail, OverflowBlock, OverflowDropOldest:
+	default:
+		return nil, fmt.Errorf("unknown spill overflow policy %q", opts.OverflowPolicy)
+	}
+
+	p := &UserSpillProducer{producer: producer, opts: opts, wake: make(chan struct{}, 1)}
+	p.room = sync.NewCond(&p.mu)
+	if err := p.load(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	if p.records > 0 {
+		log.Infof("Found %d spilled user events to drain in %s", p.records, opts.Dir)
+	}
+	return p, nil
+}
+
+// load reads the segments left by the previous run, from the saved position on
+func (p *UserSpillProducer) load() error {
+	if err := os.MkdirAll(p.opts.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	cursorId, cursorOffset, err := loadCursor(p.opts.Dir)
+	if err != nil {
+		return err
+	}
+	segments, err := listSegments(p.opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	p.nextId = cursorId + 1
+	for _, s := range segments {
+		p.nextId = max(p.nextId, s.id+1)
+		if s.id < cursorId {
+			// Already drained, it was about to be removed
+			if err := os.Remove(s.path); err != nil {
+				return err
+			}
+			continue
+		}
+		offset := int64(0)
+		if s.id == cursorId {
+			offset = cursorOffset
+		}
+		if err := scanSegment(s, offset); err != nil {
+			if !errors.Is(err, errCorruptRecord) {
+				return err
+			}
+			log.Warnf("Discarding the corrupt tail of a spill segment: %v", err)
+		}
+		if s.records == 0 {
+			if err := os.Remove(s.path); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(p.segments) == 0 {
+			p.offset = offset
+		}
+		p.segments = append(p.segments, s)
+		p.records += s.records
+		p.bytes += s.size
+	}
+
+	if len(p.segments) > 0 {
+		last := p.segments[len(p.segments)-1]
+		p.writer, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendMessage sends the event, or spills it when the broker is unavailable or the queue is not empty
+func (p *UserSpillProducer) SendMessage(event *domain.UserEvent) error {
+	p.mu.Lock()
+	spilling := p.records > 0
+	p.mu.Unlock()
+
+	if !spilling {
+		err := p.producer.SendMessage(event)
+		if err == nil || !p.opts.Spillable(err) {
+			return err
+		}
+		log.Warnf("Broker unavailable, spilling user event %s to disk: %v", event.Id, err)
+	}
+	return p.spill(event)
+}
+
+func (p *UserSpillProducer) spill(event *domain.UserEvent) error {
+	payload, err := json.Marshal(spilledEvent{Term: p.term.Load(), Event: event})
+	if err != nil {
+		return fmt.Errorf("failed to encode spilled user event: %w", err)
+	}
+	record := encodeRecord(payload)
+	size := int64(len(record))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if size > p.opts.MaxBytes {
+		return ErrSpillFull
+	}
+	for !p.closed && p.bytes+size > p.opts.MaxBytes {
+		switch p.opts.OverflowPolicy {
+		case OverflowBlock:
+			p.room.Wait()
+		case OverflowDropOldest:
+			head := p.segments[0]
+			log.Warnf("Spill queue full, dropping the %d user events of %s", head.records, head.path)
+			p.dropped += int64(head.records)
+			p.records -= head.records
+			p.removeHead()
+		default:
+			return ErrSpillFull
+		}
+	}
+	if p.closed {
+		return ErrSpillClosed
+	}
+	if err := p.append(record); err != nil {
+		return err
+	}
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// append writes the record to the last segment, or to a new one when it would grow too big
+func (p *UserSpillProducer) append(record []byte) error {
+	size := int64(len(record))
+	if p.writer == nil || p.segments[len(p.segments)-1].size+size > p.opts.SegmentBytes {
+		if err := p.roll(); err != nil {
+			return err
+		}
+	}
+	last := p.segments[len(p.segments)-1]
+
+	_, err := p.writer.Write(record)
+	if err == nil {
+		err = p.writer.Sync()
+	}
+	if err != nil {
+		// Cut the partial record, for the next one to be readable
+		_ = p.writer.Truncate(last.size)
+		return fmt.Errorf("failed to spill user event: %w", err)
+	}
+	last.size += size
+	last.records++
+	p.records++
+	p.bytes += size
+	return nil
+}
+
+// roll starts a new segment, the previous one is not written to anymore
+func (p *UserSpillProducer) roll() error {
+	if p.writer != nil {
+		if err := p.writer.Close(); err != nil {
+			log.Warnf("failed to close spill segment: %v", err)
+		}
+		p.writer = nil
+	}
+	s := &segment{id: p.nextId, path: segmentPath(p.opts.Dir, p.nextId)}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create spill segment: %w", err)
+	}
+	p.nextId++
+	p.writer = f
+	p.segments = append(p.segments, s)
+	return nil
+}
+
+// Start sets the term of the events spilled from now on to the fencing token of ctx, then drains
+// the queue until ctx is done. The returned channel is closed once the drainer has stopped.
+func (p *UserSpillProducer) Start(ctx context.Context) <-chan struct{} {
+	p.lead(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Run(ctx)
+	}()
+	return done
+}
+
+// lead sets the term of the spilled events, unchanged when ctx carries no fencing token
+func (p *UserSpillProducer) lead(ctx context.Context) {
+	if term, ok := domain.FencingTokenFromContext(ctx); ok {
+		p.term.Store(term)
+	}
+}
+
+// Run drains the queue until ctx is done, as the leader of the fencing token of ctx. An event
+// failing because the broker is still unavailable, or that could not be read from its segment,
+// is tried again after RetryInterval, and the events after it wait. Only a corrupt record is
+// discarded, and the events of an older term are dead-lettered.
+func (p *UserSpillProducer) Run(ctx context.Context) {
+	p.lead(ctx)
+	term, fenced := domain.FencingTokenFromContext(ctx)
+	for {
+		if ctx.Err() != nil {
+			// Leadership lost, the events are left to the next term
+			return
+		}
+		record, pos, err := p.peek()
+		switch {
+		case errors.Is(err, errCorruptRecord):
+			log.Errorf("Discarding unreadable spilled user events: %v", err)
+			p.discard(pos)
+			continue
+		case err != nil:
+			log.Errorf("Error reading spilled user events, retrying: %v", err)
+			select {
+			case <-time.After(p.opts.RetryInterval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		case record == nil:
+			select {
+			case <-p.wake:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		case fenced && record.Term != 0 && record.Term < term:
+			if !p.deadLetterStale(ctx, record) {
+				select {
+				case <-time.After(p.opts.RetryInterval):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			p.advance(pos)
+			continue
+		}
+
+		event := record.Event
+		if err := p.producer.SendMessage(event); err != nil && !p.deadLetter(ctx, event, err) {
+			select {
+			case <-time.After(p.opts.RetryInterval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		p.advance(pos)
+	}
+}
+
+// deadLetter stores the event failing for another reason than the broker being unavailable,
+// and tells whether it can be removed from the queue
+func (p *UserSpillProducer) deadLetter(ctx context.Context, event *domain.UserEvent, failure error) bool {
+	if p.opts.Spillable(failure) {
+		log.Debugf("Broker still unavailable, keeping spilled user event %s: %v", event.Id, failure)
+		return false
+	}
+	if p.opts.DeadLetters == nil {
+		log.Errorf("Error sending spilled user event %s, retrying: %v", event.Id, failure)
+		return false
+	}
+	if err := p.opts.DeadLetters.RecordDeadLetter(ctx, event, failure, time.Now().UTC().Round(time.Millisecond)); err != nil {
+		log.Errorf("Error dead-lettering spilled user event %s: %v", event.Id, err)
+		return false
+	}
+	log.Warnf("Spilled user event %s dead-lettered: %v", event.Id, failure)
+	return true
+}
+
+// deadLetterStale stores the event spilled in an older term, and tells whether it can be removed
+// from the queue. Without a dead letter repository, the event is dropped.
+func (p *UserSpillProducer) deadLetterStale(ctx context.Context, record *spilledEvent) bool {
+	failure := fmt.Errorf("spilled in term %d: %w", record.Term, domain.ErrStaleFencingToken)
+	if p.opts.DeadLetters == nil {
+		log.Errorf("Dropping spilled user event %s: %v", record.Event.Id, failure)
+		p.mu.Lock()
+		p.dropped++
+		p.mu.Unlock()
+		return true
+	}
+	if err := p.opts.DeadLetters.RecordDeadLetter(ctx, record.Event, failure, time.Now().UTC().Round(time.Millisecond)); err != nil {
+		log.Errorf("Error dead-lettering spilled user event %s: %v", record.Event.Id, err)
+		return false
+	}
+	log.Warnf("Spilled user event %s dead-lettered: %v", record.Event.Id, failure)
+	return true
+}
+
+// peek reads the oldest record of the queue, nil when the queue is empty
+func (p *UserSpillProducer) peek() (*spilledEvent, position, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.records == 0 || p.closed {
+		return nil, position{}, nil
+	}
+	head := p.segments[0]
+	pos := position{segmentId: head.id, offset: p.offset}
+	if p.reader == nil {
+		f, err := os.Open(head.path)
+		if err != nil {
+			return nil, pos, fmt.Errorf("failed to open spill segment: %w", err)
+		}
+		p.reader = f
+	}
+
+	payload, next, err := readRecord(p.reader, p.offset, head.size)
+	if err != nil {
+		if !errors.Is(err, errCorruptRecord) {
+			// Opened again on the next attempt
+			_ = p.reader.Close()
+			p.reader = nil
+		}
+		return nil, pos, fmt.Errorf("segment %s at %d: %w", head.path, p.offset, err)
+	}
+	pos.next = next
+	record, err := decodeSpilledEvent(payload)
+	if err != nil {
+		return nil, pos, fmt.Errorf("segment %s at %d: %w: %v", head.path, p.offset, errCorruptRecord, err)
+	}
+	return record, pos, nil
+}
+
+// decodeSpilledEvent reads a record payload, the event alone when spilled before the terms were
+// recorded
+func decodeSpilledEvent(payload []byte) (*spilledEvent, error) {
+	var record spilledEvent
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return nil, err
+	}
+	if record.Event == nil {
+		record.Event = &domain.UserEvent{}
+		if err := json.Unmarshal(payload, record.Event); err != nil {
+			return nil, err
+		}
+	}
+	return &record, nil
+}
+
+// advance removes the drained record at pos, unless it has been dropped meanwhile
+func (p *UserSpillProducer) advance(pos position) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.segments) == 0 || p.segments[0].id != pos.segmentId || p.offset != pos.offset {
+		return
+	}
+	head := p.segments[0]
+	p.offset = pos.next
+	head.records--
+	p.records--
+	p.room.Broadcast()
+	if head.records == 0 {
+		p.removeHead()
+		return
+	}
+	if err := saveCursor(p.opts.Dir, head.id, p.offset); err != nil {
+		log.Warnf("failed to save spill cursor, the drained events may be sent again after a restart: %v", err)
+	}
+}
+
+// discard drops the unreadable record at pos or, when its length cannot be trusted, the rest of its segment
+func (p *UserSpillProducer) discard(pos position) {
+	if pos.next > 0 {
+		p.advance(pos)
+		p.mu.Lock()
+		p.dropped++
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.segments) == 0 || p.segments[0].id != pos.segmentId {
+		return
+	}
+	head := p.segments[0]
+	p.dropped += int64(head.records)
+	p.records -= head.records
+	p.removeHead()
+}
+
+// removeHead deletes the first segment, and saves the position of the next one
+func (p *UserSpillProducer) removeHead() {
+	head := p.segments[0]
+	if p.reader != nil {
+		_ = p.reader.Close()
+		p.reader = nil
+	}
+	p.segments = p.segments[1:]
+	if len(p.segments) == 0 && p.writer != nil {
+		_ = p.writer.Close()
+		p.writer = nil
+	}
+	p.offset = 0
+	p.bytes -= head.size
+	p.room.Broadcast()
+
+	nextId := p.nextId
+	if len(p.segments) > 0 {
+		nextId = p.segments[0].id
+	}
+	if err := saveCursor(p.opts.Dir, nextId, 0); err != nil {
+		log.Warnf("failed to save spill cursor, the drained events may be sent again after a restart: %v", err)
+	}
+	if err := os.Remove(head.path); err != nil {
+		log.Warnf("failed to remove spill segment %s: %v", head.path, err)
+	}
+}
+
+func (p *UserSpillProducer) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return Stats{Records: p.records, Bytes: p.bytes, Segments: len(p.segments), Dropped: p.dropped}
+}
+
+// Close releases the segment files and fails the events waiting for room. The events left in
+// the queue are drained after the next start.
+func (p *UserSpillProducer) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	if p.writer != nil {
+		_ = p.writer.Close()
+		p.writer = nil
+	}
+	if p.reader != nil {
+		_ = p.reader.Close()
+		p.reader = nil
+	}
+	p.room.Broadcast()
+}
@@ -0,0 +1,407 @@
+//go:build unit
+
+package spill_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/spill"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errBrokerDown = errors.New("all brokers down")
+	errRejected   = errors.New("message too large")
+)
+
+// fakeProducer fails with errBrokerDown while down, and with errRejected for the rejected events
+type fakeProducer struct {
+	mu       sync.Mutex
+	down     bool
+	rejected map[string]bool
+	sent     []string
+}
+
+func (p *fakeProducer) SendMessage(event *domain.UserEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.down {
+		return errBrokerDown
+	}
+	if p.rejected[event.Id] {
+		return errRejected
+	}
+	p.sent = append(p.sent, event.Id)
+	return nil
+}
+
+func (p *fakeProducer) setDown(down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.down = down
+}
+
+func (p *fakeProducer) sentIds() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.sent...)
+}
+
+func isBrokerDown(err error) bool {
+	return errors.Is(err, errBrokerDown)
+}
+
+func userEvent(i int) *domain.UserEvent {
+	return &domain.UserEvent{
+		Id:            fmt.Sprintf("event-%d", i),
+		UserId:        "user-1",
+		AfterChange:   &domain.User{ID: "user-1", Email: "john@example.com", Version: int64(i)},
+		OperationType: domain.OPERATION_UPDATE,
+		Sequence:      int64(i),
+		OccurredAt:    time.Date(2024, 6, 1, 10, 0, i, 0, time.UTC),
+	}
+}
+
+// recordSize is the size of a spilled userEvent, with its header
+func recordSize(t *testing.T) int64 {
+	payload, err := json.Marshal(map[string]any{"event": userEvent(0)})
+	require.NoError(t, err)
+	return int64(len(payload)) + 8
+}
+
+func eventIds(from, to int) []string {
+	var ids []string
+	for i := from; i < to; i++ {
+		ids = append(ids, fmt.Sprintf("event-%d", i))
+	}
+	return ids
+}
+
+// drain runs the producer until its queue is empty
+func drain(t *testing.T, producer *spill.UserSpillProducer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		producer.Run(ctx)
+	}()
+	require.Eventually(t, func() bool { return producer.Stats().Records == 0 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestUserSpillProducer_SpillAndDrain(t *testing.T) {
+	broker := &fakeProducer{}
+	producer, err := spill.NewUserSpillProducer(broker, spill.Options{
+		Dir:           t.TempDir(),
+		SegmentBytes:  2 * recordSize(t),
+		RetryInterval: 10 * time.Millisecond,
+		Spillable:     isBrokerDown,
+	})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	require.NoError(t, producer.SendMessage(userEvent(0)))
+	assert.Equal(t, spill.Stats{}, producer.Stats())
+
+	broker.setDown(true)
+	for i := 1; i < 6; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	broker.setDown(false)
+	// The queue is not empty, the next events are spilled after the others
+	for i := 6; i < 10; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	stats := producer.Stats()
+	assert.Equal(t, 9, stats.Records)
+	assert.Greater(t, stats.Segments, 1)
+
+	drain(t, producer)
+
+	assert.Equal(t, eventIds(0, 10), broker.sentIds())
+	assert.Equal(t, spill.Stats{}, producer.Stats())
+	require.NoError(t, producer.SendMessage(userEvent(10)))
+	assert.Equal(t, eventIds(0, 11), broker.sentIds())
+}
+
+func TestUserSpillProducer_Restart(t *testing.T) {
+	dir := t.TempDir()
+	broker := &fakeProducer{down: true}
+	opts := spill.Options{Dir: dir, SegmentBytes: 2 * recordSize(t), RetryInterval: 10 * time.Millisecond, Spillable: isBrokerDown}
+
+	producer, err := spill.NewUserSpillProducer(broker, opts)
+	require.NoError(t, err)
+	for i := 0; i < 8; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	// Drain a few events before stopping
+	broker.setDown(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		producer.Run(ctx)
+	}()
+	require.Eventually(t, func() bool { return len(broker.sentIds()) >= 3 }, 5*time.Second, time.Millisecond)
+	broker.setDown(true)
+	cancel()
+	<-stopped
+	producer.Close()
+
+	// A record cut short by a crash is discarded
+	segments, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	require.NoError(t, err)
+	last, err := os.OpenFile(segments[len(segments)-1], os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = last.Write([]byte{0, 0, 1, 0, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, last.Close())
+
+	drained := len(broker.sentIds())
+	broker.setDown(false)
+	producer, err = spill.NewUserSpillProducer(broker, opts)
+	require.NoError(t, err)
+	defer producer.Close()
+	// The last drained event may be sent again
+	assert.GreaterOrEqual(t, producer.Stats().Records, 8-drained)
+	assert.LessOrEqual(t, producer.Stats().Records, 8-drained+1)
+
+	drain(t, producer)
+
+	sent := broker.sentIds()
+	assert.Equal(t, eventIds(0, 8), compact(sent))
+}
+
+// compact removes the consecutive duplicates
+func compact(ids []string) []string {
+	var compacted []string
+	for _, id := range ids {
+		if len(compacted) == 0 || compacted[len(compacted)-1] != id {
+			compacted = append(compacted, id)
+		}
+	}
+	return compacted
+}
+
+func TestUserSpillProducer_ReadFailure(t *testing.T) {
+	dir := t.TempDir()
+	broker := &fakeProducer{down: true}
+	producer, err := spill.NewUserSpillProducer(broker, spill.Options{
+		Dir:           dir,
+		RetryInterval: 10 * time.Millisecond,
+		Spillable:     isBrokerDown,
+	})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	segments, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	// The segment cannot be opened for a while
+	require.NoError(t, os.Rename(segments[0], segments[0]+".moved"))
+
+	broker.setDown(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		producer.Run(ctx)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, broker.sentIds())
+	assert.Equal(t, 3, producer.Stats().Records)
+	assert.Zero(t, producer.Stats().Dropped)
+
+	require.NoError(t, os.Rename(segments[0]+".moved", segments[0]))
+	require.Eventually(t, func() bool { return producer.Stats().Records == 0 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-stopped
+	assert.Equal(t, eventIds(0, 3), broker.sentIds())
+}
+
+func TestUserSpillProducer_Overflow(t *testing.T) {
+	recordSize := recordSize(t)
+	tests := []struct {
+		name        string
+		policy      spill.OverflowPolicy
+		wantErr     error
+		wantSent    []string
+		wantDropped int64
+	}{
+		{
+			name:     "fail",
+			policy:   spill.OverflowFail,
+			wantErr:  spill.ErrSpillFull,
+			wantSent: eventIds(0, 4),
+		},
+		{
+			name:        "drop oldest",
+			policy:      spill.OverflowDropOldest,
+			wantSent:    eventIds(2, 5),
+			wantDropped: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := &fakeProducer{down: true}
+			producer, err := spill.NewUserSpillProducer(broker, spill.Options{
+				Dir:            t.TempDir(),
+				MaxBytes:       4 * recordSize,
+				SegmentBytes:   2 * recordSize,
+				OverflowPolicy: tt.policy,
+				RetryInterval:  10 * time.Millisecond,
+				Spillable:      isBrokerDown,
+			})
+			require.NoError(t, err)
+			defer producer.Close()
+
+			for i := 0; i < 4; i++ {
+				require.NoError(t, producer.SendMessage(userEvent(i)))
+			}
+			err = producer.SendMessage(userEvent(4))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantDropped, producer.Stats().Dropped)
+
+			broker.setDown(false)
+			drain(t, producer)
+			assert.Equal(t, tt.wantSent, broker.sentIds())
+		})
+	}
+}
+
+func TestUserSpillProducer_OverflowBlock(t *testing.T) {
+	broker := &fakeProducer{down: true}
+	producer, err := spill.NewUserSpillProducer(broker, spill.Options{
+		Dir:            t.TempDir(),
+		MaxBytes:       2 * recordSize(t),
+		OverflowPolicy: spill.OverflowBlock,
+		RetryInterval:  10 * time.Millisecond,
+		Spillable:      isBrokerDown,
+	})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	sent := make(chan error, 1)
+	go func() { sent <- producer.SendMessage(userEvent(2)) }()
+	select {
+	case <-sent:
+		t.Fatal("the event was spilled while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	broker.setDown(false)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go producer.Run(ctx)
+	select {
+	case err := <-sent:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for room in the queue")
+	}
+	require.Eventually(t, func() bool { return len(broker.sentIds()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, eventIds(0, 3), broker.sentIds())
+}
+
+func TestUserSpillProducer_DeadLetter(t *testing.T) {
+	broker := &fakeProducer{down: true, rejected: map[string]bool{"event-1": true}}
+	mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+	producer, err := spill.NewUserSpillProducer(broker, spill.Options{
+		Dir:           t.TempDir(),
+		RetryInterval: 10 * time.Millisecond,
+		Spillable:     isBrokerDown,
+		DeadLetters:   mockDeadLetterRepo,
+	})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	mockDeadLetterRepo.On("RecordDeadLetter", mock.Anything, mock.MatchedBy(func(event *domain.UserEvent) bool {
+		return event.Id == "event-1"
+	}), errRejected, mock.AnythingOfType("time.Time")).Return(nil)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	broker.setDown(false)
+	drain(t, producer)
+
+	assert.Equal(t, []string{"event-0", "event-2"}, broker.sentIds())
+	mockDeadLetterRepo.AssertExpectations(t)
+}
+
+func TestUserSpillProducer_StaleTerm(t *testing.T) {
+	broker := &fakeProducer{down: true}
+	mockDeadLetterRepo := new(mocks.MockUserDeadLetterRepository)
+	producer, err := spill.NewUserSpillProducer(broker, spill.Options{
+		Dir:           t.TempDir(),
+		RetryInterval: 10 * time.Millisecond,
+		Spillable:     isBrokerDown,
+		DeadLetters:   mockDeadLetterRepo,
+	})
+	require.NoError(t, err)
+	defer producer.Close()
+
+	mockDeadLetterRepo.On("RecordDeadLetter", mock.Anything, mock.MatchedBy(func(event *domain.UserEvent) bool {
+		return event.Id == "event-0" || event.Id == "event-1"
+	}), mock.MatchedBy(func(err error) bool {
+		return errors.Is(err, domain.ErrStaleFencingToken)
+	}), mock.AnythingOfType("time.Time")).Return(nil).Times(2)
+
+	// Spilled while leading in term 1, then while leading in term 2
+	ctx, cancel := context.WithCancel(domain.ContextWithFencingToken(context.Background(), 1))
+	<-startAndStop(producer, ctx, cancel)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+	ctx, cancel = context.WithCancel(domain.ContextWithFencingToken(context.Background(), 2))
+	<-startAndStop(producer, ctx, cancel)
+	for i := 2; i < 4; i++ {
+		require.NoError(t, producer.SendMessage(userEvent(i)))
+	}
+
+	broker.setDown(false)
+	ctx, cancel = context.WithCancel(domain.ContextWithFencingToken(context.Background(), 2))
+	done := producer.Start(ctx)
+	require.Eventually(t, func() bool { return producer.Stats().Records == 0 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Equal(t, eventIds(2, 4), broker.sentIds())
+	mockDeadLetterRepo.AssertExpectations(t)
+}
+
+// startAndStop starts the producer in the term of ctx, and stops it right away
+func startAndStop(producer *spill.UserSpillProducer, ctx context.Context, cancel context.CancelFunc) <-chan struct{} {
+	done := producer.Start(ctx)
+	cancel()
+	return done
+}
+
+func TestNewUserSpillProducer_UnknownPolicy(t *testing.T) {
+	_, err := spill.NewUserSpillProducer(&fakeProducer{}, spill.Options{Dir: t.TempDir(), OverflowPolicy: "drop-newest"})
+	assert.EqualError(t, err, `unknown spill overflow policy "drop-newest"`)
+}
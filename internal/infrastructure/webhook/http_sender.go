@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/mapper"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// SubscriptionIdHeader is the id of the webhook subscription the event is delivered to
+	SubscriptionIdHeader = "X-Webhook-Id"
+	// EventIdHeader is the id of the delivered event, the same for every attempt so that the
+	// endpoints can ignore the events they already received
+	EventIdHeader = "X-Webhook-Event-Id"
+	// TimestampHeader is the Unix time the payload was signed at, for the endpoints to reject replays
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader is the signature of the payload, see Sign
+	SignatureHeader = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	userAgent       = "go-ddd-crud-webhooks"
+	// maxDrainedBody is the size of the response body read to reuse the connection
+	maxDrainedBody = 64 << 10
+)
+
+// HTTPSender posts the user events to the webhook endpoints as the JSON of a UserEvent, named
+// as by the REST endpoints, signed with the secret of the subscription
+type HTTPSender struct {
+	client    *http.Client
+	marshaler protojson.MarshalOptions
+}
+
+// NewHTTPSender creates a sender using client, whose timeout bounds every attempt
+func NewHTTPSender(client *http.Client) *HTTPSender {
+	return &HTTPSender{
+		client:    client,
+		marshaler: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+	}
+}
+
+func (s *HTTPSender) SendWebhook(ctx context.Context, subscription *domain.WebhookSubscription, event *domain.UserEvent) (int, error) {
+	body, err := s.marshaler.Marshal(mapper.UserEventToProto(event))
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode user event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(SubscriptionIdHeader, subscription.Id)
+	req.Header.Set(EventIdHeader, event.Id)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, signaturePrefix+Sign(subscription.Secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxDrainedBody))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of the timestamp and the body joined by a dot, keyed by the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
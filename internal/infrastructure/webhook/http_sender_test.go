@@ -0,0 +1,117 @@
+//go:build unit
+
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/infrastructure/webhook"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestHTTPSender_SendWebhook(t *testing.T) {
+	event := &domain.UserEvent{Id: "event-1", UserId: "user-123", OperationType: domain.OPERATION_UPDATE,
+		AfterChange: &domain.User{ID: "user-123", Country: "IT", HashedPassword: "hash", Version: 2}, OccurredAt: time.Now()}
+
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "delivered", status: http.StatusNoContent, wantStatus: http.StatusNoContent},
+		{name: "rejected", status: http.StatusServiceUnavailable, wantStatus: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan *http.Request, 1)
+			bodies := make(chan []byte, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- r
+				bodies <- body
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			sender := webhook.NewHTTPSender(server.Client())
+			subscription := &domain.WebhookSubscription{Id: "webhook-1", Url: server.URL, Secret: "secret"}
+			statusCode, err := sender.SendWebhook(context.TODO(), subscription, event)
+			assert.Equal(t, tt.wantStatus, statusCode)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			req, body := <-requests, <-bodies
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+			assert.Equal(t, "webhook-1", req.Header.Get(webhook.SubscriptionIdHeader))
+			assert.Equal(t, "event-1", req.Header.Get(webhook.EventIdHeader))
+
+			timestamp, err := strconv.ParseInt(req.Header.Get(webhook.TimestampHeader), 10, 64)
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), time.Minute)
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(req.Header.Get(webhook.TimestampHeader) + "."))
+			mac.Write(body)
+			assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get(webhook.SignatureHeader))
+
+			payload := &pb.UserEvent{}
+			require.NoError(t, protojson.Unmarshal(body, payload))
+			assert.Equal(t, "event-1", payload.Id)
+			assert.Equal(t, pb.OperationType_OPERATION_UPDATE, payload.OperationType)
+			assert.Equal(t, "IT", payload.AfterChange.Country)
+			assert.Contains(t, string(body), `"user_id"`)
+			assert.NotContains(t, string(body), "hash")
+		})
+	}
+}
+
+func TestHTTPSender_SendWebhook_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	sender := webhook.NewHTTPSender(&http.Client{Timeout: time.Second})
+	statusCode, err := sender.SendWebhook(context.TODO(), &domain.WebhookSubscription{Url: server.URL},
+		&domain.UserEvent{Id: "event-1"})
+	assert.Equal(t, 0, statusCode)
+	assert.Error(t, err)
+}
+
+func TestNewClient_InternalAddress(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	sender := webhook.NewHTTPSender(webhook.NewClient(time.Second))
+	statusCode, err := sender.SendWebhook(context.TODO(), &domain.WebhookSubscription{Url: server.URL},
+		&domain.UserEvent{Id: "event-1"})
+	assert.Equal(t, 0, statusCode)
+	assert.ErrorIs(t, err, webhook.ErrInternalAddress)
+	assert.Zero(t, requests.Load())
+}
+
+func TestSign(t *testing.T) {
+	// Computed with: printf '1700000000.{"id":"event-1"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "01017e2b3bf7b2f3c53c64a662fb4ee9c60a8a998e81d1b19dcfd0aa2de23880",
+		webhook.Sign("secret", 1700000000, []byte(`{"id":"event-1"}`)))
+}
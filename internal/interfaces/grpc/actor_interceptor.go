@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AuthorizationMetadataKey carries the bearer token of the caller
+	AuthorizationMetadataKey = "authorization"
+	RequestIdMetadataKey     = "x-request-id"
+	// TraceParentMetadataKey is the W3C trace context header
+	TraceParentMetadataKey = "traceparent"
+)
+
+// ActorUnaryInterceptor extracts the caller identity, the request id and the trace context from the
+// incoming metadata and stores them in the request context for the domain layer.
+// The identity is the one of the bearer token of the authorization metadata, verified by
+// verifier: a request without token is anonymous, and one with an invalid token is rejected.
+// Without verifier, every request is anonymous.
+// A request id is generated when the caller does not provide one, and it is always
+// echoed back in the response headers.
+func ActorUnaryInterceptor(verifier domain.ActorTokenVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, requestId := actorContext(ctx)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIdMetadataKey, requestId)); err != nil {
+			log.Debugf("unable to set request id header: %v", err)
+		}
+
+		ctx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ActorStreamInterceptor is the ActorUnaryInterceptor of the streaming RPCs
+func ActorStreamInterceptor(verifier domain.ActorTokenVerifier) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestId := actorContext(ss.Context())
+		if err := ss.SetHeader(metadata.Pairs(RequestIdMetadataKey, requestId)); err != nil {
+			log.Debugf("unable to set request id header: %v", err)
+		}
+
+		ctx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &actorServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// actorServerStream is a ServerStream whose context carries the caller identity
+type actorServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *actorServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// actorContext stores the request id and the trace context in ctx, and returns the request id
+func actorContext(ctx context.Context) (context.Context, string) {
+	requestId := requestIdFromMetadata(ctx)
+	if requestId == "" {
+		requestId = uuid.NewString()
+	}
+	ctx = domain.ContextWithCorrelationId(ctx, requestId)
+	if traceParent := metadataValue(ctx, TraceParentMetadataKey); traceParent != "" {
+		ctx = domain.ContextWithTraceParent(ctx, traceParent)
+	}
+	return ctx, requestId
+}
+
+// authenticate stores the caller identity in ctx, unchanged when the request is anonymous
+func authenticate(ctx context.Context, verifier domain.ActorTokenVerifier) (context.Context, error) {
+	actor, err := actorFromMetadata(ctx, verifier)
+	if err != nil {
+		log.Warnf("Rejecting request %s: %v", domain.CorrelationIdFromContext(ctx), err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if actor != nil {
+		ctx = domain.ContextWithActor(ctx, actor)
+	}
+	return ctx, nil
+}
+
+func actorFromMetadata(ctx context.Context, verifier domain.ActorTokenVerifier) (*domain.Actor, error) {
+	if verifier == nil {
+		return nil, nil
+	}
+	authorization := metadataValue(ctx, AuthorizationMetadataKey)
+	if authorization == "" {
+		return nil, nil
+	}
+	token, ok := domain.BearerToken(authorization)
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	return verifier.VerifyToken(token)
+}
+
+func requestIdFromMetadata(ctx context.Context) string {
+	return metadataValue(ctx, RequestIdMetadataKey)
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return firstMetadataValue(md, key)
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
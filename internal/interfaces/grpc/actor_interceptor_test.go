@@ -0,0 +1,222 @@
+//go:build unit
+
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeVerifier authenticates the tokens it knows
+type fakeVerifier map[string]*domain.Actor
+
+func (v fakeVerifier) VerifyToken(token string) (*domain.Actor, error) {
+	if actor, ok := v[token]; ok {
+		return actor, nil
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+var verifier = fakeVerifier{
+	"admin-token": {ID: "admin-1", Role: "admin"},
+	"user-token":  {ID: "user-123"},
+}
+
+func TestActorUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name        string
+		md          metadata.MD
+		wantedActor *domain.Actor
+		wantedCode  codes.Code
+	}{
+		{
+			name:        "actor with role",
+			md:          metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Bearer admin-token"),
+			wantedActor: &domain.Actor{ID: "admin-1", Role: "admin"},
+		},
+		{
+			name:        "actor without role",
+			md:          metadata.Pairs(grpcServer.AuthorizationMetadataKey, "bearer user-token"),
+			wantedActor: &domain.Actor{ID: "user-123"},
+		},
+		{
+			name:       "invalid token",
+			md:         metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Bearer forged-token"),
+			wantedCode: codes.Unauthenticated,
+		},
+		{
+			name:       "other scheme",
+			md:         metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Basic YWRtaW46YWRtaW4="),
+			wantedCode: codes.Unauthenticated,
+		},
+		{
+			name:        "former identity headers",
+			md:          metadata.Pairs("x-actor-id", "admin-1", "x-actor-role", "admin"),
+			wantedActor: nil,
+		},
+		{
+			name:        "no metadata",
+			md:          nil,
+			wantedActor: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var gotActor *domain.Actor
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				gotActor = domain.ActorFromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := grpcServer.ActorUnaryInterceptor(verifier)(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if tt.wantedCode != codes.OK {
+				assert.Equal(t, tt.wantedCode, status.Code(err))
+				assert.False(t, called)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantedActor, gotActor)
+		})
+	}
+}
+
+func TestActorUnaryInterceptor_RequestId(t *testing.T) {
+	tests := []struct {
+		name            string
+		md              metadata.MD
+		wantedRequestId string
+	}{
+		{
+			name:            "request id from metadata",
+			md:              metadata.Pairs(grpcServer.RequestIdMetadataKey, "request-1"),
+			wantedRequestId: "request-1",
+		},
+		{
+			name:            "generated request id",
+			md:              metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Bearer user-token"),
+			wantedRequestId: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.TODO(), tt.md)
+
+			var gotRequestId string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotRequestId = domain.CorrelationIdFromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := grpcServer.ActorUnaryInterceptor(verifier)(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			assert.NoError(t, err)
+			if tt.wantedRequestId != "" {
+				assert.Equal(t, tt.wantedRequestId, gotRequestId)
+			} else {
+				assert.NoError(t, uuid.Validate(gotRequestId))
+			}
+		})
+	}
+}
+
+func TestActorUnaryInterceptor_TraceParent(t *testing.T) {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name              string
+		md                metadata.MD
+		wantedTraceParent string
+	}{
+		{
+			name:              "trace context from metadata",
+			md:                metadata.Pairs(grpcServer.TraceParentMetadataKey, traceParent),
+			wantedTraceParent: traceParent,
+		},
+		{
+			name:              "no trace context",
+			md:                metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Bearer user-token"),
+			wantedTraceParent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.TODO(), tt.md)
+
+			var gotTraceParent string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotTraceParent = domain.TraceParentFromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := grpcServer.ActorUnaryInterceptor(verifier)(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantedTraceParent, gotTraceParent)
+		})
+	}
+}
+
+// headerServerStream is a server stream recording the headers set by the interceptor
+type headerServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *headerServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *headerServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestActorStreamInterceptor(t *testing.T) {
+	md := metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Bearer admin-token",
+		grpcServer.RequestIdMetadataKey, "request-1")
+	stream := &headerServerStream{ctx: metadata.NewIncomingContext(context.TODO(), md)}
+
+	var gotActor *domain.Actor
+	var gotCorrelationId string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		gotActor = domain.ActorFromContext(ss.Context())
+		gotCorrelationId = domain.CorrelationIdFromContext(ss.Context())
+		return nil
+	}
+
+	err := grpcServer.ActorStreamInterceptor(verifier)(nil, stream, &grpc.StreamServerInfo{}, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Actor{ID: "admin-1", Role: "admin"}, gotActor)
+	assert.Equal(t, "request-1", gotCorrelationId)
+	assert.Equal(t, []string{"request-1"}, stream.header.Get(grpcServer.RequestIdMetadataKey))
+}
+
+func TestActorStreamInterceptor_InvalidToken(t *testing.T) {
+	md := metadata.Pairs(grpcServer.AuthorizationMetadataKey, "Bearer forged-token")
+	stream := &headerServerStream{ctx: metadata.NewIncomingContext(context.TODO(), md)}
+
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		t.Fatal("the stream was handled with an invalid token")
+		return nil
+	}
+
+	err := grpcServer.ActorStreamInterceptor(verifier)(nil, stream, &grpc.StreamServerInfo{}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
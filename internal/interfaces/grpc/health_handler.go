@@ -2,17 +2,57 @@ package grpc
 
 import (
 	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
 	pb "github.com/flapenna/go-ddd-crud/pkg/pb/health/v1"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type HealthServiceServer struct {
 	pb.UnimplementedHealthServiceServer
+	watcher domain.UserWatcherStatusReporter
+	leader  domain.LeaderStatusReporter
 }
 
-func NewHealthServiceServer() *HealthServiceServer {
-	return &HealthServiceServer{}
+// NewHealthServiceServer creates the health server, watcher may be nil when the user
+// watcher does not report its state and leader when it is not leader elected
+func NewHealthServiceServer(watcher domain.UserWatcherStatusReporter, leader domain.LeaderStatusReporter) *HealthServiceServer {
+	return &HealthServiceServer{watcher: watcher, leader: leader}
 }
 func (s *HealthServiceServer) Health(context.Context, *emptypb.Empty) (*pb.HealthResponse, error) {
-	return &pb.HealthResponse{Status: "OK"}, nil
+	res := &pb.HealthResponse{Status: "OK"}
+	if s.watcher != nil {
+		watcherStatus := s.watcher.WatcherStatus()
+		res.Status = healthStatus(watcherStatus.State)
+		res.Watcher = &pb.WatcherStatus{
+			State:     string(watcherStatus.State),
+			Attempts:  int32(watcherStatus.Attempts),
+			LastError: watcherStatus.LastError,
+		}
+	}
+	// A standby is healthy whatever the state of its watcher: the watcher only starts once elected,
+	// and stops when a leader is deposed
+	if s.leader != nil {
+		leaderStatus := s.leader.LeaderStatus()
+		res.Leader = &pb.LeaderStatus{
+			State:        string(leaderStatus.State),
+			InstanceId:   leaderStatus.InstanceId,
+			FencingToken: leaderStatus.FencingToken,
+			LastError:    leaderStatus.LastError,
+		}
+		if leaderStatus.State == domain.LeaderStateStandby {
+			res.Status = "OK"
+		}
+	}
+	return res, nil
+}
+
+func healthStatus(state domain.WatcherState) string {
+	switch state {
+	case domain.WatcherStateRetrying:
+		return "DEGRADED"
+	case domain.WatcherStateFailed, domain.WatcherStateStopped:
+		return "UNHEALTHY"
+	default:
+		return "OK"
+	}
 }
@@ -4,15 +4,29 @@ package grpc_test
 
 import (
 	"context"
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
 	"github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
 	pb "github.com/flapenna/go-ddd-crud/pkg/pb/health/v1"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"testing"
 )
 
+type watcherStatusStub domain.WatcherStatus
+
+func (s watcherStatusStub) WatcherStatus() domain.WatcherStatus {
+	return domain.WatcherStatus(s)
+}
+
+type leaderStatusStub domain.LeaderStatus
+
+func (s leaderStatusStub) LeaderStatus() domain.LeaderStatus {
+	return domain.LeaderStatus(s)
+}
+
 func TestHealthServiceServer_Health(t *testing.T) {
-	server := grpc.NewHealthServiceServer()
+	server := grpc.NewHealthServiceServer(nil, nil)
 	ctx := context.TODO()
 
 	resp, err := server.Health(ctx, &emptypb.Empty{})
@@ -20,3 +34,108 @@ func TestHealthServiceServer_Health(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, &pb.HealthResponse{Status: "OK"}, resp)
 }
+
+func TestHealthServiceServer_Health_Watcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   domain.WatcherStatus
+		expected *pb.HealthResponse
+	}{
+		{
+			name:   "Running",
+			status: domain.WatcherStatus{State: domain.WatcherStateRunning},
+			expected: &pb.HealthResponse{
+				Status:  "OK",
+				Watcher: &pb.WatcherStatus{State: "running"},
+			},
+		},
+		{
+			name:   "Retrying",
+			status: domain.WatcherStatus{State: domain.WatcherStateRetrying, Attempts: 2, LastError: "connection refused"},
+			expected: &pb.HealthResponse{
+				Status:  "DEGRADED",
+				Watcher: &pb.WatcherStatus{State: "retrying", Attempts: 2, LastError: "connection refused"},
+			},
+		},
+		{
+			name:   "Failed",
+			status: domain.WatcherStatus{State: domain.WatcherStateFailed, Attempts: 10, LastError: "connection refused"},
+			expected: &pb.HealthResponse{
+				Status:  "UNHEALTHY",
+				Watcher: &pb.WatcherStatus{State: "failed", Attempts: 10, LastError: "connection refused"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := grpc.NewHealthServiceServer(watcherStatusStub(tt.status), nil)
+
+			resp, err := server.Health(context.TODO(), &emptypb.Empty{})
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(tt.expected, resp), "expected %v, got %v", tt.expected, resp)
+		})
+	}
+}
+
+func TestHealthServiceServer_Health_Leader(t *testing.T) {
+	tests := []struct {
+		name     string
+		watcher  domain.WatcherStatus
+		leader   domain.LeaderStatus
+		expected *pb.HealthResponse
+	}{
+		{
+			name:    "Leader",
+			watcher: domain.WatcherStatus{State: domain.WatcherStateRunning},
+			leader:  domain.LeaderStatus{State: domain.LeaderStateLeader, InstanceId: "instance-1", FencingToken: 4},
+			expected: &pb.HealthResponse{
+				Status:  "OK",
+				Watcher: &pb.WatcherStatus{State: "running"},
+				Leader:  &pb.LeaderStatus{State: "leader", InstanceId: "instance-1", FencingToken: 4},
+			},
+		},
+		{
+			name:    "Standby",
+			watcher: domain.WatcherStatus{State: domain.WatcherStateStarting},
+			leader:  domain.LeaderStatus{State: domain.LeaderStateStandby, InstanceId: "instance-2", LastError: "connection refused"},
+			expected: &pb.HealthResponse{
+				Status:  "OK",
+				Watcher: &pb.WatcherStatus{State: "starting"},
+				Leader:  &pb.LeaderStatus{State: "standby", InstanceId: "instance-2", LastError: "connection refused"},
+			},
+		},
+		{
+			name:    "Deposed leader",
+			watcher: domain.WatcherStatus{State: domain.WatcherStateStopped},
+			leader:  domain.LeaderStatus{State: domain.LeaderStateStandby, InstanceId: "instance-1"},
+			expected: &pb.HealthResponse{
+				Status:  "OK",
+				Watcher: &pb.WatcherStatus{State: "stopped"},
+				Leader:  &pb.LeaderStatus{State: "standby", InstanceId: "instance-1"},
+			},
+		},
+		{
+			name:    "Leader with stopped watcher",
+			watcher: domain.WatcherStatus{State: domain.WatcherStateStopped},
+			leader:  domain.LeaderStatus{State: domain.LeaderStateLeader, InstanceId: "instance-1", FencingToken: 4},
+			expected: &pb.HealthResponse{
+				Status:  "UNHEALTHY",
+				Watcher: &pb.WatcherStatus{State: "stopped"},
+				Leader:  &pb.LeaderStatus{State: "leader", InstanceId: "instance-1", FencingToken: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := grpc.NewHealthServiceServer(watcherStatusStub(tt.watcher), leaderStatusStub(tt.leader))
+
+			resp, err := server.Health(context.TODO(), &emptypb.Empty{})
+
+			assert.Nil(t, err)
+			assert.True(t, proto.Equal(tt.expected, resp), "expected %v, got %v", tt.expected, resp)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"errors"
 	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/mapper"
 	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
@@ -10,6 +11,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
 )
 
 import (
@@ -53,7 +55,7 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
 
-	return userToProto(createdUser), nil
+	return mapper.UserToProto(createdUser), nil
 }
 
 func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
@@ -70,6 +72,7 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 		Email:     req.Email,
 		Country:   req.Country,
 		Nickname:  req.Nickname,
+		Version:   req.GetExpectedVersion(),
 	}
 
 	updatedUser, err := s.userService.UpdateUser(ctx, user)
@@ -78,11 +81,15 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 			log.Warn("trying to update user that doesn't exist")
 			return nil, status.Errorf(codes.NotFound, err.Error())
 		}
+		if errors.Is(err, domain.ErrVersionConflict) {
+			log.Warn("trying to update user with a stale version")
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
 		log.Errorf("failed to update user: %v", err)
 		return nil, status.Errorf(codes.Internal, "internal server error")
 	}
 
-	return userToProto(updatedUser), nil
+	return mapper.UserToProto(updatedUser), nil
 }
 
 func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
@@ -130,7 +137,7 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 
 	users := make([]*pb.User, len(res.Results))
 	for i, u := range res.Results {
-		users[i] = userToProto(u)
+		users[i] = mapper.UserToProto(u)
 	}
 	listUsersResponse := &pb.ListUsersResponse{
 		Page:       res.Page,
@@ -141,18 +148,393 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 	return listUsersResponse, nil
 }
 
-func userToProto(user *domain.User) *pb.User {
-	if user == nil {
-		return nil
+func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	log.Infof("[GRPC] GetUser called with id %s", req.Id)
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate get user request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var asOf *time.Time
+	if req.AsOf != nil {
+		t := req.AsOf.AsTime()
+		asOf = &t
+	}
+
+	user, err := s.userService.GetUser(ctx, req.Id, asOf)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		case errors.Is(err, domain.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		log.Errorf("failed to get user: %v", err)
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	return mapper.UserToProto(user), nil
+}
+
+func (s *UserServiceServer) ListUserVersions(ctx context.Context, req *pb.ListUserVersionsRequest) (*pb.ListUserVersionsResponse, error) {
+	log.Infof("[GRPC] ListUserVersions called with id %s", req.Id)
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate list user versions request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, err := s.userService.ListUserVersions(ctx, &domain.ListUserVersionsQueryRequest{
+		UserId:   req.Id,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		log.Errorf("failed to list user versions: %v", err)
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	versions := make([]*pb.UserVersion, len(res.Results))
+	for i, v := range res.Results {
+		versions[i] = versionToProto(v)
+	}
+	return &pb.ListUserVersionsResponse{
+		Page:       res.Page,
+		PageSize:   res.PageSize,
+		TotalCount: res.TotalCount,
+		Results:    versions,
+	}, nil
+}
+
+func (s *UserServiceServer) RevertUser(ctx context.Context, req *pb.RevertUserRequest) (*pb.User, error) {
+	log.Infof("[GRPC] RevertUser called with id %s and version %d", req.Id, req.Version)
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate revert user request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	user, err := s.userService.RevertUser(ctx, req.Id, req.Version, req.ExpectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrUserVersionNotFound):
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		case errors.Is(err, domain.ErrVersionConflict):
+			log.Warn("trying to revert user with a stale version")
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+		log.Errorf("failed to revert user: %v", err)
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	return mapper.UserToProto(user), nil
+}
+
+func (s *UserServiceServer) ExportMyData(ctx context.Context, req *pb.ExportMyDataRequest) (*pb.ExportMyDataResponse, error) {
+	log.Infof("[GRPC] ExportMyData called with id %s", req.Id)
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate export my data request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	return &pb.User{
-		Id:        user.ID,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
-		Country:   user.Country,
-		Nickname:  user.Nickname,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-		UpdatedAt: timestamppb.New(user.UpdatedAt),
+
+	export, err := s.userService.ExportUserData(ctx, req.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		case errors.Is(err, domain.ErrUserNotFound):
+			log.Warn("trying to export data of user that doesn't exist")
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		log.Errorf("failed to export user data: %v", err)
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	auditEntries := make([]*pb.UserAuditEntry, len(export.AuditEntries))
+	for i, e := range export.AuditEntries {
+		auditEntries[i] = auditEntryToProto(e)
+	}
+	versions := make([]*pb.UserVersion, len(export.Versions))
+	for i, v := range export.Versions {
+		versions[i] = versionToProto(v)
+	}
+	return &pb.ExportMyDataResponse{
+		Profile:      mapper.UserToProto(export.User),
+		ExportedAt:   timestamppb.New(export.ExportedAt),
+		AuditEntries: auditEntries,
+		Versions:     versions,
+		Deleted:      export.Deleted,
+	}, nil
+}
+
+func (s *UserServiceServer) ListUserAuditEntries(ctx context.Context, req *pb.ListUserAuditEntriesRequest) (*pb.ListUserAuditEntriesResponse, error) {
+	log.Infof("[GRPC] ListUserAuditEntries called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate list user audit entries request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	listAuditEntriesRequest := &domain.ListUserAuditEntriesQueryRequest{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		UserId:     req.UserId,
+		ModifiedBy: req.ModifiedBy,
+	}
+	if req.From != nil {
+		from := req.From.AsTime()
+		listAuditEntriesRequest.From = &from
+	}
+	if req.To != nil {
+		to := req.To.AsTime()
+		listAuditEntriesRequest.To = &to
+	}
+
+	res, err := s.userService.ListUserAuditEntries(ctx, listAuditEntriesRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		log.Errorf("failed to list user audit entries: %v", err)
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	entries := make([]*pb.UserAuditEntry, len(res.Results))
+	for i, e := range res.Results {
+		entries[i] = auditEntryToProto(e)
+	}
+	return &pb.ListUserAuditEntriesResponse{
+		Page:       res.Page,
+		PageSize:   res.PageSize,
+		TotalCount: res.TotalCount,
+		Results:    entries,
+	}, nil
+}
+
+func (s *UserServiceServer) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	log.Infof("[GRPC] ListDeadLetters called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate list dead letters request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, err := s.userService.ListDeadLetters(ctx, &domain.ListDeadLettersQueryRequest{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnauthenticated):
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		case errors.Is(err, domain.ErrPermissionDenied):
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		log.Errorf("failed to list dead letters: %v", err)
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+
+	deadLetters := make([]*pb.DeadLetter, len(res.Results))
+	for i, d := range res.Results {
+		deadLetters[i] = deadLetterToProto(d)
+	}
+	return &pb.ListDeadLettersResponse{
+		Page:       res.Page,
+		PageSize:   res.PageSize,
+		TotalCount: res.TotalCount,
+		Results:    deadLetters,
+	}, nil
+}
+
+func (s *UserServiceServer) GetDeadLetter(ctx context.Context, req *pb.GetDeadLetterRequest) (*pb.DeadLetter, error) {
+	log.Infof("[GRPC] GetDeadLetter called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate get dead letter request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	deadLetter, err := s.userService.GetDeadLetter(ctx, req.Id)
+	if err != nil {
+		return nil, deadLetterError("get dead letter", err)
+	}
+	return deadLetterToProto(deadLetter), nil
+}
+
+func (s *UserServiceServer) RedriveDeadLetter(ctx context.Context, req *pb.RedriveDeadLetterRequest) (*emptypb.Empty, error) {
+	log.Infof("[GRPC] RedriveDeadLetter called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate redrive dead letter request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.userService.RedriveDeadLetter(ctx, req.Id); err != nil {
+		return nil, deadLetterError("redrive dead letter", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServiceServer) DiscardDeadLetter(ctx context.Context, req *pb.DiscardDeadLetterRequest) (*emptypb.Empty, error) {
+	log.Infof("[GRPC] DiscardDeadLetter called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate discard dead letter request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.userService.DiscardDeadLetter(ctx, req.Id); err != nil {
+		return nil, deadLetterError("discard dead letter", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserServiceServer) StartUserReplay(ctx context.Context, req *pb.StartUserReplayRequest) (*pb.UserReplay, error) {
+	log.Infof("[GRPC] StartUserReplay called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate start user replay request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	replay, err := s.userService.StartUserReplay(ctx, &domain.StartUserReplayRequest{
+		Id:            req.Id,
+		Country:       req.Country,
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		Nickname:      req.Nickname,
+		Email:         req.Email,
+		RatePerSecond: req.RatePerSecond,
+	})
+	if err != nil {
+		return nil, userReplayError("start user replay", err)
+	}
+	return userReplayToProto(replay), nil
+}
+
+func (s *UserServiceServer) GetUserReplay(ctx context.Context, req *pb.GetUserReplayRequest) (*pb.UserReplay, error) {
+	log.Infof("[GRPC] GetUserReplay called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate get user replay request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	replay, err := s.userService.GetUserReplay(ctx, req.Id)
+	if err != nil {
+		return nil, userReplayError("get user replay", err)
+	}
+	return userReplayToProto(replay), nil
+}
+
+// userReplayError maps the errors of the user replay operations to gRPC statuses
+func userReplayError(operation string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrUserReplayNotFound):
+		return status.Errorf(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrUserReplayRunning), errors.Is(err, domain.ErrUserReplayCompleted):
+		return status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+	log.Errorf("failed to %s: %v", operation, err)
+	return status.Errorf(codes.Internal, "internal server error")
+}
+
+// deadLetterError maps the errors of the dead letter operations to gRPC statuses
+func deadLetterError(operation string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrDeadLetterNotFound):
+		return status.Errorf(codes.NotFound, err.Error())
+	}
+	log.Errorf("failed to %s: %v", operation, err)
+	return status.Errorf(codes.Internal, "internal server error")
+}
+
+func versionToProto(version *domain.UserVersion) *pb.UserVersion {
+	return &pb.UserVersion{
+		Version:       version.Version,
+		User:          mapper.UserToProto(version.User),
+		Deleted:       version.Deleted,
+		ValidFrom:     timestamppb.New(version.ValidFrom),
+		ModifiedBy:    version.ModifiedBy,
+		CorrelationId: version.CorrelationId,
+	}
+}
+
+func auditEntryToProto(entry *domain.UserAuditEntry) *pb.UserAuditEntry {
+	return &pb.UserAuditEntry{
+		Id:            entry.Id,
+		UserId:        entry.UserId,
+		BeforeChange:  mapper.UserToProto(entry.BeforeChange),
+		AfterChange:   mapper.UserToProto(entry.AfterChange),
+		OperationType: mapper.UserAuditActionToProto(entry.OperationType),
+		ModifiedBy:    entry.ModifiedBy,
+		CorrelationId: entry.CorrelationId,
+		RecordedAt:    timestamppb.New(entry.RecordedAt),
+	}
+}
+
+func deadLetterToProto(deadLetter *domain.DeadLetter) *pb.DeadLetter {
+	return &pb.DeadLetter{
+		Id:            deadLetter.Id,
+		UserId:        deadLetter.Event.UserId,
+		BeforeChange:  mapper.UserToProto(deadLetter.Event.BeforeChange),
+		AfterChange:   mapper.UserToProto(deadLetter.Event.AfterChange),
+		OperationType: mapper.UserAuditActionToProto(deadLetter.Event.OperationType),
+		ModifiedBy:    deadLetter.Event.ModifiedBy,
+		CorrelationId: deadLetter.Event.CorrelationId,
+		Error:         deadLetter.Error,
+		Attempts:      deadLetter.Attempts,
+		FirstFailedAt: timestamppb.New(deadLetter.FirstFailedAt),
+		LastFailedAt:  timestamppb.New(deadLetter.LastFailedAt),
+	}
+}
+
+func userReplayToProto(replay *domain.UserReplay) *pb.UserReplay {
+	return &pb.UserReplay{
+		Id:            replay.Id,
+		State:         userReplayStateToProto(replay.State),
+		Country:       replay.Query.Country,
+		FirstName:     replay.Query.FirstName,
+		LastName:      replay.Query.LastName,
+		Nickname:      replay.Query.Nickname,
+		Email:         replay.Query.Email,
+		RatePerSecond: replay.RatePerSecond,
+		LastUserId:    replay.Query.AfterId,
+		ReplayedCount: replay.ReplayedCount,
+		Error:         replay.Error,
+		StartedBy:     replay.StartedBy,
+		StartedAt:     timestamppb.New(replay.StartedAt),
+		UpdatedAt:     timestamppb.New(replay.UpdatedAt),
+	}
+}
+
+func userReplayStateToProto(state domain.UserReplayState) pb.UserReplayState {
+	switch state {
+	case domain.UserReplayStatePending:
+		return pb.UserReplayState_USER_REPLAY_STATE_PENDING
+	case domain.UserReplayStateRunning:
+		return pb.UserReplayState_USER_REPLAY_STATE_RUNNING
+	case domain.UserReplayStateCompleted:
+		return pb.UserReplayState_USER_REPLAY_STATE_COMPLETED
+	case domain.UserReplayStateFailed:
+		return pb.UserReplayState_USER_REPLAY_STATE_FAILED
+	default:
+		return pb.UserReplayState_USER_REPLAY_STATE_UNSPECIFIED
 	}
 }
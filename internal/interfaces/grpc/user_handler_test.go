@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestUserServiceServer_CreateUser(t *testing.T) {
@@ -208,6 +209,22 @@ func TestUserServiceServer_UpdateUser(t *testing.T) {
 			mockError:    errors.New("service error"),
 			wantedErr:    status.Error(codes.Internal, "internal server error"),
 		},
+		{
+			name: "version conflict",
+			req: &pb.UpdateUserRequest{
+				Id:              userId,
+				FirstName:       "Federico",
+				LastName:        "La Penna",
+				Email:           "flapenna@email.com",
+				Country:         "IT",
+				Nickname:        "Pennino",
+				ExpectedVersion: proto.Int64(2),
+			},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrVersionConflict,
+			wantedErr:    status.Error(codes.Aborted, domain.ErrVersionConflict.Error()),
+		},
 		{
 			name: "validation error",
 			req: &pb.UpdateUserRequest{
@@ -415,3 +432,683 @@ func TestUserServiceServer_ListUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestUserServiceServer_ExportMyData(t *testing.T) {
+	userId := uuid.NewString()
+	now := time.Now()
+	tests := []struct {
+		name         string
+		req          *pb.ExportMyDataRequest
+		mockResponse *domain.UserDataExport
+		wantedRes    *pb.ExportMyDataResponse
+		mockError    error
+		wantedErr    error
+	}{
+		{
+			name: "successful export of a deleted user",
+			req:  &pb.ExportMyDataRequest{Id: userId},
+			mockResponse: &domain.UserDataExport{
+				User: &domain.User{
+					ID:        userId,
+					FirstName: "Federico",
+					LastName:  "La Penna",
+					Email:     "flapenna@email.com",
+					Country:   "IT",
+					Nickname:  "Pennino",
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+				AuditEntries: []*domain.UserAuditEntry{
+					{
+						Id:            "event-1",
+						UserId:        userId,
+						AfterChange:   &domain.User{ID: userId, CreatedAt: now, UpdatedAt: now},
+						OperationType: domain.OPERATION_CREATE,
+						ModifiedBy:    userId,
+						CorrelationId: "request-1",
+						RecordedAt:    now,
+					},
+				},
+				Versions: []*domain.UserVersion{
+					{UserId: userId, Version: 2, Deleted: true, ValidFrom: now, ModifiedBy: "admin-1", CorrelationId: "request-2"},
+				},
+				Deleted:    true,
+				ExportedAt: now,
+			},
+			wantedRes: &pb.ExportMyDataResponse{
+				Profile: &pb.User{
+					Id:        userId,
+					FirstName: "Federico",
+					LastName:  "La Penna",
+					Email:     "flapenna@email.com",
+					Country:   "IT",
+					Nickname:  "Pennino",
+					CreatedAt: timestamppb.New(now),
+					UpdatedAt: timestamppb.New(now),
+				},
+				AuditEntries: []*pb.UserAuditEntry{
+					{
+						Id:            "event-1",
+						UserId:        userId,
+						AfterChange:   &pb.User{Id: userId, CreatedAt: timestamppb.New(now), UpdatedAt: timestamppb.New(now)},
+						OperationType: pb.UserAuditAction_USER_AUDIT_ACTION_CREATE,
+						ModifiedBy:    userId,
+						CorrelationId: "request-1",
+						RecordedAt:    timestamppb.New(now),
+					},
+				},
+				Versions: []*pb.UserVersion{
+					{Version: 2, Deleted: true, ValidFrom: timestamppb.New(now), ModifiedBy: "admin-1", CorrelationId: "request-2"},
+				},
+				Deleted:    true,
+				ExportedAt: timestamppb.New(now),
+			},
+			mockError: nil,
+			wantedErr: nil,
+		},
+		{
+			name:         "missing actor",
+			req:          &pb.ExportMyDataRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrUnauthenticated,
+			wantedErr:    status.Error(codes.Unauthenticated, domain.ErrUnauthenticated.Error()),
+		},
+		{
+			name:         "permission denied",
+			req:          &pb.ExportMyDataRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrPermissionDenied,
+			wantedErr:    status.Error(codes.PermissionDenied, domain.ErrPermissionDenied.Error()),
+		},
+		{
+			name:         "user not found",
+			req:          &pb.ExportMyDataRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrUserNotFound,
+			wantedErr:    status.Error(codes.NotFound, domain.ErrUserNotFound.Error()),
+		},
+		{
+			name:         "service error",
+			req:          &pb.ExportMyDataRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    errors.New("service error"),
+			wantedErr:    status.Error(codes.Internal, "internal server error"),
+		},
+		{
+			name:         "validation error",
+			req:          &pb.ExportMyDataRequest{Id: "not-uuid"}, // not uuid
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    nil,
+			wantedErr:    status.Error(codes.InvalidArgument, "invalid ExportMyDataRequest.Id: value must be a valid UUID | caused by: invalid uuid format"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			ctx := context.TODO()
+
+			mockUserService.On("ExportUserData", mock.Anything, tt.req.Id).Return(tt.mockResponse, tt.mockError).Once()
+
+			resp, err := server.ExportMyData(ctx, tt.req)
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantedRes, resp)
+			}
+
+		})
+	}
+}
+
+func TestUserServiceServer_ListUserAuditEntries(t *testing.T) {
+	userId := uuid.NewString()
+	actorId := "admin-1"
+	now := time.Now()
+	tests := []struct {
+		name         string
+		req          *pb.ListUserAuditEntriesRequest
+		mockResponse *domain.ListUserAuditEntriesQueryResponse
+		wantedRes    *pb.ListUserAuditEntriesResponse
+		mockError    error
+		wantedErr    error
+	}{
+		{
+			name: "successful list",
+			req: &pb.ListUserAuditEntriesRequest{
+				PageSize:   10,
+				UserId:     &userId,
+				ModifiedBy: &actorId,
+				From:       timestamppb.New(now.Add(-time.Hour)),
+				To:         timestamppb.New(now),
+			},
+			mockResponse: &domain.ListUserAuditEntriesQueryResponse{
+				Page:       0,
+				PageSize:   10,
+				TotalCount: 2,
+				Results: []*domain.UserAuditEntry{
+					{
+						Id:            "event-1",
+						UserId:        userId,
+						BeforeChange:  &domain.User{ID: userId, FirstName: "Federico", CreatedAt: now, UpdatedAt: now},
+						OperationType: domain.OPERATION_DELETE,
+						ModifiedBy:    actorId,
+						CorrelationId: "request-1",
+						RecordedAt:    now,
+					},
+					{
+						Id:            "export-1",
+						UserId:        userId,
+						OperationType: domain.OPERATION_EXPORT,
+						ModifiedBy:    actorId,
+						CorrelationId: "request-2",
+						RecordedAt:    now,
+					},
+				},
+			},
+			wantedRes: &pb.ListUserAuditEntriesResponse{
+				Page:       0,
+				PageSize:   10,
+				TotalCount: 2,
+				Results: []*pb.UserAuditEntry{
+					{
+						Id:            "event-1",
+						UserId:        userId,
+						BeforeChange:  &pb.User{Id: userId, FirstName: "Federico", CreatedAt: timestamppb.New(now), UpdatedAt: timestamppb.New(now)},
+						OperationType: pb.UserAuditAction_USER_AUDIT_ACTION_DELETE,
+						ModifiedBy:    actorId,
+						CorrelationId: "request-1",
+						RecordedAt:    timestamppb.New(now),
+					},
+					{
+						Id:            "export-1",
+						UserId:        userId,
+						OperationType: pb.UserAuditAction_USER_AUDIT_ACTION_EXPORT,
+						ModifiedBy:    actorId,
+						CorrelationId: "request-2",
+						RecordedAt:    timestamppb.New(now),
+					},
+				},
+			},
+			mockError: nil,
+			wantedErr: nil,
+		},
+		{
+			name:         "missing actor",
+			req:          &pb.ListUserAuditEntriesRequest{},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrUnauthenticated,
+			wantedErr:    status.Error(codes.Unauthenticated, domain.ErrUnauthenticated.Error()),
+		},
+		{
+			name:         "permission denied",
+			req:          &pb.ListUserAuditEntriesRequest{},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrPermissionDenied,
+			wantedErr:    status.Error(codes.PermissionDenied, domain.ErrPermissionDenied.Error()),
+		},
+		{
+			name:         "service error",
+			req:          &pb.ListUserAuditEntriesRequest{},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    errors.New("service error"),
+			wantedErr:    status.Error(codes.Internal, "internal server error"),
+		},
+		{
+			name:         "validation error",
+			req:          &pb.ListUserAuditEntriesRequest{UserId: &actorId}, // not uuid
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    nil,
+			wantedErr:    status.Error(codes.InvalidArgument, "invalid ListUserAuditEntriesRequest.UserId: value must be a valid UUID | caused by: invalid uuid format"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			ctx := context.TODO()
+
+			mockUserService.On("ListUserAuditEntries", mock.Anything, mock.MatchedBy(func(req *domain.ListUserAuditEntriesQueryRequest) bool {
+				return req.UserId == tt.req.UserId && req.ModifiedBy == tt.req.ModifiedBy &&
+					(tt.req.From == nil || req.From.Equal(tt.req.From.AsTime())) &&
+					(tt.req.To == nil || req.To.Equal(tt.req.To.AsTime()))
+			})).Return(tt.mockResponse, tt.mockError).Once()
+
+			resp, err := server.ListUserAuditEntries(ctx, tt.req)
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantedRes, resp)
+			}
+
+		})
+	}
+}
+
+func TestUserServiceServer_GetUser(t *testing.T) {
+	userId := uuid.NewString()
+	now := time.Now()
+	asOf := now.Add(-24 * time.Hour)
+	tests := []struct {
+		name         string
+		req          *pb.GetUserRequest
+		mockResponse *domain.User
+		wantedRes    *pb.User
+		mockError    error
+		wantedErr    error
+	}{
+		{
+			name: "current user",
+			req:  &pb.GetUserRequest{Id: userId},
+			mockResponse: &domain.User{
+				ID:        userId,
+				FirstName: "Federico",
+				CreatedAt: now,
+				UpdatedAt: now,
+				Version:   3,
+			},
+			wantedRes: &pb.User{
+				Id:        userId,
+				FirstName: "Federico",
+				CreatedAt: timestamppb.New(now),
+				UpdatedAt: timestamppb.New(now),
+				Version:   3,
+			},
+			mockError: nil,
+			wantedErr: nil,
+		},
+		{
+			name: "user as of a past time",
+			req:  &pb.GetUserRequest{Id: userId, AsOf: timestamppb.New(asOf)},
+			mockResponse: &domain.User{
+				ID:        userId,
+				FirstName: "Fede",
+				CreatedAt: asOf,
+				UpdatedAt: asOf,
+				Version:   1,
+			},
+			wantedRes: &pb.User{
+				Id:        userId,
+				FirstName: "Fede",
+				CreatedAt: timestamppb.New(asOf),
+				UpdatedAt: timestamppb.New(asOf),
+				Version:   1,
+			},
+			mockError: nil,
+			wantedErr: nil,
+		},
+		{
+			name:         "user not found",
+			req:          &pb.GetUserRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrUserNotFound,
+			wantedErr:    status.Error(codes.NotFound, domain.ErrUserNotFound.Error()),
+		},
+		{
+			name:         "past state of another user",
+			req:          &pb.GetUserRequest{Id: userId, AsOf: timestamppb.New(asOf)},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrPermissionDenied,
+			wantedErr:    status.Error(codes.PermissionDenied, domain.ErrPermissionDenied.Error()),
+		},
+		{
+			name:         "service error",
+			req:          &pb.GetUserRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    errors.New("service error"),
+			wantedErr:    status.Error(codes.Internal, "internal server error"),
+		},
+		{
+			name:         "validation error",
+			req:          &pb.GetUserRequest{Id: "not-uuid"}, // not uuid
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    nil,
+			wantedErr:    status.Error(codes.InvalidArgument, "invalid GetUserRequest.Id: value must be a valid UUID | caused by: invalid uuid format"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			ctx := context.TODO()
+
+			mockUserService.On("GetUser", mock.Anything, tt.req.Id, mock.MatchedBy(func(asOf *time.Time) bool {
+				if tt.req.AsOf == nil {
+					return asOf == nil
+				}
+				return asOf != nil && asOf.Equal(tt.req.AsOf.AsTime())
+			})).Return(tt.mockResponse, tt.mockError).Once()
+
+			resp, err := server.GetUser(ctx, tt.req)
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantedRes, resp)
+			}
+
+		})
+	}
+}
+
+func TestUserServiceServer_ListUserVersions(t *testing.T) {
+	userId := uuid.NewString()
+	now := time.Now()
+	tests := []struct {
+		name         string
+		req          *pb.ListUserVersionsRequest
+		mockResponse *domain.ListUserVersionsQueryResponse
+		wantedRes    *pb.ListUserVersionsResponse
+		mockError    error
+		wantedErr    error
+	}{
+		{
+			name: "successful list",
+			req:  &pb.ListUserVersionsRequest{Id: userId, PageSize: 10},
+			mockResponse: &domain.ListUserVersionsQueryResponse{
+				Page:       0,
+				PageSize:   10,
+				TotalCount: 2,
+				Results: []*domain.UserVersion{
+					{UserId: userId, Version: 2, Deleted: true, ValidFrom: now, ModifiedBy: "admin-1", CorrelationId: "request-2"},
+					{UserId: userId, Version: 1, User: &domain.User{ID: userId, CreatedAt: now, UpdatedAt: now, Version: 1}, ValidFrom: now, ModifiedBy: "admin-1", CorrelationId: "request-1"},
+				},
+			},
+			wantedRes: &pb.ListUserVersionsResponse{
+				Page:       0,
+				PageSize:   10,
+				TotalCount: 2,
+				Results: []*pb.UserVersion{
+					{Version: 2, Deleted: true, ValidFrom: timestamppb.New(now), ModifiedBy: "admin-1", CorrelationId: "request-2"},
+					{Version: 1, User: &pb.User{Id: userId, CreatedAt: timestamppb.New(now), UpdatedAt: timestamppb.New(now), Version: 1}, ValidFrom: timestamppb.New(now), ModifiedBy: "admin-1", CorrelationId: "request-1"},
+				},
+			},
+			mockError: nil,
+			wantedErr: nil,
+		},
+		{
+			name:         "service error",
+			req:          &pb.ListUserVersionsRequest{Id: userId},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    errors.New("service error"),
+			wantedErr:    status.Error(codes.Internal, "internal server error"),
+		},
+		{
+			name:         "validation error",
+			req:          &pb.ListUserVersionsRequest{Id: "not-uuid"}, // not uuid
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    nil,
+			wantedErr:    status.Error(codes.InvalidArgument, "invalid ListUserVersionsRequest.Id: value must be a valid UUID | caused by: invalid uuid format"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			ctx := context.TODO()
+
+			mockUserService.On("ListUserVersions", mock.Anything, &domain.ListUserVersionsQueryRequest{
+				UserId:   tt.req.Id,
+				Page:     tt.req.Page,
+				PageSize: tt.req.PageSize,
+			}).Return(tt.mockResponse, tt.mockError).Once()
+
+			resp, err := server.ListUserVersions(ctx, tt.req)
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantedRes, resp)
+			}
+
+		})
+	}
+}
+
+func TestUserServiceServer_RevertUser(t *testing.T) {
+	userId := uuid.NewString()
+	now := time.Now()
+	tests := []struct {
+		name         string
+		req          *pb.RevertUserRequest
+		mockResponse *domain.User
+		wantedRes    *pb.User
+		mockError    error
+		wantedErr    error
+	}{
+		{
+			name: "successful revert",
+			req:  &pb.RevertUserRequest{Id: userId, Version: 1, ExpectedVersion: 3},
+			mockResponse: &domain.User{
+				ID:        userId,
+				FirstName: "Federico",
+				CreatedAt: now,
+				UpdatedAt: now,
+				Version:   4,
+			},
+			wantedRes: &pb.User{
+				Id:        userId,
+				FirstName: "Federico",
+				CreatedAt: timestamppb.New(now),
+				UpdatedAt: timestamppb.New(now),
+				Version:   4,
+			},
+			mockError: nil,
+			wantedErr: nil,
+		},
+		{
+			name:         "version conflict",
+			req:          &pb.RevertUserRequest{Id: userId, Version: 1, ExpectedVersion: 3},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrVersionConflict,
+			wantedErr:    status.Error(codes.Aborted, domain.ErrVersionConflict.Error()),
+		},
+		{
+			name:         "version not found",
+			req:          &pb.RevertUserRequest{Id: userId, Version: 7, ExpectedVersion: 3},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    domain.ErrUserVersionNotFound,
+			wantedErr:    status.Error(codes.NotFound, domain.ErrUserVersionNotFound.Error()),
+		},
+		{
+			name:         "service error",
+			req:          &pb.RevertUserRequest{Id: userId, Version: 1, ExpectedVersion: 3},
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    errors.New("service error"),
+			wantedErr:    status.Error(codes.Internal, "internal server error"),
+		},
+		{
+			name:         "validation error",
+			req:          &pb.RevertUserRequest{Id: userId, Version: 1}, // missing expected version
+			mockResponse: nil,
+			wantedRes:    nil,
+			mockError:    nil,
+			wantedErr:    status.Error(codes.InvalidArgument, "invalid RevertUserRequest.ExpectedVersion: value must be greater than 0"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			ctx := context.TODO()
+
+			mockUserService.On("RevertUser", mock.Anything, tt.req.Id, tt.req.Version, tt.req.ExpectedVersion).Return(tt.mockResponse, tt.mockError).Once()
+
+			resp, err := server.RevertUser(ctx, tt.req)
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantedRes, resp)
+			}
+
+		})
+	}
+}
+
+func TestUserServiceServer_GetDeadLetter(t *testing.T) {
+	eventId := uuid.NewString()
+	userId := uuid.NewString()
+	now := time.Now()
+	tests := []struct {
+		name         string
+		req          *pb.GetDeadLetterRequest
+		mockResponse *domain.DeadLetter
+		wantedRes    *pb.DeadLetter
+		mockError    error
+		wantedErr    error
+	}{
+		{
+			name: "successful get",
+			req:  &pb.GetDeadLetterRequest{Id: eventId},
+			mockResponse: &domain.DeadLetter{
+				Id: eventId,
+				Event: &domain.UserEvent{
+					Id:            eventId,
+					UserId:        userId,
+					AfterChange:   &domain.User{ID: userId, FirstName: "Federico", CreatedAt: now, UpdatedAt: now},
+					OperationType: domain.OPERATION_CREATE,
+					ModifiedBy:    "admin-1",
+					CorrelationId: "request-1",
+				},
+				Error:         "producer error",
+				Attempts:      2,
+				FirstFailedAt: now,
+				LastFailedAt:  now,
+			},
+			wantedRes: &pb.DeadLetter{
+				Id:     eventId,
+				UserId: userId,
+				AfterChange: &pb.User{
+					Id:        userId,
+					FirstName: "Federico",
+					CreatedAt: timestamppb.New(now),
+					UpdatedAt: timestamppb.New(now),
+				},
+				OperationType: pb.UserAuditAction_USER_AUDIT_ACTION_CREATE,
+				ModifiedBy:    "admin-1",
+				CorrelationId: "request-1",
+				Error:         "producer error",
+				Attempts:      2,
+				FirstFailedAt: timestamppb.New(now),
+				LastFailedAt:  timestamppb.New(now),
+			},
+		},
+		{
+			name:      "not found",
+			req:       &pb.GetDeadLetterRequest{Id: eventId},
+			mockError: domain.ErrDeadLetterNotFound,
+			wantedErr: status.Error(codes.NotFound, domain.ErrDeadLetterNotFound.Error()),
+		},
+		{
+			name:      "permission denied",
+			req:       &pb.GetDeadLetterRequest{Id: eventId},
+			mockError: domain.ErrPermissionDenied,
+			wantedErr: status.Error(codes.PermissionDenied, domain.ErrPermissionDenied.Error()),
+		},
+		{
+			name:      "validation error",
+			req:       &pb.GetDeadLetterRequest{Id: "not-a-uuid"},
+			wantedErr: status.Error(codes.InvalidArgument, "invalid GetDeadLetterRequest.Id: value must be a valid UUID | caused by: invalid uuid format"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			mockUserService.On("GetDeadLetter", mock.Anything, tt.req.Id).Return(tt.mockResponse, tt.mockError).Once()
+
+			resp, err := server.GetDeadLetter(context.TODO(), tt.req)
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.wantedRes, resp), "expected %v, got %v", tt.wantedRes, resp)
+			}
+		})
+	}
+}
+
+func TestUserServiceServer_RedriveDeadLetter(t *testing.T) {
+	eventId := uuid.NewString()
+	tests := []struct {
+		name      string
+		mockError error
+		wantedErr error
+	}{
+		{
+			name: "successful redrive",
+		},
+		{
+			name:      "not found",
+			mockError: domain.ErrDeadLetterNotFound,
+			wantedErr: status.Error(codes.NotFound, domain.ErrDeadLetterNotFound.Error()),
+		},
+		{
+			name:      "unauthenticated",
+			mockError: domain.ErrUnauthenticated,
+			wantedErr: status.Error(codes.Unauthenticated, domain.ErrUnauthenticated.Error()),
+		},
+		{
+			name:      "publishing error",
+			mockError: errors.New("producer error"),
+			wantedErr: status.Error(codes.Internal, "internal server error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.MockUserService)
+			server := grpcServer.NewUserServiceServer(mockUserService)
+
+			mockUserService.On("RedriveDeadLetter", mock.Anything, eventId).Return(tt.mockError).Once()
+
+			_, err := server.RedriveDeadLetter(context.TODO(), &pb.RedriveDeadLetterRequest{Id: eventId})
+			if tt.wantedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			mockUserService.AssertExpectations(t)
+		})
+	}
+}
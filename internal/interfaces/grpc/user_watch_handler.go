@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/mapper"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sync"
+)
+
+// WatchSubscribedMetadataKey is sent in the headers of WatchUsers once subscribed, before the first event.
+// The headers of a failed call, e.g. the request id, never have it.
+const WatchSubscribedMetadataKey = "x-watch-subscribed"
+
+type UserWatchServiceServer struct {
+	pb.UnimplementedUserWatchServiceServer
+	userService domain.UserService
+	stopped     chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewUserWatchServiceServer(userService domain.UserService) *UserWatchServiceServer {
+	return &UserWatchServiceServer{userService: userService, stopped: make(chan struct{})}
+}
+
+// Stop ends the open streams with Unavailable, which would otherwise never let the server stop gracefully
+func (s *UserWatchServiceServer) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopped)
+	})
+}
+
+// WatchUsers streams the user events until the client cancels. A client too slow to receive
+// them is disconnected with ResourceExhausted, and resumes from the last received event.
+func (s *UserWatchServiceServer) WatchUsers(req *pb.WatchUsersRequest, stream pb.UserWatchService_WatchUsersServer) error {
+	log.Infof("[GRPC] WatchUsers called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate watch users request: %v", err)
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopped:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	watchRequest := &domain.WatchUsersRequest{
+		UserIds:       req.UserIds,
+		Country:       req.Country,
+		AfterEventId:  req.GetAfterEventId(),
+		AfterSequence: req.GetAfterSequence(),
+		Epoch:         req.Epoch,
+	}
+	for _, operationType := range req.OperationTypes {
+		watchRequest.OperationTypes = append(watchRequest.OperationTypes, mapper.OperationTypeFromProto(operationType))
+	}
+
+	subscription, err := s.userService.WatchUsers(ctx, watchRequest)
+	if err != nil {
+		return watchUsersError(err)
+	}
+	defer subscription.Close()
+	if err := stream.SendHeader(metadata.Pairs(WatchSubscribedMetadataKey, "true")); err != nil {
+		return err
+	}
+
+	for {
+		watched, err := subscription.Next(ctx)
+		if err != nil {
+			select {
+			case <-s.stopped:
+				return status.Errorf(codes.Unavailable, "server is shutting down")
+			default:
+			}
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return watchUsersError(err)
+		}
+		err = stream.Send(&pb.WatchUsersResponse{
+			Sequence: watched.Sequence,
+			Event:    mapper.UserEventToProto(watched.Event),
+			Epoch:    watched.Epoch,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// watchUsersError maps the errors of a user event subscription to gRPC statuses
+func watchUsersError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrWatchResumeUnavailable):
+		return status.Errorf(codes.OutOfRange, err.Error())
+	case errors.Is(err, domain.ErrWatchSubscriberTooSlow):
+		return status.Errorf(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, domain.ErrUserEventsStopped):
+		return status.Errorf(codes.Unavailable, err.Error())
+	}
+	log.Errorf("failed to watch users: %v", err)
+	return status.Errorf(codes.Internal, "internal server error")
+}
@@ -0,0 +1,156 @@
+//go:build unit
+
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// watchUsersStream is the server stream of a WatchUsers call, collecting the sent responses
+type watchUsersStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.WatchUsersResponse
+}
+
+func (s *watchUsersStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchUsersStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *watchUsersStream) Send(res *pb.WatchUsersResponse) error {
+	s.sent <- res
+	return nil
+}
+
+func TestUserWatchServiceServer_WatchUsers(t *testing.T) {
+	userId := uuid.NewString()
+	country := "IT"
+	event := &domain.UserEvent{Id: "event-1", UserId: userId, OperationType: domain.OPERATION_UPDATE,
+		AfterChange: &domain.User{ID: userId, Country: "IT", Version: 2}, Sequence: 2, OccurredAt: time.Now()}
+
+	tests := []struct {
+		name        string
+		req         *pb.WatchUsersRequest
+		wantRequest *domain.WatchUsersRequest
+		mockError   error
+		stop        func(broadcaster *domain.UserEventBroadcaster, server *grpcServer.UserWatchServiceServer)
+		wantCode    codes.Code
+	}{
+		{
+			name: "streamed until the user watcher stops",
+			req: &pb.WatchUsersRequest{
+				UserIds:        []string{userId},
+				OperationTypes: []pb.OperationType{pb.OperationType_OPERATION_UPDATE},
+				Country:        &country,
+				ResumeFrom:     &pb.WatchUsersRequest_AfterEventId{AfterEventId: "event-0"},
+			},
+			wantRequest: &domain.WatchUsersRequest{
+				UserIds:        []string{userId},
+				OperationTypes: []domain.OperationType{domain.OPERATION_UPDATE},
+				Country:        &country,
+				AfterEventId:   "event-0",
+			},
+			stop: func(broadcaster *domain.UserEventBroadcaster, _ *grpcServer.UserWatchServiceServer) {
+				broadcaster.Close()
+			},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:        "streamed until the server stops",
+			req:         &pb.WatchUsersRequest{ResumeFrom: &pb.WatchUsersRequest_AfterSequence{AfterSequence: 3}, Epoch: "epoch-1"},
+			wantRequest: &domain.WatchUsersRequest{AfterSequence: 3, Epoch: "epoch-1"},
+			stop: func(_ *domain.UserEventBroadcaster, server *grpcServer.UserWatchServiceServer) {
+				server.Stop()
+			},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name:     "invalid user id",
+			req:      &pb.WatchUsersRequest{UserIds: []string{"user-123"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unspecified operation type",
+			req:      &pb.WatchUsersRequest{OperationTypes: []pb.OperationType{pb.OperationType_OPERATION_UNSPECIFIED}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:        "permission denied",
+			req:         &pb.WatchUsersRequest{},
+			wantRequest: &domain.WatchUsersRequest{},
+			mockError:   domain.ErrPermissionDenied,
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "resume point unavailable",
+			req:         &pb.WatchUsersRequest{ResumeFrom: &pb.WatchUsersRequest_AfterEventId{AfterEventId: "event-0"}},
+			wantRequest: &domain.WatchUsersRequest{AfterEventId: "event-0"},
+			mockError:   domain.ErrWatchResumeUnavailable,
+			wantCode:    codes.OutOfRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockUserService)
+			server := grpcServer.NewUserWatchServiceServer(mockService)
+			broadcaster := domain.NewUserEventBroadcaster(10, 10)
+			if tt.wantRequest != nil {
+				if tt.mockError != nil {
+					mockService.On("WatchUsers", mock.Anything, tt.wantRequest).Return(nil, tt.mockError)
+				} else {
+					subscription, err := broadcaster.Subscribe(&domain.WatchUsersRequest{})
+					assert.NoError(t, err)
+					mockService.On("WatchUsers", mock.Anything, tt.wantRequest).Return(subscription, nil)
+				}
+			}
+
+			stream := &watchUsersStream{ctx: context.TODO(), sent: make(chan *pb.WatchUsersResponse, 1)}
+			done := make(chan error, 1)
+			go func() {
+				done <- server.WatchUsers(tt.req, stream)
+			}()
+
+			if tt.stop != nil {
+				broadcaster.Broadcast(event)
+				select {
+				case res := <-stream.sent:
+					assert.Equal(t, uint64(1), res.Sequence)
+					assert.Equal(t, broadcaster.Epoch(), res.Epoch)
+					assert.Equal(t, "event-1", res.Event.Id)
+					assert.Equal(t, pb.OperationType_OPERATION_UPDATE, res.Event.OperationType)
+					assert.Equal(t, "IT", res.Event.AfterChange.Country)
+				case <-time.After(5 * time.Second):
+					t.Fatal("event not streamed")
+				}
+				tt.stop(broadcaster, server)
+			}
+
+			select {
+			case err := <-done:
+				assert.Equal(t, tt.wantCode, status.Code(err), err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("stream not ended")
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	"github.com/flapenna/go-ddd-crud/internal/mapper"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WebhookServiceServer struct {
+	pb.UnimplementedWebhookServiceServer
+	webhookService domain.WebhookService
+}
+
+func NewWebhookServiceServer(webhookService domain.WebhookService) *WebhookServiceServer {
+	return &WebhookServiceServer{webhookService: webhookService}
+}
+
+func (s *WebhookServiceServer) CreateWebhookSubscription(ctx context.Context, req *pb.CreateWebhookSubscriptionRequest) (*pb.WebhookSubscription, error) {
+	log.Infof("[GRPC] CreateWebhookSubscription called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate create webhook subscription request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	subscription, err := s.webhookService.CreateWebhookSubscription(ctx, &domain.WebhookSubscription{
+		Url:            req.Url,
+		OperationTypes: operationTypesFromProto(req.OperationTypes),
+	})
+	if err != nil {
+		return nil, webhookError("create webhook subscription", err)
+	}
+	return webhookSubscriptionToProto(subscription), nil
+}
+
+func (s *WebhookServiceServer) ListWebhookSubscriptions(ctx context.Context, req *pb.ListWebhookSubscriptionsRequest) (*pb.ListWebhookSubscriptionsResponse, error) {
+	log.Infof("[GRPC] ListWebhookSubscriptions called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate list webhook subscriptions request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, err := s.webhookService.ListWebhookSubscriptions(ctx, &domain.ListWebhookSubscriptionsQueryRequest{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	})
+	if err != nil {
+		return nil, webhookError("list webhook subscriptions", err)
+	}
+
+	subscriptions := make([]*pb.WebhookSubscription, len(res.Results))
+	for i, subscription := range res.Results {
+		subscriptions[i] = webhookSubscriptionToProto(subscription)
+	}
+	return &pb.ListWebhookSubscriptionsResponse{
+		Page:       res.Page,
+		PageSize:   res.PageSize,
+		TotalCount: res.TotalCount,
+		Results:    subscriptions,
+	}, nil
+}
+
+func (s *WebhookServiceServer) GetWebhookSubscription(ctx context.Context, req *pb.GetWebhookSubscriptionRequest) (*pb.WebhookSubscription, error) {
+	log.Infof("[GRPC] GetWebhookSubscription called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate get webhook subscription request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	subscription, err := s.webhookService.GetWebhookSubscription(ctx, req.Id)
+	if err != nil {
+		return nil, webhookError("get webhook subscription", err)
+	}
+	return webhookSubscriptionToProto(subscription), nil
+}
+
+func (s *WebhookServiceServer) UpdateWebhookSubscription(ctx context.Context, req *pb.UpdateWebhookSubscriptionRequest) (*pb.WebhookSubscription, error) {
+	log.Infof("[GRPC] UpdateWebhookSubscription called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate update webhook subscription request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	subscription, err := s.webhookService.UpdateWebhookSubscription(ctx, &domain.UpdateWebhookSubscriptionRequest{
+		Id:             req.Id,
+		Url:            req.Url,
+		OperationTypes: operationTypesFromProto(req.OperationTypes),
+		Enabled:        req.Enabled,
+		RotateSecret:   req.RotateSecret,
+	})
+	if err != nil {
+		return nil, webhookError("update webhook subscription", err)
+	}
+	return webhookSubscriptionToProto(subscription), nil
+}
+
+func (s *WebhookServiceServer) DeleteWebhookSubscription(ctx context.Context, req *pb.DeleteWebhookSubscriptionRequest) (*emptypb.Empty, error) {
+	log.Infof("[GRPC] DeleteWebhookSubscription called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate delete webhook subscription request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.webhookService.DeleteWebhookSubscription(ctx, req.Id); err != nil {
+		return nil, webhookError("delete webhook subscription", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebhookServiceServer) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	log.Infof("[GRPC] ListWebhookDeliveries called")
+	if err := req.Validate(); err != nil {
+		log.Errorf("failed to validate list webhook deliveries request: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res, err := s.webhookService.ListWebhookDeliveries(ctx, &domain.ListWebhookDeliveriesQueryRequest{
+		SubscriptionId: req.Id,
+		Page:           req.Page,
+		PageSize:       req.PageSize,
+		Succeeded:      req.Succeeded,
+	})
+	if err != nil {
+		return nil, webhookError("list webhook deliveries", err)
+	}
+
+	deliveries := make([]*pb.WebhookDelivery, len(res.Results))
+	for i, d := range res.Results {
+		deliveries[i] = &pb.WebhookDelivery{
+			Id:             d.Id,
+			SubscriptionId: d.SubscriptionId,
+			EventId:        d.EventId,
+			OperationType:  mapper.OperationTypeToProto(d.OperationType),
+			Succeeded:      d.Succeeded,
+			Attempts:       d.Attempts,
+			StatusCode:     d.StatusCode,
+			Error:          d.Error,
+			StartedAt:      timestamppb.New(d.StartedAt),
+			FinishedAt:     timestamppb.New(d.FinishedAt),
+		}
+	}
+	return &pb.ListWebhookDeliveriesResponse{
+		Page:       res.Page,
+		PageSize:   res.PageSize,
+		TotalCount: res.TotalCount,
+		Results:    deliveries,
+	}, nil
+}
+
+// webhookError maps the errors of the webhook subscriptions to gRPC statuses
+func webhookError(operation string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return status.Errorf(codes.Unauthenticated, err.Error())
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return status.Errorf(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrWebhookSubscriptionNotFound):
+		return status.Errorf(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidWebhookUrl):
+		return status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	log.Errorf("failed to %s: %v", operation, err)
+	return status.Errorf(codes.Internal, "internal server error")
+}
+
+func webhookSubscriptionToProto(subscription *domain.WebhookSubscription) *pb.WebhookSubscription {
+	operationTypes := make([]pb.OperationType, len(subscription.OperationTypes))
+	for i, operationType := range subscription.OperationTypes {
+		operationTypes[i] = mapper.OperationTypeToProto(operationType)
+	}
+	return &pb.WebhookSubscription{
+		Id:                  subscription.Id,
+		Url:                 subscription.Url,
+		Secret:              subscription.Secret,
+		OperationTypes:      operationTypes,
+		Enabled:             subscription.Enabled,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		DisabledReason:      subscription.DisabledReason,
+		CreatedBy:           subscription.CreatedBy,
+		CreatedAt:           timestamppb.New(subscription.CreatedAt),
+		UpdatedAt:           timestamppb.New(subscription.UpdatedAt),
+	}
+}
+
+func operationTypesFromProto(operationTypes []pb.OperationType) []domain.OperationType {
+	if len(operationTypes) == 0 {
+		return nil
+	}
+	converted := make([]domain.OperationType, len(operationTypes))
+	for i, operationType := range operationTypes {
+		converted[i] = mapper.OperationTypeFromProto(operationType)
+	}
+	return converted
+}
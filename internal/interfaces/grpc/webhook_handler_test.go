@@ -0,0 +1,165 @@
+//go:build unit
+
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestWebhookServiceServer_CreateWebhookSubscription(t *testing.T) {
+	now := time.Now()
+	subscriptionId := uuid.NewString()
+	tests := []struct {
+		name         string
+		req          *pb.CreateWebhookSubscriptionRequest
+		mockResponse *domain.WebhookSubscription
+		mockError    error
+		wantedRes    *pb.WebhookSubscription
+		wantedCode   codes.Code
+	}{
+		{
+			name: "successful create",
+			req: &pb.CreateWebhookSubscriptionRequest{Url: "https://partner.example.com/hooks",
+				OperationTypes: []pb.OperationType{pb.OperationType_OPERATION_DELETE}},
+			mockResponse: &domain.WebhookSubscription{Id: subscriptionId, Url: "https://partner.example.com/hooks", Secret: "secret",
+				OperationTypes: []domain.OperationType{domain.OPERATION_DELETE}, Enabled: true, CreatedBy: "admin-1",
+				CreatedAt: now, UpdatedAt: now},
+			wantedRes: &pb.WebhookSubscription{Id: subscriptionId, Url: "https://partner.example.com/hooks", Secret: "secret",
+				OperationTypes: []pb.OperationType{pb.OperationType_OPERATION_DELETE}, Enabled: true, CreatedBy: "admin-1",
+				CreatedAt: timestamppb.New(now), UpdatedAt: timestamppb.New(now)},
+		},
+		{
+			name:       "permission denied",
+			req:        &pb.CreateWebhookSubscriptionRequest{Url: "https://partner.example.com/hooks"},
+			mockError:  domain.ErrPermissionDenied,
+			wantedCode: codes.PermissionDenied,
+		},
+		{
+			name:       "invalid url",
+			req:        &pb.CreateWebhookSubscriptionRequest{Url: "partner.example.com/hooks"},
+			wantedCode: codes.InvalidArgument,
+		},
+		{
+			name:       "plain http url",
+			req:        &pb.CreateWebhookSubscriptionRequest{Url: "http://partner.example.com/hooks"},
+			mockError:  domain.ErrInvalidWebhookUrl,
+			wantedCode: codes.InvalidArgument,
+		},
+		{
+			name: "unspecified operation type",
+			req: &pb.CreateWebhookSubscriptionRequest{Url: "https://partner.example.com/hooks",
+				OperationTypes: []pb.OperationType{pb.OperationType_OPERATION_UNSPECIFIED}},
+			wantedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWebhookService := new(mocks.MockWebhookService)
+			server := grpcServer.NewWebhookServiceServer(mockWebhookService)
+			if tt.mockResponse != nil || tt.mockError != nil {
+				mockWebhookService.On("CreateWebhookSubscription", mock.Anything, mock.MatchedBy(func(s *domain.WebhookSubscription) bool {
+					return s.Url == tt.req.Url && len(s.OperationTypes) == len(tt.req.OperationTypes)
+				})).Return(tt.mockResponse, tt.mockError).Once()
+			}
+
+			resp, err := server.CreateWebhookSubscription(context.TODO(), tt.req)
+			if tt.wantedCode != codes.OK {
+				assert.Equal(t, tt.wantedCode, status.Code(err), err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.wantedRes, resp), "expected %v, got %v", tt.wantedRes, resp)
+			}
+			mockWebhookService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookServiceServer_UpdateWebhookSubscription(t *testing.T) {
+	subscriptionId := uuid.NewString()
+	tests := []struct {
+		name       string
+		mockError  error
+		wantedCode codes.Code
+	}{
+		{name: "successful update"},
+		{name: "not found", mockError: domain.ErrWebhookSubscriptionNotFound, wantedCode: codes.NotFound},
+		{name: "unauthenticated", mockError: domain.ErrUnauthenticated, wantedCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWebhookService := new(mocks.MockWebhookService)
+			server := grpcServer.NewWebhookServiceServer(mockWebhookService)
+			var mockResponse *domain.WebhookSubscription
+			if tt.mockError == nil {
+				mockResponse = &domain.WebhookSubscription{Id: subscriptionId, Url: "https://partner.example.com/v2/hooks", Enabled: true}
+			}
+			mockWebhookService.On("UpdateWebhookSubscription", mock.Anything, &domain.UpdateWebhookSubscriptionRequest{
+				Id:             subscriptionId,
+				Url:            "https://partner.example.com/v2/hooks",
+				OperationTypes: []domain.OperationType{domain.OPERATION_CREATE, domain.OPERATION_UPDATE},
+				Enabled:        true,
+				RotateSecret:   true,
+			}).Return(mockResponse, tt.mockError).Once()
+
+			resp, err := server.UpdateWebhookSubscription(context.TODO(), &pb.UpdateWebhookSubscriptionRequest{
+				Id:             subscriptionId,
+				Url:            "https://partner.example.com/v2/hooks",
+				OperationTypes: []pb.OperationType{pb.OperationType_OPERATION_CREATE, pb.OperationType_OPERATION_UPDATE},
+				Enabled:        true,
+				RotateSecret:   true,
+			})
+			assert.Equal(t, tt.wantedCode, status.Code(err), err)
+			if tt.mockError == nil {
+				assert.Equal(t, subscriptionId, resp.Id)
+				assert.True(t, resp.Enabled)
+			}
+			mockWebhookService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookServiceServer_ListWebhookDeliveries(t *testing.T) {
+	subscriptionId := uuid.NewString()
+	now := time.Now()
+	failed := false
+
+	mockWebhookService := new(mocks.MockWebhookService)
+	server := grpcServer.NewWebhookServiceServer(mockWebhookService)
+	mockWebhookService.On("ListWebhookDeliveries", mock.Anything, &domain.ListWebhookDeliveriesQueryRequest{
+		SubscriptionId: subscriptionId, Page: 1, PageSize: 5, Succeeded: &failed,
+	}).Return(&domain.ListWebhookDeliveriesQueryResponse{Page: 1, PageSize: 5, TotalCount: 6, Results: []*domain.WebhookDelivery{
+		{Id: "delivery-1", SubscriptionId: subscriptionId, EventId: "event-1", OperationType: domain.OPERATION_UPDATE,
+			Attempts: 5, StatusCode: 503, Error: "unexpected status 503", StartedAt: now, FinishedAt: now},
+	}}, nil).Once()
+
+	resp, err := server.ListWebhookDeliveries(context.TODO(), &pb.ListWebhookDeliveriesRequest{
+		Id: subscriptionId, Page: 1, PageSize: 5, Succeeded: &failed,
+	})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&pb.ListWebhookDeliveriesResponse{Page: 1, PageSize: 5, TotalCount: 6, Results: []*pb.WebhookDelivery{
+		{Id: "delivery-1", SubscriptionId: subscriptionId, EventId: "event-1", OperationType: pb.OperationType_OPERATION_UPDATE,
+			Attempts: 5, StatusCode: 503, Error: "unexpected status 503", StartedAt: timestamppb.New(now), FinishedAt: timestamppb.New(now)},
+	}}, resp), "got %v", resp)
+
+	// The deliveries of an unknown subscription are not found
+	mockWebhookService.On("ListWebhookDeliveries", mock.Anything, mock.Anything).Return(nil, domain.ErrWebhookSubscriptionNotFound).Once()
+	_, err = server.ListWebhookDeliveries(context.TODO(), &pb.ListWebhookDeliveriesRequest{Id: uuid.NewString()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockWebhookService.AssertExpectations(t)
+}
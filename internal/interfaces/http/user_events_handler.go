@@ -0,0 +1,249 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/gorilla/websocket"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// UserEventsPath is the path of the user event feed on the HTTP gateway
+const UserEventsPath = "/api/v1/users/events"
+
+const (
+	// LastEventIdHeader is sent by the reconnecting EventSource clients, with the id of the last received event
+	LastEventIdHeader = "Last-Event-ID"
+	// LastEventIdParam resumes the clients which cannot set headers, e.g. a browser WebSocket
+	LastEventIdParam = "last_event_id"
+	// writeTimeout bounds the writes to a WebSocket client
+	writeTimeout = 10 * time.Second
+)
+
+// forwardedHeaders are forwarded to the gRPC server as metadata, as by the gateway
+var forwardedHeaders = []string{grpcServer.AuthorizationMetadataKey, grpcServer.RequestIdMetadataKey,
+	grpcServer.TraceParentMetadataKey}
+
+// UserEventsHandler streams the user events of the WatchUsers RPC to the browsers, as server-sent
+// events or as the text messages of a WebSocket. Every event is encoded as the JSON of a UserEvent.
+type UserEventsHandler struct {
+	client            pb.UserWatchServiceClient
+	heartbeatInterval time.Duration
+	upgrader          websocket.Upgrader
+	marshaler         protojson.MarshalOptions
+}
+
+// NewUserEventsHandler creates the handler of the user event feed, sending a heartbeat to the idle
+// clients every heartbeatInterval so that the proxies keep their connection open
+func NewUserEventsHandler(client pb.UserWatchServiceClient, heartbeatInterval time.Duration) *UserEventsHandler {
+	return &UserEventsHandler{
+		client:            client,
+		heartbeatInterval: heartbeatInterval,
+		marshaler:         protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+	}
+}
+
+// ServeHTTP streams the events matching the user_ids, operation_types and country query
+// parameters, after the event of the Last-Event-ID header or the last_event_id parameter
+func (h *UserEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log.Infof("[HTTP] UserEvents called")
+	req, err := watchUsersRequest(r)
+	if err != nil {
+		writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(r.Context(), outgoingMetadata(r)))
+	defer cancel()
+	stream, err := h.client.WatchUsers(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if md, _ := stream.Header(); len(md.Get(grpcServer.WatchSubscribedMetadataKey)) == 0 {
+		_, err := stream.Recv()
+		writeError(w, err)
+		return
+	}
+
+	events, errs := receive(ctx, stream)
+	if websocket.IsWebSocketUpgrade(r) {
+		h.serveWebSocket(ctx, cancel, w, r, events, errs)
+		return
+	}
+	h.serveEventStream(ctx, w, events, errs)
+}
+
+// serveEventStream writes the events as server-sent events, identified by the event id. A
+// failure of the stream is sent as an error event, with the gRPC status as data.
+func (h *UserEventsHandler) serveEventStream(ctx context.Context, w http.ResponseWriter,
+	events <-chan *pb.WatchUsersResponse, errs <-chan error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, status.Error(codes.Internal, "streaming unsupported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Disables the response buffering of nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case res := <-events:
+			var data []byte
+			if data, err = h.marshaler.Marshal(res.Event); err == nil {
+				_, err = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", res.Event.Id, data)
+			}
+		case streamErr := <-errs:
+			data, _ := protojson.Marshal(status.Convert(streamErr).Proto())
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			flusher.Flush()
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err != nil {
+			log.Warnf("failed to send user event: %v", err)
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// serveWebSocket writes the events as text messages. A failure of the stream closes the
+// connection with a code telling the client whether to reconnect.
+func (h *UserEventsHandler) serveWebSocket(ctx context.Context, cancel context.CancelFunc, w http.ResponseWriter,
+	r *http.Request, events <-chan *pb.WatchUsersResponse, errs <-chan error) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader replied with the error
+		log.Warnf("failed to upgrade user events request: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// The client messages are discarded, reading them answers the pings and detects a closed connection
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case res := <-events:
+			data, err := h.marshaler.Marshal(res.Event)
+			if err == nil {
+				_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+				err = conn.WriteMessage(websocket.TextMessage, data)
+			}
+			if err != nil {
+				log.Warnf("failed to send user event: %v", err)
+				return
+			}
+		case streamErr := <-errs:
+			st := status.Convert(streamErr)
+			closeCode := websocket.CloseInternalServerErr
+			if st.Code() == codes.Unavailable || st.Code() == codes.ResourceExhausted {
+				closeCode = websocket.CloseTryAgainLater
+			}
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, st.Message()),
+				time.Now().Add(writeTimeout))
+			return
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// receive forwards the responses of the stream until ctx is done, then its final error
+func receive(ctx context.Context, stream pb.UserWatchService_WatchUsersClient) (<-chan *pb.WatchUsersResponse, <-chan error) {
+	events := make(chan *pb.WatchUsersResponse)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			res, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case events <- res:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, errs
+}
+
+func watchUsersRequest(r *http.Request) (*pb.WatchUsersRequest, error) {
+	query := r.URL.Query()
+	req := &pb.WatchUsersRequest{UserIds: query["user_ids"]}
+	for _, name := range query["operation_types"] {
+		operationType, ok := pb.OperationType_value[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown operation type %q", name)
+		}
+		req.OperationTypes = append(req.OperationTypes, pb.OperationType(operationType))
+	}
+	if query.Has("country") {
+		country := query.Get("country")
+		req.Country = &country
+	}
+
+	lastEventId := r.Header.Get(LastEventIdHeader)
+	if lastEventId == "" {
+		lastEventId = query.Get(LastEventIdParam)
+	}
+	if lastEventId != "" {
+		req.ResumeFrom = &pb.WatchUsersRequest_AfterEventId{AfterEventId: lastEventId}
+	}
+	return req, nil
+}
+
+func outgoingMetadata(r *http.Request) metadata.MD {
+	md := metadata.MD{}
+	for _, key := range forwardedHeaders {
+		if value := r.Header.Get(key); value != "" {
+			md.Set(strings.ToLower(key), value)
+		}
+	}
+	return md
+}
+
+// writeError replies with the gRPC status of err, as the gateway does
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	data, _ := protojson.Marshal(st.Proto())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,193 @@
+//go:build unit
+
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	httpServer "github.com/flapenna/go-ddd-crud/internal/interfaces/http"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// adminVerifier authenticates the token of the admin only
+type adminVerifier struct{}
+
+func (adminVerifier) VerifyToken(token string) (*domain.Actor, error) {
+	if token != "admin-token" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.Actor{ID: "admin-1", Role: domain.ActorRoleAdmin}, nil
+}
+
+// newUserEventsServer serves the user event feed of the WatchUsers RPC of a gRPC server using userService
+func newUserEventsServer(t *testing.T, userService domain.UserService) *httptest.Server {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.StreamInterceptor(grpcServer.ActorStreamInterceptor(adminVerifier{})))
+	watchServer := grpcServer.NewUserWatchServiceServer(userService)
+	pb.RegisterUserWatchServiceServer(server, watchServer)
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(httpServer.NewUserEventsHandler(pb.NewUserWatchServiceClient(conn), 10*time.Millisecond))
+	t.Cleanup(func() {
+		httpServer.Close()
+		conn.Close()
+		watchServer.Stop()
+		server.Stop()
+	})
+	return httpServer
+}
+
+// adminWatching matches the context of the admin calling WatchUsers
+func adminWatching(ctx context.Context) bool {
+	actor := domain.ActorFromContext(ctx)
+	return actor != nil && actor.ID == "admin-1" && actor.IsAdmin()
+}
+
+func TestUserEventsHandler_EventStream(t *testing.T) {
+	userId := uuid.NewString()
+	broadcaster := domain.NewUserEventBroadcaster(10, 10)
+	subscription, err := broadcaster.Subscribe(&domain.WatchUsersRequest{})
+	require.NoError(t, err)
+	mockService := new(mocks.MockUserService)
+	mockService.On("WatchUsers", mock.MatchedBy(adminWatching), &domain.WatchUsersRequest{
+		UserIds:        []string{userId},
+		OperationTypes: []domain.OperationType{domain.OPERATION_UPDATE},
+		AfterEventId:   "event-0",
+	}).Return(subscription, nil)
+	server := newUserEventsServer(t, mockService)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"?user_ids="+userId+"&operation_types=OPERATION_UPDATE", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set(httpServer.LastEventIdHeader, "event-0")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	broadcaster.Broadcast(&domain.UserEvent{Id: "event-1", UserId: userId, OperationType: domain.OPERATION_UPDATE,
+		AfterChange: &domain.User{ID: userId, Country: "IT", Version: 2}})
+	lines := bufio.NewScanner(res.Body)
+	fields := readServerSentEvent(t, lines)
+	assert.Equal(t, "event-1", fields["id"])
+	event := &pb.UserEvent{}
+	require.NoError(t, protojson.Unmarshal([]byte(fields["data"]), event))
+	assert.Equal(t, userId, event.UserId)
+	assert.Equal(t, pb.OperationType_OPERATION_UPDATE, event.OperationType)
+	assert.Equal(t, "IT", event.AfterChange.Country)
+	// The fields are named as by the REST endpoints
+	assert.Contains(t, fields["data"], `"user_id"`)
+
+	// The end of the stream is sent as an error event
+	broadcaster.Close()
+	fields = readServerSentEvent(t, lines)
+	assert.Equal(t, "error", fields["event"])
+	var errorStatus struct{ Code int }
+	require.NoError(t, json.Unmarshal([]byte(fields["data"]), &errorStatus))
+	assert.Equal(t, 14, errorStatus.Code)
+}
+
+// readServerSentEvent returns the fields of the next event, skipping the heartbeats
+func readServerSentEvent(t *testing.T, lines *bufio.Scanner) map[string]string {
+	fields := make(map[string]string)
+	for lines.Scan() {
+		line := lines.Text()
+		switch {
+		case line == "" && len(fields) > 0:
+			return fields
+		case line == "", strings.HasPrefix(line, ":"):
+		default:
+			name, value, _ := strings.Cut(line, ": ")
+			fields[name] = value
+		}
+	}
+	t.Fatalf("event stream ended: %v", lines.Err())
+	return nil
+}
+
+func TestUserEventsHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		mockError  error
+		wantStatus int
+	}{
+		{name: "unknown operation type", query: "?operation_types=OPERATION_RENAME", wantStatus: http.StatusBadRequest},
+		{name: "invalid user id", query: "?user_ids=user-123", wantStatus: http.StatusBadRequest},
+		{name: "permission denied", mockError: domain.ErrPermissionDenied, wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", mockError: domain.ErrUnauthenticated, wantStatus: http.StatusUnauthorized},
+		{name: "resume point unavailable", query: "?last_event_id=event-0", mockError: domain.ErrWatchResumeUnavailable,
+			wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockUserService)
+			if tt.mockError != nil {
+				mockService.On("WatchUsers", mock.Anything, mock.Anything).Return(nil, tt.mockError)
+			}
+			server := newUserEventsServer(t, mockService)
+
+			res, err := http.Get(server.URL + tt.query)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+			assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserEventsHandler_WebSocket(t *testing.T) {
+	broadcaster := domain.NewUserEventBroadcaster(10, 10)
+	subscription, err := broadcaster.Subscribe(&domain.WatchUsersRequest{})
+	require.NoError(t, err)
+	mockService := new(mocks.MockUserService)
+	mockService.On("WatchUsers", mock.MatchedBy(adminWatching), &domain.WatchUsersRequest{AfterEventId: "event-0"}).
+		Return(subscription, nil)
+	server := newUserEventsServer(t, mockService)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer admin-token")
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?" + httpServer.LastEventIdParam + "=event-0"
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	broadcaster.Broadcast(&domain.UserEvent{Id: "event-1", UserId: "user-123", OperationType: domain.OPERATION_CREATE})
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	messageType, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, messageType)
+	event := &pb.UserEvent{}
+	require.NoError(t, protojson.Unmarshal(data, event))
+	assert.Equal(t, "event-1", event.Id)
+
+	// The end of the stream closes the connection, the client can reconnect later
+	broadcaster.Close()
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), err)
+}
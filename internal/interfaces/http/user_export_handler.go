@@ -0,0 +1,126 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"net/http"
+	"time"
+)
+
+// UserExportPath is the path of the downloadable data export of a user on the HTTP gateway
+const UserExportPath = "/api/v1/users/{id}/export.zip"
+
+// UserExportHandler serves the ExportMyData RPC as a zip bundle, holding a JSON file per section of
+// the export: the profile, the version history and the audit trail, described by a manifest
+type UserExportHandler struct {
+	client    pb.UserServiceClient
+	marshaler protojson.MarshalOptions
+}
+
+// exportManifest describes the bundle, as manifest.json
+type exportManifest struct {
+	UserId     string   `json:"user_id"`
+	ExportedAt string   `json:"exported_at"`
+	Deleted    bool     `json:"deleted"`
+	Files      []string `json:"files"`
+}
+
+func NewUserExportHandler(client pb.UserServiceClient) *UserExportHandler {
+	return &UserExportHandler{
+		client:    client,
+		marshaler: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true, Multiline: true},
+	}
+}
+
+// ServeExport replies with the bundle of the user of the id path parameter, as an attachment. The
+// caller is authorized and the export audited by the RPC, as for the JSON export.
+func (h *UserExportHandler) ServeExport(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	log.Infof("[HTTP] UserExport called with id %s", pathParams["id"])
+	ctx := metadata.NewOutgoingContext(r.Context(), outgoingMetadata(r))
+	export, err := h.client.ExportMyData(ctx, &pb.ExportMyDataRequest{Id: pathParams["id"]})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	bundle, err := h.bundle(pathParams["id"], export)
+	if err != nil {
+		log.Errorf("failed to bundle user data export: %v", err)
+		writeError(w, status.Error(codes.Internal, "internal server error"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-%s-export.zip\"", pathParams["id"]))
+	_, _ = w.Write(bundle)
+}
+
+// bundle zips the sections of the export, built in memory for a failure to be reported before replying
+func (h *UserExportHandler) bundle(userId string, export *pb.ExportMyDataResponse) ([]byte, error) {
+	versions := make([]proto.Message, len(export.Versions))
+	for i, version := range export.Versions {
+		versions[i] = version
+	}
+	auditEntries := make([]proto.Message, len(export.AuditEntries))
+	for i, entry := range export.AuditEntries {
+		auditEntries[i] = entry
+	}
+
+	files := make(map[string][]byte)
+	var err error
+	if files["profile.json"], err = h.marshaler.Marshal(export.Profile); err != nil {
+		return nil, err
+	}
+	if files["versions.json"], err = h.marshalArray(versions); err != nil {
+		return nil, err
+	}
+	if files["audit_entries.json"], err = h.marshalArray(auditEntries); err != nil {
+		return nil, err
+	}
+	manifest := exportManifest{
+		UserId:     userId,
+		ExportedAt: export.ExportedAt.AsTime().Format(time.RFC3339Nano),
+		Deleted:    export.Deleted,
+		Files:      []string{"profile.json", "versions.json", "audit_entries.json"},
+	}
+	if files["manifest.json"], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range append([]string{"manifest.json"}, manifest.Files...) {
+		file, err := archive.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalArray encodes the messages as a JSON array, protojson only encoding single messages
+func (h *UserExportHandler) marshalArray(messages []proto.Message) ([]byte, error) {
+	array := make([]json.RawMessage, len(messages))
+	for i, message := range messages {
+		data, err := h.marshaler.Marshal(message)
+		if err != nil {
+			return nil, err
+		}
+		array[i] = data
+	}
+	return json.MarshalIndent(array, "", "  ")
+}
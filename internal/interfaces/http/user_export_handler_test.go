@@ -0,0 +1,136 @@
+//go:build unit
+
+package http_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	httpServer "github.com/flapenna/go-ddd-crud/internal/interfaces/http"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newUserExportServer serves the export bundle of the ExportMyData RPC of a gRPC server using userService,
+// from a gateway mux along with the JSON export
+func newUserExportServer(t *testing.T, userService domain.UserService) *httptest.Server {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcServer.ActorUnaryInterceptor(adminVerifier{})))
+	pb.RegisterUserServiceServer(server, grpcServer.NewUserServiceServer(userService))
+	go server.Serve(lis)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	gwMux := runtime.NewServeMux()
+	require.NoError(t, pb.RegisterUserServiceHandler(context.Background(), gwMux, conn))
+	handler := httpServer.NewUserExportHandler(pb.NewUserServiceClient(conn))
+	require.NoError(t, gwMux.HandlePath(http.MethodGet, httpServer.UserExportPath, handler.ServeExport))
+	httpServer := httptest.NewServer(gwMux)
+	t.Cleanup(func() {
+		httpServer.Close()
+		conn.Close()
+		server.Stop()
+	})
+	return httpServer
+}
+
+func TestUserExportHandler_ServeExport(t *testing.T) {
+	userId := uuid.NewString()
+	now := time.Now().UTC().Round(time.Millisecond)
+	user := &domain.User{ID: userId, FirstName: "Federico", CreatedAt: now, UpdatedAt: now, Version: 2}
+	mockService := new(mocks.MockUserService)
+	adminExporting := func(ctx context.Context) bool {
+		actor := domain.ActorFromContext(ctx)
+		return actor != nil && actor.ID == "admin-1" && actor.IsAdmin()
+	}
+	mockService.On("ExportUserData", mock.MatchedBy(adminExporting), userId).Return(&domain.UserDataExport{
+		User: user,
+		Versions: []*domain.UserVersion{
+			{UserId: userId, Version: 1, User: user, ValidFrom: now},
+			{UserId: userId, Version: 2, User: user, ValidFrom: now},
+		},
+		AuditEntries: []*domain.UserAuditEntry{
+			{Id: "event-1", UserId: userId, AfterChange: user, OperationType: domain.OPERATION_CREATE, RecordedAt: now},
+		},
+		ExportedAt: now,
+	}, nil)
+	server := newUserExportServer(t, mockService)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/users/"+userId+"/export.zip", nil)
+	require.NoError(t, err)
+	req.Header.Set(grpcServer.AuthorizationMetadataKey, "Bearer admin-token")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/zip", res.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="user-`+userId+`-export.zip"`, res.Header.Get("Content-Disposition"))
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		files[file.Name], err = io.ReadAll(reader)
+		require.NoError(t, err)
+		reader.Close()
+	}
+
+	var manifest struct {
+		UserId     string   `json:"user_id"`
+		ExportedAt string   `json:"exported_at"`
+		Deleted    bool     `json:"deleted"`
+		Files      []string `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, userId, manifest.UserId)
+	assert.Equal(t, now.Format(time.RFC3339Nano), manifest.ExportedAt)
+	assert.False(t, manifest.Deleted)
+	assert.Equal(t, []string{"profile.json", "versions.json", "audit_entries.json"}, manifest.Files)
+
+	var profile map[string]any
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, "Federico", profile["first_name"])
+	var versions, auditEntries []map[string]any
+	require.NoError(t, json.Unmarshal(files["versions.json"], &versions))
+	assert.Len(t, versions, 2)
+	require.NoError(t, json.Unmarshal(files["audit_entries.json"], &auditEntries))
+	require.Len(t, auditEntries, 1)
+	assert.Equal(t, "USER_AUDIT_ACTION_CREATE", auditEntries[0]["operation_type"])
+}
+
+func TestUserExportHandler_ServeExportDenied(t *testing.T) {
+	userId := uuid.NewString()
+	mockService := new(mocks.MockUserService)
+	mockService.On("ExportUserData", mock.Anything, userId).Return(nil, domain.ErrUnauthenticated)
+	server := newUserExportServer(t, mockService)
+
+	// The caller is authorized by the RPC, as for the JSON export, and no bundle is sent
+	res, err := http.Get(server.URL + "/api/v1/users/" + userId + "/export.zip")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Empty(t, res.Header.Get("Content-Disposition"))
+}
@@ -0,0 +1,180 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"strconv"
+	"time"
+)
+
+// Headers added to the dead lettered commands, next to their original headers
+const (
+	DeadLetterErrorHeader     = "dlq_error"
+	DeadLetterTopicHeader     = "dlq_source_topic"
+	DeadLetterPartitionHeader = "dlq_source_partition"
+	DeadLetterOffsetHeader    = "dlq_source_offset"
+)
+
+const (
+	contentTypeHeader   = "content-type"
+	protobufContentType = "application/x-protobuf"
+
+	readPollTimeout = 100 * time.Millisecond
+)
+
+// UserCommandConsumerOptions names the topics of the user command consumer
+type UserCommandConsumerOptions struct {
+	CommandTopic    string
+	ResultTopic     string
+	DeadLetterTopic string
+	// RetryBackoff is the wait between two attempts to deliver a result or a dead letter
+	RetryBackoff time.Duration
+}
+
+// UserCommandConsumer runs the commands of the command topic and publishes their results
+type UserCommandConsumer struct {
+	consumer *kafka.Consumer
+	producer *kafka.Producer
+	handler  *UserCommandHandler
+	opts     UserCommandConsumerOptions
+}
+
+// NewUserCommandConsumer creates a consumer of the commands. The consumer must not commit its
+// offsets automatically; producer is only used with its own delivery channels, so it can be
+// shared with the user producer.
+func NewUserCommandConsumer(consumer *kafka.Consumer, producer *kafka.Producer, handler *UserCommandHandler,
+	opts UserCommandConsumerOptions) *UserCommandConsumer {
+	return &UserCommandConsumer{
+		consumer: consumer,
+		producer: producer,
+		handler:  handler,
+		opts:     opts,
+	}
+}
+
+// Run consumes the commands until ctx is done. The offset of a command is committed once its
+// result, and its dead letter when it failed, are delivered: a command interrupted in between
+// is handled again after a restart. A command that could not be handled, e.g. while the processed
+// commands are unavailable, is handled again after RetryBackoff, and the next ones wait.
+// An error is returned when the consumer cannot go on.
+func (c *UserCommandConsumer) Run(ctx context.Context) error {
+	if err := c.consumer.Subscribe(c.opts.CommandTopic, nil); err != nil {
+		return err
+	}
+	for ctx.Err() == nil {
+		msg, err := c.consumer.ReadMessage(readPollTimeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if !errors.As(err, &kafkaErr) || !kafkaErr.IsTimeout() {
+				log.Errorf("Kafka consumer error: %v", err)
+			}
+			continue
+		}
+
+		if err := c.process(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				// Stopped before the results were delivered, the command is not committed
+				return nil
+			}
+			log.Errorf("Failed to handle user command %s, retrying in %s: %v", msg.TopicPartition, c.opts.RetryBackoff, err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(c.opts.RetryBackoff):
+			}
+			// Read the command again
+			if err := c.consumer.Seek(msg.TopicPartition, 0); err != nil {
+				return fmt.Errorf("failed to seek back to user command %s: %w", msg.TopicPartition, err)
+			}
+			continue
+		}
+		if _, err := c.consumer.CommitMessage(msg); err != nil {
+			log.Errorf("Failed to commit user command %s: %v", msg.TopicPartition, err)
+		}
+	}
+	return nil
+}
+
+func (c *UserCommandConsumer) process(ctx context.Context, msg *kafka.Message) error {
+	// A started command completes even when the consumer is stopping
+	result, handleErr := c.handler.Handle(context.WithoutCancel(ctx), msg)
+	if errors.Is(handleErr, errProcessedCommands) {
+		return handleErr
+	}
+	if result != nil {
+		value, err := proto.Marshal(result)
+		if err != nil {
+			return err
+		}
+		topic := c.opts.ResultTopic
+		err = c.publish(ctx, &kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+			Key:            []byte(result.CommandId),
+			Value:          value,
+			Headers: []kafka.Header{
+				{Key: contentTypeHeader, Value: []byte(protobufContentType)},
+				{Key: CorrelationIdHeader, Value: []byte(correlationId(result.CommandId, msg.Headers))},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if handleErr != nil {
+		log.Errorf("User command %s dead lettered: %v", msg.TopicPartition, handleErr)
+		return c.publish(ctx, c.deadLetter(msg, handleErr))
+	}
+	return nil
+}
+
+// deadLetter is the original command, with the error and its position in the command topic
+func (c *UserCommandConsumer) deadLetter(msg *kafka.Message, err error) *kafka.Message {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+4)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: DeadLetterErrorHeader, Value: []byte(err.Error())},
+		kafka.Header{Key: DeadLetterTopicHeader, Value: []byte(*msg.TopicPartition.Topic)},
+		kafka.Header{Key: DeadLetterPartitionHeader, Value: []byte(strconv.Itoa(int(msg.TopicPartition.Partition)))},
+		kafka.Header{Key: DeadLetterOffsetHeader, Value: []byte(strconv.FormatInt(int64(msg.TopicPartition.Offset), 10))},
+	)
+	topic := c.opts.DeadLetterTopic
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}
+}
+
+// publish delivers the message, retrying until it is delivered or ctx is done
+func (c *UserCommandConsumer) publish(ctx context.Context, msg *kafka.Message) error {
+	for {
+		err := c.deliver(msg)
+		if err == nil {
+			return nil
+		}
+		log.Errorf("Failed to deliver message to %s, retrying in %s: %v", *msg.TopicPartition.Topic, c.opts.RetryBackoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.opts.RetryBackoff):
+		}
+	}
+}
+
+func (c *UserCommandConsumer) deliver(msg *kafka.Message) error {
+	delivered := make(chan kafka.Event, 1)
+	if err := c.producer.Produce(msg, delivered); err != nil {
+		return err
+	}
+	report, ok := (<-delivered).(*kafka.Message)
+	if !ok {
+		return errors.New("unexpected delivery report")
+	}
+	return report.TopicPartition.Error
+}
@@ -0,0 +1,163 @@
+//go:build integration
+
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	kafkaServer "github.com/flapenna/go-ddd-crud/internal/interfaces/kafka"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"testing"
+	"time"
+)
+
+const (
+	redpandaImage   = "docker.vectorized.io/vectorized/redpanda:v21.8.1"
+	redpandaPort    = "29092"
+	commandTopic    = "go-ddd-crud_user-commands"
+	resultTopic     = "go-ddd-crud_user-command-results"
+	deadLetterTopic = "go-ddd-crud_user-commands-dlq"
+)
+
+type UserCommandConsumerTestSuite struct {
+	suite.Suite
+	kafkaC           testcontainers.Container
+	bootstrapServers string
+	producer         *kafka.Producer
+	ctx              context.Context
+	cancel           context.CancelFunc
+}
+
+func (suite *UserCommandConsumerTestSuite) SetupSuite() {
+	// Another port than the producer tests, which may run in parallel
+	req := testcontainers.ContainerRequest{
+		Image:        redpandaImage,
+		ExposedPorts: []string{redpandaPort + ":" + redpandaPort + "/tcp"},
+		Cmd: []string{"redpanda", "start",
+			"--kafka-addr", "PLAINTEXT://0.0.0.0:" + redpandaPort,
+			"--advertise-kafka-addr", "PLAINTEXT://localhost:" + redpandaPort},
+		WaitingFor: wait.ForLog("Successfully started Redpanda!"),
+	}
+	kafkaC, err := testcontainers.GenericContainer(context.Background(), testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	suite.Require().NoError(err)
+
+	suite.bootstrapServers = fmt.Sprintf("localhost:%s", redpandaPort)
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": suite.bootstrapServers})
+	suite.Require().NoError(err)
+
+	suite.kafkaC = kafkaC
+	suite.producer = producer
+	suite.ctx, suite.cancel = context.WithTimeout(context.Background(), 60*time.Second)
+}
+
+func (suite *UserCommandConsumerTestSuite) TearDownSuite() {
+	suite.cancel()
+	suite.producer.Close()
+	suite.kafkaC.Terminate(context.Background())
+}
+
+func (suite *UserCommandConsumerTestSuite) TestUserCommandConsumer_Run() {
+	userId := uuid.NewString()
+	mockService := new(mocks.MockUserService)
+	mockService.On("DeleteUser", mock.Anything, userId).Return(domain.ErrUserNotFound).Once()
+
+	commandConsumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  suite.bootstrapServers,
+		"group.id":           "test-commands-group",
+		"enable.auto.commit": false,
+		"auto.offset.reset":  "earliest",
+	})
+	suite.Require().NoError(err)
+	defer commandConsumer.Close()
+	consumer := kafkaServer.NewUserCommandConsumer(commandConsumer, suite.producer,
+		kafkaServer.NewUserCommandHandler(grpcServer.NewUserServiceServer(mockService), nil, nil),
+		kafkaServer.UserCommandConsumerOptions{
+			CommandTopic:    commandTopic,
+			ResultTopic:     resultTopic,
+			DeadLetterTopic: deadLetterTopic,
+			RetryBackoff:    100 * time.Millisecond,
+		})
+
+	// A command to run, then a message which is not a command
+	command, err := proto.Marshal(&pb.UserCommand{Id: "command-1", Command: &pb.UserCommand_DeleteUser{DeleteUser: &pb.DeleteUserRequest{Id: userId}}})
+	suite.Require().NoError(err)
+	suite.produce(commandTopic, command)
+	suite.produce(commandTopic, []byte("not a command"))
+
+	ctx, cancel := context.WithCancel(suite.ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Run(ctx)
+	}()
+
+	// The command failed with the error of the RPC
+	message := suite.readMessage(resultTopic)
+	suite.Equal("command-1", string(message.Key))
+	result := &pb.UserCommandResult{}
+	suite.Require().NoError(proto.Unmarshal(message.Value, result))
+	suite.Equal("command-1", result.CommandId)
+	suite.Equal(int32(codes.NotFound), result.Status.Code)
+
+	// The undecodable message is dead lettered, with its origin
+	message = suite.readMessage(deadLetterTopic)
+	suite.Equal([]byte("not a command"), message.Value)
+	headers := make(map[string]string)
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	suite.Equal(commandTopic, headers[kafkaServer.DeadLetterTopicHeader])
+	suite.Equal("1", headers[kafkaServer.DeadLetterOffsetHeader])
+	suite.Contains(headers[kafkaServer.DeadLetterErrorHeader], "failed to decode user command")
+
+	cancel()
+	suite.NoError(<-done)
+	mockService.AssertExpectations(suite.T())
+
+	// Both messages were committed once processed
+	committed, err := commandConsumer.Committed([]kafka.TopicPartition{{Topic: &[]string{commandTopic}[0], Partition: 0}}, 10000)
+	suite.Require().NoError(err)
+	suite.Equal(kafka.Offset(2), committed[0].Offset)
+}
+
+func (suite *UserCommandConsumerTestSuite) produce(topic string, value []byte) {
+	delivered := make(chan kafka.Event, 1)
+	err := suite.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          value,
+	}, delivered)
+	suite.Require().NoError(err)
+	suite.Require().NoError((<-delivered).(*kafka.Message).TopicPartition.Error)
+}
+
+func (suite *UserCommandConsumerTestSuite) readMessage(topic string) *kafka.Message {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": suite.bootstrapServers,
+		"group.id":          "test-" + topic,
+		"auto.offset.reset": "earliest",
+	})
+	suite.Require().NoError(err)
+	defer consumer.Close()
+	suite.Require().NoError(consumer.Subscribe(topic, nil))
+
+	message, err := consumer.ReadMessage(30 * time.Second)
+	suite.Require().NoError(err)
+	return message
+}
+
+func TestUserCommandConsumerTestSuite(t *testing.T) {
+	suite.Run(t, new(UserCommandConsumerTestSuite))
+}
@@ -0,0 +1,195 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Headers of the command messages, the counterparts of the gRPC metadata of the RPCs
+const (
+	// AuthorizationHeader carries the bearer token of the sender, as the authorization metadata
+	AuthorizationHeader = "authorization"
+	CorrelationIdHeader = "correlation_id"
+	TraceParentHeader   = "traceparent"
+)
+
+// errProcessedCommands fails the commands which could not be checked against the processed ones,
+// to be handled again rather than to run twice or be dead lettered
+var errProcessedCommands = errors.New("failed to check the processed user commands")
+
+// UserCommandHandler runs the user commands through the gRPC handlers, so that a command is
+// validated and fails exactly as the equivalent RPC
+type UserCommandHandler struct {
+	server   pb.UserServiceServer
+	verifier domain.ActorTokenVerifier
+	commands domain.ProcessedUserCommandRepository
+}
+
+// NewUserCommandHandler creates a handler authenticating the senders with verifier, every command
+// being anonymous without verifier. The results are recorded in commands, for a command sent again
+// with the same id to get its first result rather than to run twice. Without commands, every
+// message runs.
+func NewUserCommandHandler(server pb.UserServiceServer, verifier domain.ActorTokenVerifier,
+	commands domain.ProcessedUserCommandRepository) *UserCommandHandler {
+	return &UserCommandHandler{server: server, verifier: verifier, commands: commands}
+}
+
+// Handle runs the command of the message and returns its result. An error is also returned
+// when the message should be dead lettered: when it cannot be decoded, without a result,
+// or when the command failed on the server side rather than because of the command.
+// A command processed already is not run again, its recorded result is returned instead.
+func (h *UserCommandHandler) Handle(ctx context.Context, msg *kafka.Message) (*pb.UserCommandResult, error) {
+	command := &pb.UserCommand{}
+	if err := proto.Unmarshal(msg.Value, command); err != nil {
+		return nil, fmt.Errorf("failed to decode user command: %w", err)
+	}
+	log.Infof("[KAFKA] UserCommand %s received", command.Id)
+
+	if processed, err := h.processedResult(ctx, command.Id); err != nil || processed != nil {
+		return processed, err
+	}
+
+	ctx, err := h.commandContext(ctx, command.Id, msg.Headers)
+	var user *pb.User
+	if err == nil {
+		user, err = h.run(ctx, command)
+	}
+	commandStatus := status.New(codes.OK, "")
+	if err != nil {
+		commandStatus = status.Convert(err)
+	}
+	result := &pb.UserCommandResult{
+		CommandId:   command.Id,
+		Status:      commandStatus.Proto(),
+		User:        user,
+		ProcessedAt: timestamppb.Now(),
+	}
+	if isServerError(err) {
+		// Not recorded, the command runs again when redriven
+		return result, err
+	}
+	h.record(ctx, result)
+	return result, nil
+}
+
+// processedResult returns the recorded result of the command, nil when it has not been processed
+func (h *UserCommandHandler) processedResult(ctx context.Context, commandId string) (*pb.UserCommandResult, error) {
+	if h.commands == nil || commandId == "" {
+		return nil, nil
+	}
+	processed, err := h.commands.GetProcessedUserCommand(ctx, commandId)
+	if errors.Is(err, domain.ErrUserCommandNotProcessed) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errProcessedCommands, err)
+	}
+	result := &pb.UserCommandResult{}
+	if err := proto.Unmarshal(processed.Result, result); err != nil {
+		return nil, fmt.Errorf("failed to decode the recorded result of user command %s: %w", commandId, err)
+	}
+	log.Infof("[KAFKA] UserCommand %s already processed at %s, publishing its result again", commandId, processed.ProcessedAt)
+	return result, nil
+}
+
+// record stores the result of the command. A command whose result could not be recorded runs
+// again if it is sent again.
+func (h *UserCommandHandler) record(ctx context.Context, result *pb.UserCommandResult) {
+	if h.commands == nil || result.CommandId == "" {
+		return
+	}
+	value, err := proto.Marshal(result)
+	if err == nil {
+		err = h.commands.RecordProcessedUserCommand(ctx, &domain.ProcessedUserCommand{
+			Id:          result.CommandId,
+			Result:      value,
+			ProcessedAt: result.ProcessedAt.AsTime(),
+		})
+	}
+	switch {
+	case errors.Is(err, domain.ErrUserCommandAlreadyProcessed):
+		log.Warnf("User command %s was processed concurrently", result.CommandId)
+	case err != nil:
+		log.Errorf("Failed to record user command %s, it runs again if sent again: %v", result.CommandId, err)
+	}
+}
+
+func (h *UserCommandHandler) run(ctx context.Context, command *pb.UserCommand) (*pb.User, error) {
+	if err := command.Validate(); err != nil {
+		log.Errorf("failed to validate user command: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	switch c := command.Command.(type) {
+	case *pb.UserCommand_CreateUser:
+		return h.server.CreateUser(ctx, c.CreateUser)
+	case *pb.UserCommand_UpdateUser:
+		return h.server.UpdateUser(ctx, c.UpdateUser)
+	case *pb.UserCommand_DeleteUser:
+		_, err := h.server.DeleteUser(ctx, c.DeleteUser)
+		return nil, err
+	default:
+		return nil, status.Error(codes.InvalidArgument, "unknown user command")
+	}
+}
+
+// commandContext stores the actor, the correlation id and the trace context of the command
+// headers in the context, as the actor interceptor does for the RPCs. The command id is the
+// correlation id unless one is provided. A command with an invalid token fails as unauthenticated.
+func (h *UserCommandHandler) commandContext(ctx context.Context, commandId string, headers []kafka.Header) (context.Context, error) {
+	ctx = domain.ContextWithCorrelationId(ctx, correlationId(commandId, headers))
+	if traceParent := headerValue(headers, TraceParentHeader); traceParent != "" {
+		ctx = domain.ContextWithTraceParent(ctx, traceParent)
+	}
+
+	authorization := headerValue(headers, AuthorizationHeader)
+	if h.verifier == nil || authorization == "" {
+		return ctx, nil
+	}
+	token, ok := domain.BearerToken(authorization)
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, domain.ErrInvalidToken.Error())
+	}
+	actor, err := h.verifier.VerifyToken(token)
+	if err != nil {
+		log.Warnf("Rejecting user command %s: %v", commandId, err)
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return domain.ContextWithActor(ctx, actor), nil
+}
+
+// isServerError tells whether the command failed regardless of its content, and may succeed when redriven
+func isServerError(err error) bool {
+	switch status.Code(err) {
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DeadlineExceeded, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// correlationId is the correlation id header of the command, defaulting to its id
+func correlationId(commandId string, headers []kafka.Header) string {
+	if correlationId := headerValue(headers, CorrelationIdHeader); correlationId != "" {
+		return correlationId
+	}
+	return commandId
+}
+
+func headerValue(headers []kafka.Header, key string) string {
+	for _, header := range headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
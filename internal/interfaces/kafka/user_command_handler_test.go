@@ -0,0 +1,253 @@
+//go:build unit
+
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	grpcServer "github.com/flapenna/go-ddd-crud/internal/interfaces/grpc"
+	kafkaServer "github.com/flapenna/go-ddd-crud/internal/interfaces/kafka"
+	"github.com/flapenna/go-ddd-crud/mocks"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUserCommandHandler_Handle(t *testing.T) {
+	now := time.Now()
+	userId := uuid.NewString()
+	createUser := &pb.CreateUserRequest{
+		FirstName: "Federico",
+		LastName:  "La Penna",
+		Email:     "flapenna@email.com",
+		Country:   "IT",
+		Nickname:  "Pennino",
+		Password:  "password",
+	}
+	createdUser := &domain.User{ID: userId, FirstName: "Federico", Version: 1, CreatedAt: now, UpdatedAt: now}
+	headers := []kafka.Header{
+		{Key: kafkaServer.AuthorizationHeader, Value: []byte("Bearer admin-token")},
+		{Key: kafkaServer.TraceParentHeader, Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+	}
+
+	tests := []struct {
+		name           string
+		value          []byte
+		headers        []kafka.Header
+		setupMock      func(*mocks.MockUserService)
+		wantCode       codes.Code
+		wantUserId     string
+		wantDeadLetter bool
+		wantNoResult   bool
+	}{
+		{
+			name:    "user created",
+			value:   commandValue(t, &pb.UserCommand{Id: "command-1", Command: &pb.UserCommand_CreateUser{CreateUser: createUser}}),
+			headers: headers,
+			setupMock: func(mockService *mocks.MockUserService) {
+				mockService.On("CreateUser", mock.MatchedBy(func(ctx context.Context) bool {
+					return domain.ActorIdFromContext(ctx) == "admin-1" && domain.CorrelationIdFromContext(ctx) == "command-1" &&
+						domain.TraceParentFromContext(ctx) != ""
+				}), mock.AnythingOfType("*domain.User")).Return(createdUser, nil).Once()
+			},
+			wantCode:   codes.OK,
+			wantUserId: userId,
+		},
+		{
+			name:    "user deleted with the correlation id header",
+			value:   commandValue(t, &pb.UserCommand{Id: "command-2", Command: &pb.UserCommand_DeleteUser{DeleteUser: &pb.DeleteUserRequest{Id: userId}}}),
+			headers: []kafka.Header{{Key: kafkaServer.CorrelationIdHeader, Value: []byte("request-1")}},
+			setupMock: func(mockService *mocks.MockUserService) {
+				mockService.On("DeleteUser", mock.MatchedBy(func(ctx context.Context) bool {
+					return domain.CorrelationIdFromContext(ctx) == "request-1"
+				}), userId).Return(nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "user not found",
+			value: commandValue(t, &pb.UserCommand{Id: "command-3", Command: &pb.UserCommand_UpdateUser{UpdateUser: &pb.UpdateUserRequest{
+				Id: userId, FirstName: "Federico", LastName: "La Penna", Email: "flapenna@email.com", Country: "IT", Nickname: "Pennino",
+			}}}),
+			setupMock: func(mockService *mocks.MockUserService) {
+				mockService.On("UpdateUser", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil, domain.ErrUserNotFound).Once()
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:      "invalid command",
+			value:     commandValue(t, &pb.UserCommand{Id: "command-4", Command: &pb.UserCommand_CreateUser{CreateUser: &pb.CreateUserRequest{}}}),
+			setupMock: func(*mocks.MockUserService) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:      "missing command",
+			value:     commandValue(t, &pb.UserCommand{Id: "command-5"}),
+			setupMock: func(*mocks.MockUserService) {},
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			name:      "invalid token",
+			value:     commandValue(t, &pb.UserCommand{Id: "command-7", Command: &pb.UserCommand_CreateUser{CreateUser: createUser}}),
+			headers:   []kafka.Header{{Key: kafkaServer.AuthorizationHeader, Value: []byte("Bearer forged-token")}},
+			setupMock: func(*mocks.MockUserService) {},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name:    "former identity headers",
+			value:   commandValue(t, &pb.UserCommand{Id: "command-8", Command: &pb.UserCommand_DeleteUser{DeleteUser: &pb.DeleteUserRequest{Id: userId}}}),
+			headers: []kafka.Header{{Key: "actor_id", Value: []byte("admin-1")}, {Key: "actor_role", Value: []byte(domain.ActorRoleAdmin)}},
+			setupMock: func(mockService *mocks.MockUserService) {
+				mockService.On("DeleteUser", mock.MatchedBy(func(ctx context.Context) bool {
+					return domain.ActorFromContext(ctx) == nil
+				}), userId).Return(nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:  "service error",
+			value: commandValue(t, &pb.UserCommand{Id: "command-6", Command: &pb.UserCommand_CreateUser{CreateUser: createUser}}),
+			setupMock: func(mockService *mocks.MockUserService) {
+				mockService.On("CreateUser", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil, errors.New("mongo error")).Once()
+			},
+			wantCode:       codes.Internal,
+			wantDeadLetter: true,
+		},
+		{
+			name:           "undecodable message",
+			value:          []byte("not a command"),
+			setupMock:      func(*mocks.MockUserService) {},
+			wantDeadLetter: true,
+			wantNoResult:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockUserService)
+			tt.setupMock(mockService)
+			handler := kafkaServer.NewUserCommandHandler(grpcServer.NewUserServiceServer(mockService), verifier, nil)
+
+			result, err := handler.Handle(context.TODO(), &kafka.Message{Value: tt.value, Headers: tt.headers})
+
+			assert.Equal(t, tt.wantDeadLetter, err != nil)
+			if tt.wantNoResult {
+				assert.Nil(t, result)
+				return
+			}
+			assert.NotEmpty(t, result.CommandId)
+			assert.Equal(t, int32(tt.wantCode), result.Status.Code)
+			assert.NotNil(t, result.ProcessedAt)
+			if tt.wantUserId != "" {
+				assert.Equal(t, tt.wantUserId, result.User.Id)
+			} else {
+				assert.Nil(t, result.User)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserCommandHandler_ProcessedCommands(t *testing.T) {
+	userId := uuid.NewString()
+	deleteUser := commandValue(t, &pb.UserCommand{Id: "command-1", Command: &pb.UserCommand_DeleteUser{DeleteUser: &pb.DeleteUserRequest{Id: userId}}})
+	recorded, err := proto.Marshal(&pb.UserCommandResult{CommandId: "command-1", Status: status.New(codes.OK, "").Proto(),
+		ProcessedAt: timestamppb.New(time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC))})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockUserService, *mocks.MockProcessedUserCommandRepository)
+		wantCode       codes.Code
+		wantDeadLetter bool
+		wantNoResult   bool
+	}{
+		{
+			name: "command run and recorded",
+			setupMocks: func(mockService *mocks.MockUserService, mockCommandRepo *mocks.MockProcessedUserCommandRepository) {
+				mockCommandRepo.On("GetProcessedUserCommand", mock.Anything, "command-1").Return(nil, domain.ErrUserCommandNotProcessed).Once()
+				mockService.On("DeleteUser", mock.Anything, userId).Return(domain.ErrUserNotFound).Once()
+				mockCommandRepo.On("RecordProcessedUserCommand", mock.Anything, mock.MatchedBy(func(command *domain.ProcessedUserCommand) bool {
+					result := &pb.UserCommandResult{}
+					return command.Id == "command-1" && proto.Unmarshal(command.Result, result) == nil &&
+						result.Status.Code == int32(codes.NotFound) && !command.ProcessedAt.IsZero()
+				})).Return(nil).Once()
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "command processed already",
+			setupMocks: func(mockService *mocks.MockUserService, mockCommandRepo *mocks.MockProcessedUserCommandRepository) {
+				mockCommandRepo.On("GetProcessedUserCommand", mock.Anything, "command-1").
+					Return(&domain.ProcessedUserCommand{Id: "command-1", Result: recorded}, nil).Once()
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "processed commands unavailable",
+			setupMocks: func(mockService *mocks.MockUserService, mockCommandRepo *mocks.MockProcessedUserCommandRepository) {
+				mockCommandRepo.On("GetProcessedUserCommand", mock.Anything, "command-1").Return(nil, errors.New("mongo error")).Once()
+			},
+			wantDeadLetter: true,
+			wantNoResult:   true,
+		},
+		{
+			name: "server error not recorded",
+			setupMocks: func(mockService *mocks.MockUserService, mockCommandRepo *mocks.MockProcessedUserCommandRepository) {
+				mockCommandRepo.On("GetProcessedUserCommand", mock.Anything, "command-1").Return(nil, domain.ErrUserCommandNotProcessed).Once()
+				mockService.On("DeleteUser", mock.Anything, userId).Return(errors.New("mongo error")).Once()
+			},
+			wantCode:       codes.Internal,
+			wantDeadLetter: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockUserService)
+			mockCommandRepo := new(mocks.MockProcessedUserCommandRepository)
+			tt.setupMocks(mockService, mockCommandRepo)
+			handler := kafkaServer.NewUserCommandHandler(grpcServer.NewUserServiceServer(mockService), verifier, mockCommandRepo)
+
+			result, err := handler.Handle(context.TODO(), &kafka.Message{Value: deleteUser})
+
+			assert.Equal(t, tt.wantDeadLetter, err != nil)
+			if tt.wantNoResult {
+				assert.Nil(t, result)
+			} else {
+				assert.Equal(t, "command-1", result.CommandId)
+				assert.Equal(t, int32(tt.wantCode), result.Status.Code)
+			}
+			mockService.AssertExpectations(t)
+			mockCommandRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// fakeVerifier authenticates the tokens it knows
+type fakeVerifier map[string]*domain.Actor
+
+func (v fakeVerifier) VerifyToken(token string) (*domain.Actor, error) {
+	if actor, ok := v[token]; ok {
+		return actor, nil
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+var verifier = fakeVerifier{"admin-token": {ID: "admin-1", Role: domain.ActorRoleAdmin}}
+
+func commandValue(t *testing.T, command *pb.UserCommand) []byte {
+	value, err := proto.Marshal(command)
+	assert.NoError(t, err)
+	return value
+}
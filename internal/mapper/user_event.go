@@ -0,0 +1,87 @@
+// Package mapper converts the user domain to the protobuf messages shared by the APIs, the
+// Kafka events and the webhooks, for all of them to encode a user event the same way.
+package mapper
+
+import (
+	"github.com/flapenna/go-ddd-crud/internal/domain/user"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func UserEventToProto(event *domain.UserEvent) *pb.UserEvent {
+	return &pb.UserEvent{
+		Id:            event.Id,
+		UserId:        event.UserId,
+		BeforeChange:  UserToProto(event.BeforeChange),
+		AfterChange:   UserToProto(event.AfterChange),
+		OperationType: OperationTypeToProto(event.OperationType),
+		ModifiedBy:    event.ModifiedBy,
+		CorrelationId: event.CorrelationId,
+		Sequence:      event.Sequence,
+		ClusterTime:   event.ClusterTime,
+		OccurredAt:    timestamppb.New(event.OccurredAt),
+		ChangedFields: &fieldmaskpb.FieldMask{Paths: event.ChangedFields},
+		Replayed:      event.Replayed,
+	}
+}
+
+// UserToProto returns nil for a nil user, e.g. the state before a creation
+func UserToProto(user *domain.User) *pb.User {
+	if user == nil {
+		return nil
+	}
+	return &pb.User{
+		Id:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Country:   user.Country,
+		Nickname:  user.Nickname,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		Version:   user.Version,
+	}
+}
+
+func OperationTypeToProto(operationType domain.OperationType) pb.OperationType {
+	switch operationType {
+	case domain.OPERATION_CREATE:
+		return pb.OperationType_OPERATION_CREATE
+	case domain.OPERATION_UPDATE:
+		return pb.OperationType_OPERATION_UPDATE
+	case domain.OPERATION_DELETE:
+		return pb.OperationType_OPERATION_DELETE
+	default:
+		return pb.OperationType_OPERATION_UNSPECIFIED
+	}
+}
+
+func OperationTypeFromProto(operationType pb.OperationType) domain.OperationType {
+	switch operationType {
+	case pb.OperationType_OPERATION_CREATE:
+		return domain.OPERATION_CREATE
+	case pb.OperationType_OPERATION_UPDATE:
+		return domain.OPERATION_UPDATE
+	case pb.OperationType_OPERATION_DELETE:
+		return domain.OPERATION_DELETE
+	default:
+		return domain.OPERATION_UNSPECIFIED
+	}
+}
+
+// UserAuditActionToProto converts the operation recorded in the audit log, exports included
+func UserAuditActionToProto(operationType domain.OperationType) pb.UserAuditAction {
+	switch operationType {
+	case domain.OPERATION_CREATE:
+		return pb.UserAuditAction_USER_AUDIT_ACTION_CREATE
+	case domain.OPERATION_UPDATE:
+		return pb.UserAuditAction_USER_AUDIT_ACTION_UPDATE
+	case domain.OPERATION_DELETE:
+		return pb.UserAuditAction_USER_AUDIT_ACTION_DELETE
+	case domain.OPERATION_EXPORT:
+		return pb.UserAuditAction_USER_AUDIT_ACTION_EXPORT
+	default:
+		return pb.UserAuditAction_USER_AUDIT_ACTION_UNSPECIFIED
+	}
+}
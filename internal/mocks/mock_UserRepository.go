@@ -116,6 +116,65 @@ func (_c *MockUserRepository_DeleteUserById_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetUserById provides a mock function with given fields: ctx, id
+func (_m *MockUserRepository) GetUserById(ctx context.Context, id string) (*domain.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserById")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepository_GetUserById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserById'
+type MockUserRepository_GetUserById_Call struct {
+	*mock.Call
+}
+
+// GetUserById is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUserRepository_Expecter) GetUserById(ctx interface{}, id interface{}) *MockUserRepository_GetUserById_Call {
+	return &MockUserRepository_GetUserById_Call{Call: _e.mock.On("GetUserById", ctx, id)}
+}
+
+func (_c *MockUserRepository_GetUserById_Call) Run(run func(ctx context.Context, id string)) *MockUserRepository_GetUserById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserById_Call) Return(_a0 *domain.User, _a1 error) *MockUserRepository_GetUserById_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepository_GetUserById_Call) RunAndReturn(run func(context.Context, string) (*domain.User, error)) *MockUserRepository_GetUserById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListUsers provides a mock function with given fields: ctx, request
 func (_m *MockUserRepository) ListUsers(ctx context.Context, request *domain.ListUsersQueryRequest) (*domain.ListUsersQueryResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -175,6 +234,54 @@ func (_c *MockUserRepository_ListUsers_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// ScanUsers provides a mock function with given fields: ctx, query, fn
+func (_m *MockUserRepository) ScanUsers(ctx context.Context, query *domain.ScanUsersQuery, fn func(user *domain.User) error) error {
+	ret := _m.Called(ctx, query, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScanUsers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ScanUsersQuery, func(user *domain.User) error) error); ok {
+		r0 = rf(ctx, query, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_ScanUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScanUsers'
+type MockUserRepository_ScanUsers_Call struct {
+	*mock.Call
+}
+
+// ScanUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *domain.ScanUsersQuery
+//   - fn func(user *domain.User) error
+func (_e *MockUserRepository_Expecter) ScanUsers(ctx interface{}, query interface{}, fn interface{}) *MockUserRepository_ScanUsers_Call {
+	return &MockUserRepository_ScanUsers_Call{Call: _e.mock.On("ScanUsers", ctx, query, fn)}
+}
+
+func (_c *MockUserRepository_ScanUsers_Call) Run(run func(ctx context.Context, query *domain.ScanUsersQuery, fn func(user *domain.User) error)) *MockUserRepository_ScanUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ScanUsersQuery), args[2].(func(user *domain.User) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ScanUsers_Call) Return(_a0 error) *MockUserRepository_ScanUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_ScanUsers_Call) RunAndReturn(run func(context.Context, *domain.ScanUsersQuery, func(user *domain.User) error) error) *MockUserRepository_ScanUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUser provides a mock function with given fields: ctx, user
 func (_m *MockUserRepository) UpdateUser(ctx context.Context, user *domain.User) error {
 	ret := _m.Called(ctx, user)
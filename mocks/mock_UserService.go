@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "github.com/flapenna/go-ddd-crud/internal/domain/user"
 	mock "github.com/stretchr/testify/mock"
@@ -128,6 +129,467 @@ func (_c *MockUserService_DeleteUser_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// DiscardDeadLetter provides a mock function with given fields: ctx, id
+func (_m *MockUserService) DiscardDeadLetter(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiscardDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserService_DiscardDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiscardDeadLetter'
+type MockUserService_DiscardDeadLetter_Call struct {
+	*mock.Call
+}
+
+// DiscardDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUserService_Expecter) DiscardDeadLetter(ctx interface{}, id interface{}) *MockUserService_DiscardDeadLetter_Call {
+	return &MockUserService_DiscardDeadLetter_Call{Call: _e.mock.On("DiscardDeadLetter", ctx, id)}
+}
+
+func (_c *MockUserService_DiscardDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockUserService_DiscardDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_DiscardDeadLetter_Call) Return(_a0 error) *MockUserService_DiscardDeadLetter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserService_DiscardDeadLetter_Call) RunAndReturn(run func(context.Context, string) error) *MockUserService_DiscardDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportUserData provides a mock function with given fields: ctx, id
+func (_m *MockUserService) ExportUserData(ctx context.Context, id string) (*domain.UserDataExport, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 *domain.UserDataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.UserDataExport, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.UserDataExport); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserDataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_ExportUserData_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportUserData'
+type MockUserService_ExportUserData_Call struct {
+	*mock.Call
+}
+
+// ExportUserData is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUserService_Expecter) ExportUserData(ctx interface{}, id interface{}) *MockUserService_ExportUserData_Call {
+	return &MockUserService_ExportUserData_Call{Call: _e.mock.On("ExportUserData", ctx, id)}
+}
+
+func (_c *MockUserService_ExportUserData_Call) Run(run func(ctx context.Context, id string)) *MockUserService_ExportUserData_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ExportUserData_Call) Return(_a0 *domain.UserDataExport, _a1 error) *MockUserService_ExportUserData_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_ExportUserData_Call) RunAndReturn(run func(context.Context, string) (*domain.UserDataExport, error)) *MockUserService_ExportUserData_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadLetter provides a mock function with given fields: ctx, id
+func (_m *MockUserService) GetDeadLetter(ctx context.Context, id string) (*domain.DeadLetter, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetter")
+	}
+
+	var r0 *domain.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.DeadLetter, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.DeadLetter); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_GetDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadLetter'
+type MockUserService_GetDeadLetter_Call struct {
+	*mock.Call
+}
+
+// GetDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUserService_Expecter) GetDeadLetter(ctx interface{}, id interface{}) *MockUserService_GetDeadLetter_Call {
+	return &MockUserService_GetDeadLetter_Call{Call: _e.mock.On("GetDeadLetter", ctx, id)}
+}
+
+func (_c *MockUserService_GetDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockUserService_GetDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetDeadLetter_Call) Return(_a0 *domain.DeadLetter, _a1 error) *MockUserService_GetDeadLetter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_GetDeadLetter_Call) RunAndReturn(run func(context.Context, string) (*domain.DeadLetter, error)) *MockUserService_GetDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUser provides a mock function with given fields: ctx, id, asOf
+func (_m *MockUserService) GetUser(ctx context.Context, id string, asOf *time.Time) (*domain.User, error) {
+	ret := _m.Called(ctx, id, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUser")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) (*domain.User, error)); ok {
+		return rf(ctx, id, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) *domain.User); ok {
+		r0 = rf(ctx, id, asOf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time) error); ok {
+		r1 = rf(ctx, id, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_GetUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUser'
+type MockUserService_GetUser_Call struct {
+	*mock.Call
+}
+
+// GetUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - asOf *time.Time
+func (_e *MockUserService_Expecter) GetUser(ctx interface{}, id interface{}, asOf interface{}) *MockUserService_GetUser_Call {
+	return &MockUserService_GetUser_Call{Call: _e.mock.On("GetUser", ctx, id, asOf)}
+}
+
+func (_c *MockUserService_GetUser_Call) Run(run func(ctx context.Context, id string, asOf *time.Time)) *MockUserService_GetUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetUser_Call) Return(_a0 *domain.User, _a1 error) *MockUserService_GetUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_GetUser_Call) RunAndReturn(run func(context.Context, string, *time.Time) (*domain.User, error)) *MockUserService_GetUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserReplay provides a mock function with given fields: ctx, id
+func (_m *MockUserService) GetUserReplay(ctx context.Context, id string) (*domain.UserReplay, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserReplay")
+	}
+
+	var r0 *domain.UserReplay
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.UserReplay, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.UserReplay); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserReplay)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_GetUserReplay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserReplay'
+type MockUserService_GetUserReplay_Call struct {
+	*mock.Call
+}
+
+// GetUserReplay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUserService_Expecter) GetUserReplay(ctx interface{}, id interface{}) *MockUserService_GetUserReplay_Call {
+	return &MockUserService_GetUserReplay_Call{Call: _e.mock.On("GetUserReplay", ctx, id)}
+}
+
+func (_c *MockUserService_GetUserReplay_Call) Run(run func(ctx context.Context, id string)) *MockUserService_GetUserReplay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_GetUserReplay_Call) Return(_a0 *domain.UserReplay, _a1 error) *MockUserService_GetUserReplay_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_GetUserReplay_Call) RunAndReturn(run func(context.Context, string) (*domain.UserReplay, error)) *MockUserService_GetUserReplay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDeadLetters provides a mock function with given fields: ctx, request
+func (_m *MockUserService) ListDeadLetters(ctx context.Context, request *domain.ListDeadLettersQueryRequest) (*domain.ListDeadLettersQueryResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeadLetters")
+	}
+
+	var r0 *domain.ListDeadLettersQueryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListDeadLettersQueryRequest) (*domain.ListDeadLettersQueryResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListDeadLettersQueryRequest) *domain.ListDeadLettersQueryResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ListDeadLettersQueryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListDeadLettersQueryRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_ListDeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeadLetters'
+type MockUserService_ListDeadLetters_Call struct {
+	*mock.Call
+}
+
+// ListDeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *domain.ListDeadLettersQueryRequest
+func (_e *MockUserService_Expecter) ListDeadLetters(ctx interface{}, request interface{}) *MockUserService_ListDeadLetters_Call {
+	return &MockUserService_ListDeadLetters_Call{Call: _e.mock.On("ListDeadLetters", ctx, request)}
+}
+
+func (_c *MockUserService_ListDeadLetters_Call) Run(run func(ctx context.Context, request *domain.ListDeadLettersQueryRequest)) *MockUserService_ListDeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListDeadLettersQueryRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ListDeadLetters_Call) Return(_a0 *domain.ListDeadLettersQueryResponse, _a1 error) *MockUserService_ListDeadLetters_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_ListDeadLetters_Call) RunAndReturn(run func(context.Context, *domain.ListDeadLettersQueryRequest) (*domain.ListDeadLettersQueryResponse, error)) *MockUserService_ListDeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUserAuditEntries provides a mock function with given fields: ctx, request
+func (_m *MockUserService) ListUserAuditEntries(ctx context.Context, request *domain.ListUserAuditEntriesQueryRequest) (*domain.ListUserAuditEntriesQueryResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserAuditEntries")
+	}
+
+	var r0 *domain.ListUserAuditEntriesQueryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListUserAuditEntriesQueryRequest) (*domain.ListUserAuditEntriesQueryResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListUserAuditEntriesQueryRequest) *domain.ListUserAuditEntriesQueryResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ListUserAuditEntriesQueryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListUserAuditEntriesQueryRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_ListUserAuditEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUserAuditEntries'
+type MockUserService_ListUserAuditEntries_Call struct {
+	*mock.Call
+}
+
+// ListUserAuditEntries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *domain.ListUserAuditEntriesQueryRequest
+func (_e *MockUserService_Expecter) ListUserAuditEntries(ctx interface{}, request interface{}) *MockUserService_ListUserAuditEntries_Call {
+	return &MockUserService_ListUserAuditEntries_Call{Call: _e.mock.On("ListUserAuditEntries", ctx, request)}
+}
+
+func (_c *MockUserService_ListUserAuditEntries_Call) Run(run func(ctx context.Context, request *domain.ListUserAuditEntriesQueryRequest)) *MockUserService_ListUserAuditEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListUserAuditEntriesQueryRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ListUserAuditEntries_Call) Return(_a0 *domain.ListUserAuditEntriesQueryResponse, _a1 error) *MockUserService_ListUserAuditEntries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_ListUserAuditEntries_Call) RunAndReturn(run func(context.Context, *domain.ListUserAuditEntriesQueryRequest) (*domain.ListUserAuditEntriesQueryResponse, error)) *MockUserService_ListUserAuditEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListUserVersions provides a mock function with given fields: ctx, request
+func (_m *MockUserService) ListUserVersions(ctx context.Context, request *domain.ListUserVersionsQueryRequest) (*domain.ListUserVersionsQueryResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserVersions")
+	}
+
+	var r0 *domain.ListUserVersionsQueryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListUserVersionsQueryRequest) (*domain.ListUserVersionsQueryResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListUserVersionsQueryRequest) *domain.ListUserVersionsQueryResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ListUserVersionsQueryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListUserVersionsQueryRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_ListUserVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUserVersions'
+type MockUserService_ListUserVersions_Call struct {
+	*mock.Call
+}
+
+// ListUserVersions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *domain.ListUserVersionsQueryRequest
+func (_e *MockUserService_Expecter) ListUserVersions(ctx interface{}, request interface{}) *MockUserService_ListUserVersions_Call {
+	return &MockUserService_ListUserVersions_Call{Call: _e.mock.On("ListUserVersions", ctx, request)}
+}
+
+func (_c *MockUserService_ListUserVersions_Call) Run(run func(ctx context.Context, request *domain.ListUserVersionsQueryRequest)) *MockUserService_ListUserVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListUserVersionsQueryRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ListUserVersions_Call) Return(_a0 *domain.ListUserVersionsQueryResponse, _a1 error) *MockUserService_ListUserVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_ListUserVersions_Call) RunAndReturn(run func(context.Context, *domain.ListUserVersionsQueryRequest) (*domain.ListUserVersionsQueryResponse, error)) *MockUserService_ListUserVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListUsers provides a mock function with given fields: ctx, request
 func (_m *MockUserService) ListUsers(ctx context.Context, request *domain.ListUsersQueryRequest) (*domain.ListUsersQueryResponse, error) {
 	ret := _m.Called(ctx, request)
@@ -187,9 +649,288 @@ func (_c *MockUserService_ListUsers_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// RedriveDeadLetter provides a mock function with given fields: ctx, id
+func (_m *MockUserService) RedriveDeadLetter(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedriveDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserService_RedriveDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedriveDeadLetter'
+type MockUserService_RedriveDeadLetter_Call struct {
+	*mock.Call
+}
+
+// RedriveDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockUserService_Expecter) RedriveDeadLetter(ctx interface{}, id interface{}) *MockUserService_RedriveDeadLetter_Call {
+	return &MockUserService_RedriveDeadLetter_Call{Call: _e.mock.On("RedriveDeadLetter", ctx, id)}
+}
+
+func (_c *MockUserService_RedriveDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockUserService_RedriveDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_RedriveDeadLetter_Call) Return(_a0 error) *MockUserService_RedriveDeadLetter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserService_RedriveDeadLetter_Call) RunAndReturn(run func(context.Context, string) error) *MockUserService_RedriveDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevertUser provides a mock function with given fields: ctx, id, version, expectedVersion
+func (_m *MockUserService) RevertUser(ctx context.Context, id string, version int64, expectedVersion int64) (*domain.User, error) {
+	ret := _m.Called(ctx, id, version, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertUser")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) (*domain.User, error)); ok {
+		return rf(ctx, id, version, expectedVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) *domain.User); ok {
+		r0 = rf(ctx, id, version, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) error); ok {
+		r1 = rf(ctx, id, version, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_RevertUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevertUser'
+type MockUserService_RevertUser_Call struct {
+	*mock.Call
+}
+
+// RevertUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - version int64
+//   - expectedVersion int64
+func (_e *MockUserService_Expecter) RevertUser(ctx interface{}, id interface{}, version interface{}, expectedVersion interface{}) *MockUserService_RevertUser_Call {
+	return &MockUserService_RevertUser_Call{Call: _e.mock.On("RevertUser", ctx, id, version, expectedVersion)}
+}
+
+func (_c *MockUserService_RevertUser_Call) Run(run func(ctx context.Context, id string, version int64, expectedVersion int64)) *MockUserService_RevertUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUserService_RevertUser_Call) Return(_a0 *domain.User, _a1 error) *MockUserService_RevertUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_RevertUser_Call) RunAndReturn(run func(context.Context, string, int64, int64) (*domain.User, error)) *MockUserService_RevertUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartReplayingUsers provides a mock function with given fields: ctx
+func (_m *MockUserService) StartReplayingUsers(ctx context.Context) <-chan struct{} {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartReplayingUsers")
+	}
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan struct{}); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
+}
+
+// MockUserService_StartReplayingUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartReplayingUsers'
+type MockUserService_StartReplayingUsers_Call struct {
+	*mock.Call
+}
+
+// StartReplayingUsers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockUserService_Expecter) StartReplayingUsers(ctx interface{}) *MockUserService_StartReplayingUsers_Call {
+	return &MockUserService_StartReplayingUsers_Call{Call: _e.mock.On("StartReplayingUsers", ctx)}
+}
+
+func (_c *MockUserService_StartReplayingUsers_Call) Run(run func(ctx context.Context)) *MockUserService_StartReplayingUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockUserService_StartReplayingUsers_Call) Return(_a0 <-chan struct{}) *MockUserService_StartReplayingUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserService_StartReplayingUsers_Call) RunAndReturn(run func(context.Context) <-chan struct{}) *MockUserService_StartReplayingUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartUserReplay provides a mock function with given fields: ctx, request
+func (_m *MockUserService) StartUserReplay(ctx context.Context, request *domain.StartUserReplayRequest) (*domain.UserReplay, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartUserReplay")
+	}
+
+	var r0 *domain.UserReplay
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.StartUserReplayRequest) (*domain.UserReplay, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.StartUserReplayRequest) *domain.UserReplay); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserReplay)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.StartUserReplayRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_StartUserReplay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartUserReplay'
+type MockUserService_StartUserReplay_Call struct {
+	*mock.Call
+}
+
+// StartUserReplay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *domain.StartUserReplayRequest
+func (_e *MockUserService_Expecter) StartUserReplay(ctx interface{}, request interface{}) *MockUserService_StartUserReplay_Call {
+	return &MockUserService_StartUserReplay_Call{Call: _e.mock.On("StartUserReplay", ctx, request)}
+}
+
+func (_c *MockUserService_StartUserReplay_Call) Run(run func(ctx context.Context, request *domain.StartUserReplayRequest)) *MockUserService_StartUserReplay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.StartUserReplayRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserService_StartUserReplay_Call) Return(_a0 *domain.UserReplay, _a1 error) *MockUserService_StartUserReplay_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_StartUserReplay_Call) RunAndReturn(run func(context.Context, *domain.StartUserReplayRequest) (*domain.UserReplay, error)) *MockUserService_StartUserReplay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartBroadcastingUsers provides a mock function with given fields: ctx, watcher
+func (_m *MockUserService) StartBroadcastingUsers(ctx context.Context, watcher domain.UserWatcher) <-chan struct{} {
+	ret := _m.Called(ctx, watcher)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartBroadcastingUsers")
+	}
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserWatcher) <-chan struct{}); ok {
+		r0 = rf(ctx, watcher)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
+}
+
+// MockUserService_StartBroadcastingUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartBroadcastingUsers'
+type MockUserService_StartBroadcastingUsers_Call struct {
+	*mock.Call
+}
+
+// StartBroadcastingUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - watcher domain.UserWatcher
+func (_e *MockUserService_Expecter) StartBroadcastingUsers(ctx interface{}, watcher interface{}) *MockUserService_StartBroadcastingUsers_Call {
+	return &MockUserService_StartBroadcastingUsers_Call{Call: _e.mock.On("StartBroadcastingUsers", ctx, watcher)}
+}
+
+func (_c *MockUserService_StartBroadcastingUsers_Call) Run(run func(ctx context.Context, watcher domain.UserWatcher)) *MockUserService_StartBroadcastingUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.UserWatcher))
+	})
+	return _c
+}
+
+func (_c *MockUserService_StartBroadcastingUsers_Call) Return(_a0 <-chan struct{}) *MockUserService_StartBroadcastingUsers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserService_StartBroadcastingUsers_Call) RunAndReturn(run func(context.Context, domain.UserWatcher) <-chan struct{}) *MockUserService_StartBroadcastingUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StartWatchingUsers provides a mock function with given fields: ctx
-func (_m *MockUserService) StartWatchingUsers(ctx context.Context) {
-	_m.Called(ctx)
+func (_m *MockUserService) StartWatchingUsers(ctx context.Context) <-chan struct{} {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartWatchingUsers")
+	}
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan struct{}); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
 }
 
 // MockUserService_StartWatchingUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartWatchingUsers'
@@ -210,12 +951,12 @@ func (_c *MockUserService_StartWatchingUsers_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockUserService_StartWatchingUsers_Call) Return() *MockUserService_StartWatchingUsers_Call {
-	_c.Call.Return()
+func (_c *MockUserService_StartWatchingUsers_Call) Return(_a0 <-chan struct{}) *MockUserService_StartWatchingUsers_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockUserService_StartWatchingUsers_Call) RunAndReturn(run func(context.Context)) *MockUserService_StartWatchingUsers_Call {
+func (_c *MockUserService_StartWatchingUsers_Call) RunAndReturn(run func(context.Context) <-chan struct{}) *MockUserService_StartWatchingUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -279,6 +1020,65 @@ func (_c *MockUserService_UpdateUser_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// WatchUsers provides a mock function with given fields: ctx, request
+func (_m *MockUserService) WatchUsers(ctx context.Context, request *domain.WatchUsersRequest) (*domain.UserEventSubscription, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchUsers")
+	}
+
+	var r0 *domain.UserEventSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WatchUsersRequest) (*domain.UserEventSubscription, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WatchUsersRequest) *domain.UserEventSubscription); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserEventSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.WatchUsersRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserService_WatchUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchUsers'
+type MockUserService_WatchUsers_Call struct {
+	*mock.Call
+}
+
+// WatchUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *domain.WatchUsersRequest
+func (_e *MockUserService_Expecter) WatchUsers(ctx interface{}, request interface{}) *MockUserService_WatchUsers_Call {
+	return &MockUserService_WatchUsers_Call{Call: _e.mock.On("WatchUsers", ctx, request)}
+}
+
+func (_c *MockUserService_WatchUsers_Call) Run(run func(ctx context.Context, request *domain.WatchUsersRequest)) *MockUserService_WatchUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.WatchUsersRequest))
+	})
+	return _c
+}
+
+func (_c *MockUserService_WatchUsers_Call) Return(_a0 *domain.UserEventSubscription, _a1 error) *MockUserService_WatchUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserService_WatchUsers_Call) RunAndReturn(run func(context.Context, *domain.WatchUsersRequest) (*domain.UserEventSubscription, error)) *MockUserService_WatchUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockUserService creates a new instance of MockUserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserService(t interface {
@@ -59,6 +59,64 @@ func (m *HealthResponse) validate(all bool) error {
 
 	// no validation rules for Status
 
+	if all {
+		switch v := interface{}(m.GetWatcher()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, HealthResponseValidationError{
+					field:  "Watcher",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, HealthResponseValidationError{
+					field:  "Watcher",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetWatcher()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return HealthResponseValidationError{
+				field:  "Watcher",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetLeader()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, HealthResponseValidationError{
+					field:  "Leader",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, HealthResponseValidationError{
+					field:  "Leader",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetLeader()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return HealthResponseValidationError{
+				field:  "Leader",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
 	if len(errors) > 0 {
 		return HealthResponseMultiError(errors)
 	}
@@ -136,3 +194,217 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = HealthResponseValidationError{}
+
+// Validate checks the field values on WatcherStatus with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *WatcherStatus) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WatcherStatus with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in WatcherStatusMultiError, or
+// nil if none found.
+func (m *WatcherStatus) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WatcherStatus) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for State
+
+	// no validation rules for Attempts
+
+	// no validation rules for LastError
+
+	if len(errors) > 0 {
+		return WatcherStatusMultiError(errors)
+	}
+
+	return nil
+}
+
+// WatcherStatusMultiError is an error wrapping multiple validation errors
+// returned by WatcherStatus.ValidateAll() if the designated constraints
+// aren't met.
+type WatcherStatusMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WatcherStatusMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WatcherStatusMultiError) AllErrors() []error { return m }
+
+// WatcherStatusValidationError is the validation error returned by
+// WatcherStatus.Validate if the designated constraints aren't met.
+type WatcherStatusValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WatcherStatusValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WatcherStatusValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WatcherStatusValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WatcherStatusValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WatcherStatusValidationError) ErrorName() string { return "WatcherStatusValidationError" }
+
+// Error satisfies the builtin error interface
+func (e WatcherStatusValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWatcherStatus.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WatcherStatusValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WatcherStatusValidationError{}
+
+// Validate checks the field values on LeaderStatus with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LeaderStatus) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LeaderStatus with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in LeaderStatusMultiError, or
+// nil if none found.
+func (m *LeaderStatus) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LeaderStatus) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for State
+
+	// no validation rules for InstanceId
+
+	// no validation rules for FencingToken
+
+	// no validation rules for LastError
+
+	if len(errors) > 0 {
+		return LeaderStatusMultiError(errors)
+	}
+
+	return nil
+}
+
+// LeaderStatusMultiError is an error wrapping multiple validation errors
+// returned by LeaderStatus.ValidateAll() if the designated constraints aren't
+// met.
+type LeaderStatusMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LeaderStatusMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LeaderStatusMultiError) AllErrors() []error { return m }
+
+// LeaderStatusValidationError is the validation error returned by
+// LeaderStatus.Validate if the designated constraints aren't met.
+type LeaderStatusValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LeaderStatusValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LeaderStatusValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LeaderStatusValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LeaderStatusValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LeaderStatusValidationError) ErrorName() string { return "LeaderStatusValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LeaderStatusValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLeaderStatus.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LeaderStatusValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LeaderStatusValidationError{}
@@ -0,0 +1,342 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: pb/user/v1/user_command.proto
+
+package v1
+
+import (
+	_ "github.com/envoyproxy/protoc-gen-validate/validate"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// UserCommand is a user mutation sent to the user commands topic, run as the equivalent RPC
+type UserCommand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// identifies the command in its result, and is the default correlation id
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are assignable to Command:
+	//	*UserCommand_CreateUser
+	//	*UserCommand_UpdateUser
+	//	*UserCommand_DeleteUser
+	Command isUserCommand_Command `protobuf_oneof:"command"`
+}
+
+func (x *UserCommand) Reset() {
+	*x = UserCommand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_user_v1_user_command_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCommand) ProtoMessage() {}
+
+func (x *UserCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_user_v1_user_command_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserCommand.ProtoReflect.Descriptor instead.
+func (*UserCommand) Descriptor() ([]byte, []int) {
+	return file_pb_user_v1_user_command_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UserCommand) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (m *UserCommand) GetCommand() isUserCommand_Command {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (x *UserCommand) GetCreateUser() *CreateUserRequest {
+	if x, ok := x.GetCommand().(*UserCommand_CreateUser); ok {
+		return x.CreateUser
+	}
+	return nil
+}
+
+func (x *UserCommand) GetUpdateUser() *UpdateUserRequest {
+	if x, ok := x.GetCommand().(*UserCommand_UpdateUser); ok {
+		return x.UpdateUser
+	}
+	return nil
+}
+
+func (x *UserCommand) GetDeleteUser() *DeleteUserRequest {
+	if x, ok := x.GetCommand().(*UserCommand_DeleteUser); ok {
+		return x.DeleteUser
+	}
+	return nil
+}
+
+type isUserCommand_Command interface {
+	isUserCommand_Command()
+}
+
+type UserCommand_CreateUser struct {
+	CreateUser *CreateUserRequest `protobuf:"bytes,2,opt,name=create_user,json=createUser,proto3,oneof"`
+}
+
+type UserCommand_UpdateUser struct {
+	UpdateUser *UpdateUserRequest `protobuf:"bytes,3,opt,name=update_user,json=updateUser,proto3,oneof"`
+}
+
+type UserCommand_DeleteUser struct {
+	DeleteUser *DeleteUserRequest `protobuf:"bytes,4,opt,name=delete_user,json=deleteUser,proto3,oneof"`
+}
+
+func (*UserCommand_CreateUser) isUserCommand_Command() {}
+
+func (*UserCommand_UpdateUser) isUserCommand_Command() {}
+
+func (*UserCommand_DeleteUser) isUserCommand_Command() {}
+
+// UserCommandResult is published to the user command results topic for every processed command
+type UserCommandResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommandId string `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	// code and message the equivalent RPC returned, OK when the command succeeded
+	Status *status.Status `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// user created or updated by the command
+	User        *User                  `protobuf:"bytes,3,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	ProcessedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+}
+
+func (x *UserCommandResult) Reset() {
+	*x = UserCommandResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_user_v1_user_command_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserCommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserCommandResult) ProtoMessage() {}
+
+func (x *UserCommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_user_v1_user_command_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserCommandResult.ProtoReflect.Descriptor instead.
+func (*UserCommandResult) Descriptor() ([]byte, []int) {
+	return file_pb_user_v1_user_command_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserCommandResult) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *UserCommandResult) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *UserCommandResult) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserCommandResult) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
+var File_pb_user_v1_user_command_proto protoreflect.FileDescriptor
+
+var file_pb_user_v1_user_command_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x70, 0x62, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x73, 0x65,
+	0x72, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x1d, 0x70, 0x62, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xef, 0x01, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x12, 0x2b, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1b, 0xfa,
+	0x42, 0x18, 0x72, 0x16, 0x10, 0x01, 0x18, 0x40, 0x32, 0x10, 0x5e, 0x5b, 0x61, 0x2d, 0x7a, 0x41,
+	0x2d, 0x5a, 0x30, 0x2d, 0x39, 0x5f, 0x2d, 0x5d, 0x2b, 0x24, 0x52, 0x02, 0x69, 0x64, 0x12, 0x35,
+	0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f,
+	0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00,
+	0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x0b,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x42, 0x0e, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x03,
+	0xf8, 0x42, 0x01, 0x22, 0xc6, 0x01, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x05, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65,
+	0x64, 0x41, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x42, 0x20, 0x42, 0x10,
+	0x55, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x50, 0x72, 0x6f, 0x74, 0x6f,
+	0x50, 0x01, 0x5a, 0x0a, 0x70, 0x62, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_user_v1_user_command_proto_rawDescOnce sync.Once
+	file_pb_user_v1_user_command_proto_rawDescData = file_pb_user_v1_user_command_proto_rawDesc
+)
+
+func file_pb_user_v1_user_command_proto_rawDescGZIP() []byte {
+	file_pb_user_v1_user_command_proto_rawDescOnce.Do(func() {
+		file_pb_user_v1_user_command_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_user_v1_user_command_proto_rawDescData)
+	})
+	return file_pb_user_v1_user_command_proto_rawDescData
+}
+
+var file_pb_user_v1_user_command_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pb_user_v1_user_command_proto_goTypes = []any{
+	(*UserCommand)(nil),           // 0: UserCommand
+	(*UserCommandResult)(nil),     // 1: UserCommandResult
+	(*CreateUserRequest)(nil),     // 2: CreateUserRequest
+	(*UpdateUserRequest)(nil),     // 3: UpdateUserRequest
+	(*DeleteUserRequest)(nil),     // 4: DeleteUserRequest
+	(*status.Status)(nil),         // 5: google.rpc.Status
+	(*User)(nil),                  // 6: User
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_pb_user_v1_user_command_proto_depIdxs = []int32{
+	2, // 0: UserCommand.create_user:type_name -> CreateUserRequest
+	3, // 1: UserCommand.update_user:type_name -> UpdateUserRequest
+	4, // 2: UserCommand.delete_user:type_name -> DeleteUserRequest
+	5, // 3: UserCommandResult.status:type_name -> google.rpc.Status
+	6, // 4: UserCommandResult.user:type_name -> User
+	7, // 5: UserCommandResult.processed_at:type_name -> google.protobuf.Timestamp
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_pb_user_v1_user_command_proto_init() }
+func file_pb_user_v1_user_command_proto_init() {
+	if File_pb_user_v1_user_command_proto != nil {
+		return
+	}
+	file_pb_user_v1_user_service_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_pb_user_v1_user_command_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*UserCommand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_user_v1_user_command_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*UserCommandResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pb_user_v1_user_command_proto_msgTypes[0].OneofWrappers = []any{
+		(*UserCommand_CreateUser)(nil),
+		(*UserCommand_UpdateUser)(nil),
+		(*UserCommand_DeleteUser)(nil),
+	}
+	file_pb_user_v1_user_command_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pb_user_v1_user_command_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pb_user_v1_user_command_proto_goTypes,
+		DependencyIndexes: file_pb_user_v1_user_command_proto_depIdxs,
+		MessageInfos:      file_pb_user_v1_user_command_proto_msgTypes,
+	}.Build()
+	File_pb_user_v1_user_command_proto = out.File
+	file_pb_user_v1_user_command_proto_rawDesc = nil
+	file_pb_user_v1_user_command_proto_goTypes = nil
+	file_pb_user_v1_user_command_proto_depIdxs = nil
+}
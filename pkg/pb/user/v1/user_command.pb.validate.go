@@ -0,0 +1,497 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: pb/user/v1/user_command.proto
+
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on UserCommand with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *UserCommand) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UserCommand with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in UserCommandMultiError, or
+// nil if none found.
+func (m *UserCommand) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UserCommand) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if l := utf8.RuneCountInString(m.GetId()); l < 1 || l > 64 {
+		err := UserCommandValidationError{
+			field:  "Id",
+			reason: "value length must be between 1 and 64 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_UserCommand_Id_Pattern.MatchString(m.GetId()) {
+		err := UserCommandValidationError{
+			field:  "Id",
+			reason: "value does not match regex pattern \"^[a-zA-Z0-9_-]+$\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	oneofCommandPresent := false
+	switch v := m.Command.(type) {
+	case *UserCommand_CreateUser:
+		if v == nil {
+			err := UserCommandValidationError{
+				field:  "Command",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofCommandPresent = true
+
+		if all {
+			switch v := interface{}(m.GetCreateUser()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UserCommandValidationError{
+						field:  "CreateUser",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UserCommandValidationError{
+						field:  "CreateUser",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetCreateUser()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UserCommandValidationError{
+					field:  "CreateUser",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *UserCommand_UpdateUser:
+		if v == nil {
+			err := UserCommandValidationError{
+				field:  "Command",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofCommandPresent = true
+
+		if all {
+			switch v := interface{}(m.GetUpdateUser()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UserCommandValidationError{
+						field:  "UpdateUser",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UserCommandValidationError{
+						field:  "UpdateUser",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetUpdateUser()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UserCommandValidationError{
+					field:  "UpdateUser",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	case *UserCommand_DeleteUser:
+		if v == nil {
+			err := UserCommandValidationError{
+				field:  "Command",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+		oneofCommandPresent = true
+
+		if all {
+			switch v := interface{}(m.GetDeleteUser()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UserCommandValidationError{
+						field:  "DeleteUser",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UserCommandValidationError{
+						field:  "DeleteUser",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetDeleteUser()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UserCommandValidationError{
+					field:  "DeleteUser",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	default:
+		_ = v // ensures v is used
+	}
+	if !oneofCommandPresent {
+		err := UserCommandValidationError{
+			field:  "Command",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return UserCommandMultiError(errors)
+	}
+
+	return nil
+}
+
+// UserCommandMultiError is an error wrapping multiple validation errors
+// returned by UserCommand.ValidateAll() if the designated constraints aren't
+// met.
+type UserCommandMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UserCommandMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UserCommandMultiError) AllErrors() []error { return m }
+
+// UserCommandValidationError is the validation error returned by
+// UserCommand.Validate if the designated constraints aren't met.
+type UserCommandValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UserCommandValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UserCommandValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UserCommandValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UserCommandValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UserCommandValidationError) ErrorName() string { return "UserCommandValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UserCommandValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUserCommand.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UserCommandValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UserCommandValidationError{}
+
+var _UserCommand_Id_Pattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// Validate checks the field values on UserCommandResult with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *UserCommandResult) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UserCommandResult with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UserCommandResultMultiError, or nil if none found.
+func (m *UserCommandResult) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UserCommandResult) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for CommandId
+
+	if all {
+		switch v := interface{}(m.GetStatus()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UserCommandResultValidationError{
+					field:  "Status",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UserCommandResultValidationError{
+					field:  "Status",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetStatus()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UserCommandResultValidationError{
+				field:  "Status",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetProcessedAt()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, UserCommandResultValidationError{
+					field:  "ProcessedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, UserCommandResultValidationError{
+					field:  "ProcessedAt",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetProcessedAt()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return UserCommandResultValidationError{
+				field:  "ProcessedAt",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if m.User != nil {
+
+		if all {
+			switch v := interface{}(m.GetUser()).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, UserCommandResultValidationError{
+						field:  "User",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, UserCommandResultValidationError{
+						field:  "User",
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(m.GetUser()).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return UserCommandResultValidationError{
+					field:  "User",
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return UserCommandResultMultiError(errors)
+	}
+
+	return nil
+}
+
+// UserCommandResultMultiError is an error wrapping multiple validation errors
+// returned by UserCommandResult.ValidateAll() if the designated constraints
+// aren't met.
+type UserCommandResultMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UserCommandResultMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UserCommandResultMultiError) AllErrors() []error { return m }
+
+// UserCommandResultValidationError is the validation error returned by
+// UserCommandResult.Validate if the designated constraints aren't met.
+type UserCommandResultValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UserCommandResultValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UserCommandResultValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UserCommandResultValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UserCommandResultValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UserCommandResultValidationError) ErrorName() string {
+	return "UserCommandResultValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UserCommandResultValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUserCommandResult.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UserCommandResultValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UserCommandResultValidationError{}
@@ -0,0 +1,423 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: pb/user/v1/user_watch.proto
+
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// define the regex for a UUID once up-front
+var _user_watch_uuidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// Validate checks the field values on WatchUsersRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *WatchUsersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WatchUsersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WatchUsersRequestMultiError, or nil if none found.
+func (m *WatchUsersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WatchUsersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetUserIds()) > 100 {
+		err := WatchUsersRequestValidationError{
+			field:  "UserIds",
+			reason: "value must contain no more than 100 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetUserIds() {
+		_, _ = idx, item
+
+		if err := m._validateUuid(item); err != nil {
+			err = WatchUsersRequestValidationError{
+				field:  fmt.Sprintf("UserIds[%v]", idx),
+				reason: "value must be a valid UUID",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	for idx, item := range m.GetOperationTypes() {
+		_, _ = idx, item
+
+		if _, ok := _WatchUsersRequest_OperationTypes_NotInLookup[item]; ok {
+			err := WatchUsersRequestValidationError{
+				field:  fmt.Sprintf("OperationTypes[%v]", idx),
+				reason: "value must not be in list [0]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if _, ok := OperationType_name[int32(item)]; !ok {
+			err := WatchUsersRequestValidationError{
+				field:  fmt.Sprintf("OperationTypes[%v]", idx),
+				reason: "value must be one of the defined enum values",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if utf8.RuneCountInString(m.GetEpoch()) > 64 {
+		err := WatchUsersRequestValidationError{
+			field:  "Epoch",
+			reason: "value length must be at most 64 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	switch v := m.ResumeFrom.(type) {
+	case *WatchUsersRequest_AfterEventId:
+		if v == nil {
+			err := WatchUsersRequestValidationError{
+				field:  "ResumeFrom",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if l := utf8.RuneCountInString(m.GetAfterEventId()); l < 1 || l > 64 {
+			err := WatchUsersRequestValidationError{
+				field:  "AfterEventId",
+				reason: "value length must be between 1 and 64 runes, inclusive",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	case *WatchUsersRequest_AfterSequence:
+		if v == nil {
+			err := WatchUsersRequestValidationError{
+				field:  "ResumeFrom",
+				reason: "oneof value cannot be a typed-nil",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+		if m.GetAfterSequence() <= 0 {
+			err := WatchUsersRequestValidationError{
+				field:  "AfterSequence",
+				reason: "value must be greater than 0",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	default:
+		_ = v // ensures v is used
+	}
+
+	if m.Country != nil {
+
+		if !_WatchUsersRequest_Country_Pattern.MatchString(m.GetCountry()) {
+			err := WatchUsersRequestValidationError{
+				field:  "Country",
+				reason: "value does not match regex pattern \"^[A-Z]{2}$\"",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return WatchUsersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+func (m *WatchUsersRequest) _validateUuid(uuid string) error {
+	if matched := _user_watch_uuidPattern.MatchString(uuid); !matched {
+		return errors.New("invalid uuid format")
+	}
+
+	return nil
+}
+
+// WatchUsersRequestMultiError is an error wrapping multiple validation errors
+// returned by WatchUsersRequest.ValidateAll() if the designated constraints
+// aren't met.
+type WatchUsersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WatchUsersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WatchUsersRequestMultiError) AllErrors() []error { return m }
+
+// WatchUsersRequestValidationError is the validation error returned by
+// WatchUsersRequest.Validate if the designated constraints aren't met.
+type WatchUsersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WatchUsersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WatchUsersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WatchUsersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WatchUsersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WatchUsersRequestValidationError) ErrorName() string {
+	return "WatchUsersRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WatchUsersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWatchUsersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WatchUsersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WatchUsersRequestValidationError{}
+
+var _WatchUsersRequest_OperationTypes_NotInLookup = map[OperationType]struct{}{
+	0: {},
+}
+
+var _WatchUsersRequest_Country_Pattern = regexp.MustCompile("^[A-Z]{2}$")
+
+// Validate checks the field values on WatchUsersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no
+// violations.
+func (m *WatchUsersResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on WatchUsersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// WatchUsersResponseMultiError, or nil if none found.
+func (m *WatchUsersResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *WatchUsersResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Sequence
+
+	if all {
+		switch v := interface{}(m.GetEvent()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, WatchUsersResponseValidationError{
+					field:  "Event",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, WatchUsersResponseValidationError{
+					field:  "Event",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetEvent()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return WatchUsersResponseValidationError{
+				field:  "Event",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Epoch
+
+	if len(errors) > 0 {
+		return WatchUsersResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// WatchUsersResponseMultiError is an error wrapping multiple validation errors
+// returned by WatchUsersResponse.ValidateAll() if the designated constraints
+// aren't met.
+type WatchUsersResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m WatchUsersResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m WatchUsersResponseMultiError) AllErrors() []error { return m }
+
+// WatchUsersResponseValidationError is the validation error returned by
+// WatchUsersResponse.Validate if the designated constraints aren't met.
+type WatchUsersResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e WatchUsersResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e WatchUsersResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e WatchUsersResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e WatchUsersResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e WatchUsersResponseValidationError) ErrorName() string {
+	return "WatchUsersResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e WatchUsersResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sWatchUsersResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = WatchUsersResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = WatchUsersResponseValidationError{}
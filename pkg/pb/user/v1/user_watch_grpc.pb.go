@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: pb/user/v1/user_watch.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	UserWatchService_WatchUsers_FullMethodName = "/UserWatchService/WatchUsers"
+)
+
+// UserWatchServiceClient is the client API for UserWatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SERVICES DEFINITION
+type UserWatchServiceClient interface {
+	// streams the published user events, as they are sent to the user event topic
+	WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (UserWatchService_WatchUsersClient, error)
+}
+
+type userWatchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserWatchServiceClient(cc grpc.ClientConnInterface) UserWatchServiceClient {
+	return &userWatchServiceClient{cc}
+}
+
+func (c *userWatchServiceClient) WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (UserWatchService_WatchUsersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UserWatchService_ServiceDesc.Streams[0], UserWatchService_WatchUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userWatchServiceWatchUsersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UserWatchService_WatchUsersClient interface {
+	Recv() (*WatchUsersResponse, error)
+	grpc.ClientStream
+}
+
+type userWatchServiceWatchUsersClient struct {
+	grpc.ClientStream
+}
+
+func (x *userWatchServiceWatchUsersClient) Recv() (*WatchUsersResponse, error) {
+	m := new(WatchUsersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UserWatchServiceServer is the server API for UserWatchService service.
+// All implementations must embed UnimplementedUserWatchServiceServer
+// for forward compatibility
+//
+// SERVICES DEFINITION
+type UserWatchServiceServer interface {
+	// streams the published user events, as they are sent to the user event topic
+	WatchUsers(*WatchUsersRequest, UserWatchService_WatchUsersServer) error
+	mustEmbedUnimplementedUserWatchServiceServer()
+}
+
+// UnimplementedUserWatchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUserWatchServiceServer struct {
+}
+
+func (UnimplementedUserWatchServiceServer) WatchUsers(*WatchUsersRequest, UserWatchService_WatchUsersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUsers not implemented")
+}
+func (UnimplementedUserWatchServiceServer) mustEmbedUnimplementedUserWatchServiceServer() {}
+
+// UnsafeUserWatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserWatchServiceServer will
+// result in compilation errors.
+type UnsafeUserWatchServiceServer interface {
+	mustEmbedUnimplementedUserWatchServiceServer()
+}
+
+func RegisterUserWatchServiceServer(s grpc.ServiceRegistrar, srv UserWatchServiceServer) {
+	s.RegisterService(&UserWatchService_ServiceDesc, srv)
+}
+
+func _UserWatchService_WatchUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UserWatchServiceServer).WatchUsers(m, &userWatchServiceWatchUsersServer{ServerStream: stream})
+}
+
+type UserWatchService_WatchUsersServer interface {
+	Send(*WatchUsersResponse) error
+	grpc.ServerStream
+}
+
+type userWatchServiceWatchUsersServer struct {
+	grpc.ServerStream
+}
+
+func (x *userWatchServiceWatchUsersServer) Send(m *WatchUsersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// UserWatchService_ServiceDesc is the grpc.ServiceDesc for UserWatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserWatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "UserWatchService",
+	HandlerType: (*UserWatchServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUsers",
+			Handler:       _UserWatchService_WatchUsers_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pb/user/v1/user_watch.proto",
+}
@@ -0,0 +1,147 @@
+// Package userevents consumes the user events published by the user service, e.g. from the
+// go-ddd-crud_user-event topic, with typed handlers, deduplication, retries and dead letters.
+//
+//	source, err := userevents.NewKafkaSource(kafka.ConfigMap{
+//		"bootstrap.servers": "localhost:9092",
+//		"group.id":          "my-service",
+//		"auto.offset.reset": "earliest",
+//	}, "go-ddd-crud_user-event")
+//	consumer := userevents.NewConsumer(source, userevents.ConsumerOptions{})
+//	consumer.OnCreate(func(ctx context.Context, event *pb.UserEvent) error { ... })
+//	err = consumer.Run(ctx)
+package userevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"time"
+)
+
+const (
+	defaultMaxAttempts       = 3
+	defaultRetryBackoff      = 100 * time.Millisecond
+	defaultDeduplicationSize = 10000
+)
+
+// Handler handles a user event. An error makes the event retried, then dead lettered.
+type Handler func(ctx context.Context, event *pb.UserEvent) error
+
+// ConsumerOptions customizes the handling of the events
+type ConsumerOptions struct {
+	// MaxAttempts is the number of times a handler is called for an event, 3 by default
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry, doubled at every retry, 100ms by default
+	RetryBackoff time.Duration
+	// DeadLetters receives the events failing every attempt and the messages which cannot be
+	// decoded. Without it, Run returns the error and the message is not committed.
+	DeadLetters DeadLetterSink
+	// Deduplicator skips the events already handled, by id. It remembers the last 10000 events
+	// in memory by default, which are forgotten on restart.
+	Deduplicator Deduplicator
+}
+
+// Consumer dispatches the user events of a source to the handler of their operation. A message
+// is committed once handled, skipped or dead lettered: events are handled at least once.
+type Consumer struct {
+	source   Source
+	opts     ConsumerOptions
+	handlers map[pb.OperationType]Handler
+}
+
+// NewConsumer creates a consumer of the source. The handlers are registered before Run.
+func NewConsumer(source Source, opts ConsumerOptions) *Consumer {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultRetryBackoff
+	}
+	if opts.Deduplicator == nil {
+		opts.Deduplicator = NewMemoryDeduplicator(defaultDeduplicationSize)
+	}
+	return &Consumer{
+		source:   source,
+		opts:     opts,
+		handlers: make(map[pb.OperationType]Handler),
+	}
+}
+
+// OnCreate handles the user creations, replayed creations included
+func (c *Consumer) OnCreate(handler Handler) {
+	c.handlers[pb.OperationType_OPERATION_CREATE] = handler
+}
+
+// OnUpdate handles the user updates
+func (c *Consumer) OnUpdate(handler Handler) {
+	c.handlers[pb.OperationType_OPERATION_UPDATE] = handler
+}
+
+// OnDelete handles the user deletions
+func (c *Consumer) OnDelete(handler Handler) {
+	c.handlers[pb.OperationType_OPERATION_DELETE] = handler
+}
+
+// Run handles the events until ctx is done or the source is closed, which return nil. It
+// returns the error of the source, or of an event which could not be dead lettered.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		msg, err := c.source.Read(ctx)
+		if errors.Is(err, ErrSourceClosed) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read user event: %w", err)
+		}
+
+		if err := c.handle(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := c.source.Commit(ctx, msg); err != nil {
+			return fmt.Errorf("failed to commit user event at offset %d of partition %d: %w", msg.Offset, msg.Partition, err)
+		}
+	}
+}
+
+func (c *Consumer) handle(ctx context.Context, msg *Message) error {
+	event, err := Decode(msg.Headers[ContentTypeHeader], msg.Value)
+	if err != nil {
+		return c.deadLetter(ctx, msg, err)
+	}
+	handler, ok := c.handlers[event.OperationType]
+	if !ok || c.opts.Deduplicator.Seen(event.Id) {
+		return nil
+	}
+
+	backoff := c.opts.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err = handler(ctx, event)
+		if err == nil {
+			c.opts.Deduplicator.Mark(event.Id)
+			return nil
+		}
+		if attempt >= c.opts.MaxAttempts {
+			return c.deadLetter(ctx, msg, fmt.Errorf("user event %s failed after %d attempts: %w", event.Id, attempt, err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, msg *Message, cause error) error {
+	if c.opts.DeadLetters == nil {
+		return cause
+	}
+	if err := c.opts.DeadLetters.Send(ctx, msg, cause); err != nil {
+		return fmt.Errorf("failed to dead letter user event at offset %d of partition %d: %w", msg.Offset, msg.Partition, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build unit
+
+package userevents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/flapenna/go-ddd-crud/pkg/userevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer_Run(t *testing.T) {
+	created := &pb.UserEvent{Id: "event-1", UserId: "user-1", OperationType: pb.OperationType_OPERATION_CREATE}
+	updated := &pb.UserEvent{Id: "event-2", UserId: "user-1", OperationType: pb.OperationType_OPERATION_UPDATE}
+	deleted := &pb.UserEvent{Id: "event-3", UserId: "user-1", OperationType: pb.OperationType_OPERATION_DELETE}
+
+	tests := []struct {
+		name            string
+		events          []*pb.UserEvent
+		undecodable     bool
+		failures        map[string]int
+		withDeadLetters bool
+		wantHandled     []string
+		wantDeadLetters int
+		wantCommitted   int64
+		wantErr         bool
+	}{
+		{
+			name:          "dispatched by operation",
+			events:        []*pb.UserEvent{created, updated, deleted},
+			wantHandled:   []string{"create:event-1", "update:event-2", "delete:event-3"},
+			wantCommitted: 2,
+		},
+		{
+			name:          "duplicates skipped",
+			events:        []*pb.UserEvent{created, created, updated},
+			wantHandled:   []string{"create:event-1", "update:event-2"},
+			wantCommitted: 2,
+		},
+		{
+			name:          "retried until handled",
+			events:        []*pb.UserEvent{created},
+			failures:      map[string]int{"event-1": 2},
+			wantHandled:   []string{"create:event-1", "create:event-1", "create:event-1"},
+			wantCommitted: 0,
+		},
+		{
+			name:            "dead lettered after every attempt",
+			events:          []*pb.UserEvent{created, updated},
+			failures:        map[string]int{"event-1": 3},
+			withDeadLetters: true,
+			wantHandled:     []string{"create:event-1", "create:event-1", "create:event-1", "update:event-2"},
+			wantDeadLetters: 1,
+			wantCommitted:   1,
+		},
+		{
+			name:            "undecodable message dead lettered",
+			events:          []*pb.UserEvent{updated},
+			undecodable:     true,
+			withDeadLetters: true,
+			wantHandled:     []string{"update:event-2"},
+			wantDeadLetters: 1,
+			wantCommitted:   1,
+		},
+		{
+			name:          "failure without dead letters stops before the commit",
+			events:        []*pb.UserEvent{created, updated},
+			failures:      map[string]int{"event-1": 3},
+			wantHandled:   []string{"create:event-1", "create:event-1", "create:event-1"},
+			wantCommitted: -1,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := userevents.NewMemorySource()
+			if tt.undecodable {
+				source.AddMessage(&userevents.Message{Value: []byte("not an event"),
+					Headers: map[string]string{userevents.ContentTypeHeader: userevents.ContentTypeJSON}})
+			}
+			require.NoError(t, source.Add(tt.events...))
+			require.NoError(t, source.Close())
+
+			deadLetters := userevents.NewMemoryDeadLetterSink()
+			opts := userevents.ConsumerOptions{RetryBackoff: time.Millisecond}
+			if tt.withDeadLetters {
+				opts.DeadLetters = deadLetters
+			}
+			consumer := userevents.NewConsumer(source, opts)
+			handled := make([]string, 0)
+			handler := func(operation string) userevents.Handler {
+				return func(ctx context.Context, event *pb.UserEvent) error {
+					handled = append(handled, operation+":"+event.Id)
+					if tt.failures[event.Id] > 0 {
+						tt.failures[event.Id]--
+						return errors.New("handler error")
+					}
+					return nil
+				}
+			}
+			consumer.OnCreate(handler("create"))
+			consumer.OnUpdate(handler("update"))
+			consumer.OnDelete(handler("delete"))
+
+			err := consumer.Run(context.TODO())
+
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantHandled, handled)
+			assert.Len(t, deadLetters.DeadLetters(), tt.wantDeadLetters)
+			assert.Equal(t, tt.wantCommitted, source.Committed())
+		})
+	}
+}
+
+func TestConsumer_RunStopsWithContext(t *testing.T) {
+	source := userevents.NewMemorySource()
+	consumer := userevents.NewConsumer(source, userevents.ConsumerOptions{})
+	handled := make(chan string, 1)
+	consumer.OnUpdate(func(ctx context.Context, event *pb.UserEvent) error {
+		handled <- event.Id
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.Run(ctx)
+	}()
+
+	// Events added to a running consumer are handled, and events without a handler are committed
+	require.NoError(t, source.Add(&pb.UserEvent{Id: "event-1", OperationType: pb.OperationType_OPERATION_CREATE},
+		&pb.UserEvent{Id: "event-2", OperationType: pb.OperationType_OPERATION_UPDATE}))
+	select {
+	case id := <-handled:
+		assert.Equal(t, "event-2", id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not handled")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, int64(1), source.Committed())
+}
+
+func TestMemoryDeduplicator(t *testing.T) {
+	deduplicator := userevents.NewMemoryDeduplicator(2)
+	deduplicator.Mark("event-1")
+	deduplicator.Mark("event-2")
+	assert.True(t, deduplicator.Seen("event-1"))
+
+	// The oldest id is forgotten beyond the capacity
+	deduplicator.Mark("event-3")
+	assert.False(t, deduplicator.Seen("event-1"))
+	assert.True(t, deduplicator.Seen("event-2"))
+	assert.True(t, deduplicator.Seen("event-3"))
+}
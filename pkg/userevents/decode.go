@@ -0,0 +1,65 @@
+package userevents
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Content types of the user event serializations, sent in the content-type header
+const (
+	ContentTypeProtobuf       = "application/x-protobuf"
+	ContentTypeJSON           = "application/json"
+	ContentTypeSchemaRegistry = "application/vnd.confluent.protobuf"
+
+	ContentTypeHeader = "content-type"
+)
+
+const schemaRegistryMagicByte = 0x0
+
+// Decode deserializes a user event of the given content type, raw protobuf when it is empty
+func Decode(contentType string, value []byte) (*pb.UserEvent, error) {
+	event := &pb.UserEvent{}
+	var err error
+	switch contentType {
+	case ContentTypeProtobuf, "":
+		err = proto.Unmarshal(value, event)
+	case ContentTypeJSON:
+		err = protojson.Unmarshal(value, event)
+	case ContentTypeSchemaRegistry:
+		value, err = stripSchemaRegistryHeader(value)
+		if err == nil {
+			err = proto.Unmarshal(value, event)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode user event: %w", err)
+	}
+	return event, nil
+}
+
+// stripSchemaRegistryHeader removes the magic byte, the schema id and the message indexes
+func stripSchemaRegistryHeader(value []byte) ([]byte, error) {
+	if len(value) < 6 || value[0] != schemaRegistryMagicByte {
+		return nil, errors.New("invalid schema registry wire format")
+	}
+	value = value[5:]
+	count, n := binary.Varint(value)
+	if n <= 0 {
+		return nil, errors.New("invalid schema registry message indexes")
+	}
+	value = value[n:]
+	for i := int64(0); i < count; i++ {
+		_, n := binary.Varint(value)
+		if n <= 0 {
+			return nil, errors.New("invalid schema registry message indexes")
+		}
+		value = value[n:]
+	}
+	return value, nil
+}
@@ -0,0 +1,52 @@
+//go:build unit
+
+package userevents_test
+
+import (
+	"testing"
+
+	kafkaClient "github.com/flapenna/go-ddd-crud/internal/infrastructure/kafka"
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"github.com/flapenna/go-ddd-crud/pkg/userevents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type staticSchemaRegistry int
+
+func (r staticSchemaRegistry) LatestSchemaId(string) (int, error) {
+	return int(r), nil
+}
+
+func TestDecode(t *testing.T) {
+	event := &pb.UserEvent{
+		Id:            "event-1",
+		UserId:        "user-123",
+		AfterChange:   &pb.User{Id: "user-123", FirstName: "Federico"},
+		OperationType: pb.OperationType_OPERATION_CREATE,
+		Sequence:      1,
+	}
+
+	// Every serialization of the user service can be decoded
+	encoders := []kafkaClient.UserEventEncoder{
+		kafkaClient.NewProtobufEncoder(),
+		kafkaClient.NewJSONEncoder(),
+		kafkaClient.NewSchemaRegistryEncoder(staticSchemaRegistry(300), "go-ddd-crud_user-event-value"),
+	}
+	for _, encoder := range encoders {
+		t.Run(encoder.ContentType(), func(t *testing.T) {
+			value, err := encoder.Encode(event)
+			require.NoError(t, err)
+
+			decoded, err := userevents.Decode(encoder.ContentType(), value)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(event, decoded))
+		})
+	}
+
+	_, err := userevents.Decode("application/avro", []byte{})
+	assert.Error(t, err)
+	_, err = userevents.Decode(userevents.ContentTypeSchemaRegistry, []byte{1, 2})
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package userevents
+
+import "sync"
+
+// Deduplicator remembers the ids of the handled events, to skip them when they are delivered
+// again, e.g. republished by a resumed replay. Skipping the events redelivered after a restart
+// of the consumer takes an implementation backed by a persistent store, such as the database the
+// handlers write to: MemoryDeduplicator forgets every id when the process stops.
+type Deduplicator interface {
+	Seen(id string) bool
+	Mark(id string)
+}
+
+// MemoryDeduplicator remembers the ids of the last handled events, for the lifetime of the process
+type MemoryDeduplicator struct {
+	mu   sync.Mutex
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+// NewMemoryDeduplicator creates a deduplicator forgetting the oldest ids beyond capacity
+func NewMemoryDeduplicator(capacity int) *MemoryDeduplicator {
+	return &MemoryDeduplicator{
+		ids:  make(map[string]struct{}, capacity),
+		ring: make([]string, capacity),
+	}
+}
+
+func (d *MemoryDeduplicator) Seen(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, seen := d.ids[id]
+	return seen
+}
+
+func (d *MemoryDeduplicator) Mark(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, seen := d.ids[id]; seen || len(d.ring) == 0 {
+		return
+	}
+	if oldest := d.ring[d.next]; oldest != "" {
+		delete(d.ids, oldest)
+	}
+	d.ring[d.next] = id
+	d.ids[id] = struct{}{}
+	d.next = (d.next + 1) % len(d.ring)
+}
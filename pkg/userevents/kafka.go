@@ -0,0 +1,138 @@
+package userevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"strconv"
+	"time"
+)
+
+// Headers added to the dead lettered messages, next to their original headers
+const (
+	DeadLetterErrorHeader     = "dlq_error"
+	DeadLetterTopicHeader     = "dlq_source_topic"
+	DeadLetterPartitionHeader = "dlq_source_partition"
+	DeadLetterOffsetHeader    = "dlq_source_offset"
+)
+
+const readPollTimeout = 100 * time.Millisecond
+
+// KafkaSource reads the user event topic with a consumer group
+type KafkaSource struct {
+	consumer *kafka.Consumer
+}
+
+// NewKafkaSource creates a consumer subscribed to topic. The offsets are only committed by
+// Commit, enable.auto.commit is always disabled.
+func NewKafkaSource(config kafka.ConfigMap, topic string) (*KafkaSource, error) {
+	consumerConfig := make(kafka.ConfigMap, len(config)+1)
+	for key, value := range config {
+		consumerConfig[key] = value
+	}
+	consumerConfig["enable.auto.commit"] = false
+
+	consumer, err := kafka.NewConsumer(&consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := consumer.Subscribe(topic, nil); err != nil {
+		consumer.Close()
+		return nil, err
+	}
+	return &KafkaSource{consumer: consumer}, nil
+}
+
+func (s *KafkaSource) Read(ctx context.Context) (*Message, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		msg, err := s.consumer.ReadMessage(readPollTimeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if errors.As(err, &kafkaErr) && (kafkaErr.IsTimeout() || !kafkaErr.IsFatal()) {
+				continue
+			}
+			return nil, err
+		}
+
+		headers := make(map[string]string, len(msg.Headers))
+		for _, header := range msg.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+		return &Message{
+			Topic:     *msg.TopicPartition.Topic,
+			Partition: msg.TopicPartition.Partition,
+			Offset:    int64(msg.TopicPartition.Offset),
+			Key:       msg.Key,
+			Value:     msg.Value,
+			Headers:   headers,
+		}, nil
+	}
+}
+
+func (s *KafkaSource) Commit(_ context.Context, msg *Message) error {
+	_, err := s.consumer.CommitOffsets([]kafka.TopicPartition{{
+		Topic:     &msg.Topic,
+		Partition: msg.Partition,
+		// The committed offset is the next one to read
+		Offset: kafka.Offset(msg.Offset + 1),
+	}})
+	return err
+}
+
+func (s *KafkaSource) Close() error {
+	return s.consumer.Close()
+}
+
+// KafkaDeadLetterSink sends the dead letters to a topic, with their original key, value and headers
+type KafkaDeadLetterSink struct {
+	producer *kafka.Producer
+	topic    string
+}
+
+// NewKafkaDeadLetterSink creates a sink producing to topic. The producer is only used with its
+// own delivery channels, so it can be shared.
+func NewKafkaDeadLetterSink(producer *kafka.Producer, topic string) *KafkaDeadLetterSink {
+	return &KafkaDeadLetterSink{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// Send blocks until the dead letter is delivered
+func (s *KafkaDeadLetterSink) Send(ctx context.Context, msg *Message, cause error) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers)+4)
+	for key, value := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	headers = append(headers,
+		kafka.Header{Key: DeadLetterErrorHeader, Value: []byte(cause.Error())},
+		kafka.Header{Key: DeadLetterTopicHeader, Value: []byte(msg.Topic)},
+		kafka.Header{Key: DeadLetterPartitionHeader, Value: []byte(strconv.Itoa(int(msg.Partition)))},
+		kafka.Header{Key: DeadLetterOffsetHeader, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+	)
+
+	delivered := make(chan kafka.Event, 1)
+	err := s.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &s.topic, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}, delivered)
+	if err != nil {
+		return fmt.Errorf("failed to dead letter message: %w", err)
+	}
+	select {
+	case e := <-delivered:
+		report, ok := e.(*kafka.Message)
+		if !ok {
+			return errors.New("unexpected delivery report")
+		}
+		return report.TopicPartition.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+package userevents
+
+import (
+	"context"
+	"sync"
+
+	pb "github.com/flapenna/go-ddd-crud/pkg/pb/user/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryTopic is the topic of the messages added to a MemorySource
+const MemoryTopic = "memory"
+
+// MemorySource is an in-memory, single partition Source for unit tests
+type MemorySource struct {
+	mu         sync.Mutex
+	pending    []*Message
+	nextOffset int64
+	committed  int64
+	closed     bool
+	ready      chan struct{}
+}
+
+func NewMemorySource() *MemorySource {
+	return &MemorySource{
+		committed: -1,
+		ready:     make(chan struct{}, 1),
+	}
+}
+
+// Add appends the event, serialized as protobuf and keyed by its user id as by the user service
+func (s *MemorySource) Add(events ...*pb.UserEvent) error {
+	for _, event := range events {
+		value, err := proto.Marshal(event)
+		if err != nil {
+			return err
+		}
+		s.AddMessage(&Message{
+			Key:     []byte(event.UserId),
+			Value:   value,
+			Headers: map[string]string{ContentTypeHeader: ContentTypeProtobuf},
+		})
+	}
+	return nil
+}
+
+// AddMessage appends the message, its topic, partition and offset being assigned by the source
+func (s *MemorySource) AddMessage(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg.Topic = MemoryTopic
+	msg.Partition = 0
+	msg.Offset = s.nextOffset
+	s.nextOffset++
+	s.pending = append(s.pending, msg)
+	s.signal()
+}
+
+// Read returns ErrSourceClosed once the source is closed and every message was read
+func (s *MemorySource) Read(ctx context.Context) (*Message, error) {
+	for {
+		s.mu.Lock()
+		if len(s.pending) > 0 {
+			msg := s.pending[0]
+			s.pending = s.pending[1:]
+			s.mu.Unlock()
+			return msg, nil
+		}
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return nil, ErrSourceClosed
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.ready:
+		}
+	}
+}
+
+func (s *MemorySource) Commit(_ context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if msg.Offset > s.committed {
+		s.committed = msg.Offset
+	}
+	return nil
+}
+
+// Committed returns the offset of the last committed message, -1 when none was
+func (s *MemorySource) Committed() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.committed
+}
+
+// Close lets the readers drain the remaining messages
+func (s *MemorySource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.signal()
+	return nil
+}
+
+func (s *MemorySource) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// MemoryDeadLetterSink keeps the dead letters in memory, for unit tests
+type MemoryDeadLetterSink struct {
+	mu          sync.Mutex
+	deadLetters []*DeadLetter
+}
+
+// DeadLetter is a message kept by a MemoryDeadLetterSink
+type DeadLetter struct {
+	Message *Message
+	Cause   error
+}
+
+func NewMemoryDeadLetterSink() *MemoryDeadLetterSink {
+	return &MemoryDeadLetterSink{}
+}
+
+func (s *MemoryDeadLetterSink) Send(_ context.Context, msg *Message, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = append(s.deadLetters, &DeadLetter{Message: msg, Cause: cause})
+	return nil
+}
+
+func (s *MemoryDeadLetterSink) DeadLetters() []*DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*DeadLetter(nil), s.deadLetters...)
+}
@@ -0,0 +1,33 @@
+package userevents
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSourceClosed is returned by Read once a closed source has no message left
+var ErrSourceClosed = errors.New("user event source closed")
+
+// Message is a record of the user event topic
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+}
+
+// Source reads the messages of the user event topic
+type Source interface {
+	// Read blocks until the next message is available or ctx is done
+	Read(ctx context.Context) (*Message, error)
+	// Commit acknowledges the message, and the previous messages of its partition
+	Commit(ctx context.Context, msg *Message) error
+	Close() error
+}
+
+// DeadLetterSink keeps the messages which could not be handled
+type DeadLetterSink interface {
+	Send(ctx context.Context, msg *Message, cause error) error
+}